@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -18,11 +20,19 @@ import (
 )
 
 func Change(desc urtypes.OutputDescriptor, index uint32) (string, error) {
-	return address(desc, index, true)
+	d, err := NewDeriver(desc)
+	if err != nil {
+		return "", err
+	}
+	return d.Address(index, true)
 }
 
 func Receive(desc urtypes.OutputDescriptor, index uint32) (string, error) {
-	return address(desc, index, false)
+	d, err := NewDeriver(desc)
+	if err != nil {
+		return "", err
+	}
+	return d.Address(index, false)
 }
 
 func Supported(desc urtypes.OutputDescriptor) bool {
@@ -32,14 +42,111 @@ func Supported(desc urtypes.OutputDescriptor) bool {
 
 var errUnsupported = errors.New("unsupported descriptor")
 
-func address(desc urtypes.OutputDescriptor, index uint32, change bool) (string, error) {
+// Deriver derives the addresses of a descriptor incrementally. The
+// extended keys of the receive and change branches are derived
+// once, leaving a single derivation per key for every address.
+type Deriver struct {
+	desc urtypes.OutputDescriptor
+	// branches holds the branch keys of every descriptor
+	// key, indexed by change.
+	branches [2][]branchKey
+}
+
+// branchKey is a key derived up to its wildcard element.
+type branchKey struct {
+	xpub *hdkeychain.ExtendedKey
+	// wildcard reports whether the path contains a wildcard.
+	wildcard bool
+	// suffix is the path following the wildcard.
+	suffix []uint32
+}
+
+// Match is the location of an address in a descriptor.
+type Match struct {
+	Index  uint32
+	Change bool
+}
+
+func NewDeriver(desc urtypes.OutputDescriptor) (*Deriver, error) {
+	d := &Deriver{desc: desc}
+	for i := range d.branches {
+		for _, k := range desc.Keys {
+			b, err := deriveBranch(k, i == 1)
+			if err != nil {
+				return nil, fmt.Errorf("address: %w", err)
+			}
+			d.branches[i] = append(d.branches[i], b)
+		}
+	}
+	return d, nil
+}
+
+// Address derives the receive or change address at index.
+func (d *Deriver) Address(index uint32, change bool) (string, error) {
+	branch := 0
+	if change {
+		branch = 1
+	}
+	return address(d.desc, d.branches[branch], index)
+}
+
+// Normalize returns the canonical encoding of addr, which may
+// be a BIP21 URI. It returns an error if addr is not a valid
+// address for the network of the descriptor.
+func (d *Deriver) Normalize(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if len(addr) > len(bip21Scheme) && strings.EqualFold(addr[:len(bip21Scheme)], bip21Scheme) {
+		addr = addr[len(bip21Scheme):]
+		if i := strings.IndexByte(addr, '?'); i != -1 {
+			addr = addr[:i]
+		}
+	}
+	if len(d.desc.Keys) == 0 {
+		return "", fmt.Errorf("address: descriptor: %w", errUnsupported)
+	}
+	network := d.desc.Keys[0].Network
+	a, err := btcutil.DecodeAddress(addr, network)
+	if err != nil {
+		return "", fmt.Errorf("address: %q: %w", addr, err)
+	}
+	if !a.IsForNet(network) {
+		return "", fmt.Errorf("address: %q: not a %s address", addr, network.Name)
+	}
+	return a.String(), nil
+}
+
+const bip21Scheme = "bitcoin:"
+
+// Search derives the receive and change addresses from index
+// start up to, but not including, end and returns the location
+// of addr, if found.
+func (d *Deriver) Search(addr string, start, end uint32) (Match, bool, error) {
+	addr, err := d.Normalize(addr)
+	if err != nil {
+		return Match{}, false, err
+	}
+	for i := start; i < end; i++ {
+		for _, change := range []bool{false, true} {
+			a, err := d.Address(i, change)
+			if err != nil {
+				return Match{}, false, err
+			}
+			if a == addr {
+				return Match{Index: i, Change: change}, true, nil
+			}
+		}
+	}
+	return Match{}, false, nil
+}
+
+func address(desc urtypes.OutputDescriptor, branches []branchKey, index uint32) (string, error) {
 	var addr btcutil.Address
 	var network *chaincfg.Params
 	switch desc.Type {
 	case urtypes.SortedMulti:
 		var keys []*btcutil.AddressPubKey
-		for _, k := range desc.Keys {
-			pub, err := derivePubKey(k, index, change)
+		for i, k := range desc.Keys {
+			pub, err := branches[i].derive(index)
 			if err != nil {
 				return "", fmt.Errorf("address: %w", err)
 			}
@@ -75,7 +182,7 @@ func address(desc urtypes.OutputDescriptor, index uint32, change bool) (string,
 	case urtypes.Singlesig:
 		k := desc.Keys[0]
 		network = k.Network
-		pub, err := derivePubKey(k, index, change)
+		pub, err := branches[0].derive(index)
 		if err != nil {
 			return "", fmt.Errorf("address: %w", err)
 		}
@@ -113,7 +220,8 @@ func address(desc urtypes.OutputDescriptor, index uint32, change bool) (string,
 	return addr.String(), nil
 }
 
-func derivePubKey(k urtypes.KeyDescriptor, index uint32, change bool) (*secp256k1.PublicKey, error) {
+// deriveBranch derives k up to the wildcard element of its path.
+func deriveBranch(k urtypes.KeyDescriptor, change bool) (branchKey, error) {
 	children := k.Children
 	if len(children) == 0 {
 		// Default to <0;1>/*.
@@ -128,7 +236,7 @@ func derivePubKey(k urtypes.KeyDescriptor, index uint32, change bool) (*secp256k
 			},
 		)
 	}
-	xpub := k.ExtendedKey()
+	b := branchKey{xpub: k.ExtendedKey()}
 	for _, c := range children {
 		var id uint32
 		switch c.Type {
@@ -136,17 +244,45 @@ func derivePubKey(k urtypes.KeyDescriptor, index uint32, change bool) (*secp256k
 			id = c.Index
 		case urtypes.RangeDerivation:
 			if c.End != c.Index+1 {
-				return nil, errors.New("unsupported range path element")
+				return branchKey{}, errors.New("unsupported range path element")
 			}
 			id = c.Index
 			if change {
 				id = c.End
 			}
 		case urtypes.WildcardDerivation:
-			id = index
+			if b.wildcard {
+				return branchKey{}, errors.New("multiple wildcard path elements")
+			}
+			b.wildcard = true
+			continue
 		default:
-			return nil, errors.New("unsupported path element")
+			return branchKey{}, errors.New("unsupported path element")
+		}
+		if b.wildcard {
+			b.suffix = append(b.suffix, id)
+			continue
+		}
+		child, err := b.xpub.Derive(id)
+		if err != nil {
+			return branchKey{}, err
+		}
+		b.xpub = child
+	}
+	return b, nil
+}
+
+// derive the public key at index.
+func (b branchKey) derive(index uint32) (*secp256k1.PublicKey, error) {
+	xpub := b.xpub
+	if b.wildcard {
+		child, err := xpub.Derive(index)
+		if err != nil {
+			return nil, err
 		}
+		xpub = child
+	}
+	for _, id := range b.suffix {
 		child, err := xpub.Derive(id)
 		if err != nil {
 			return nil, err
@@ -83,3 +83,62 @@ func TestAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestSearch(t *testing.T) {
+	const xpub = "xpub6DiYrfRwNnjeX4vHsWMajJVFKrbEEnu8gAW9vDuQzgTWEsEHE16sGWeXXUV1LBWQE1yCTmeprSNcqZ3W74hqVdgDbtYHUv3eM4W2TEUhpan"
+	tests := []struct {
+		desc  string
+		addr  string
+		match Match
+		found bool
+	}{
+		{"wpkh(" + xpub + ")", "bc1qkwl5qpx6k93cqmnygn6kgucgka8q3z4kur2nm8", Match{Index: 2}, true},
+		{"wpkh(" + xpub + ")", "BITCOIN:BC1QVWLSCFGDMTKNA074WYLRVQLY4W6NLPKLSMYX7X?amount=0.1", Match{Index: 1, Change: true}, true},
+		{"pkh(" + xpub + ")", "bitcoin:12fk5WJ9AtzQzRWFtCabn8Wh45zmjmcpFR", Match{Change: true}, true},
+		{"wsh(sortedmulti(1," + xpub + "/1234/<5;6>/*))", "bc1qwh9lhlgx9an4kz3s9qtrfm3xyvms84lkjy4paflg408vswjq4zcqx2xzlp", Match{Index: 1, Change: true}, true},
+		// Address of a different script type.
+		{"wpkh(" + xpub + ")", "1M88vKcJFc4KPAe5RHXsuJqWcg3muStyK4", Match{}, false},
+	}
+	for _, test := range tests {
+		desc, err := nonstandard.OutputDescriptor([]byte(test.desc))
+		if err != nil {
+			t.Fatalf("%s: %v", test.desc, err)
+		}
+		d, err := NewDeriver(desc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, found, err := d.Search(test.addr, 0, 10)
+		if err != nil {
+			t.Errorf("%s: %v", test.addr, err)
+			continue
+		}
+		if m != test.match || found != test.found {
+			t.Errorf("%s: got %+v (found %v), want %+v (found %v)", test.addr, m, found, test.match, test.found)
+		}
+	}
+}
+
+func TestSearchInvalid(t *testing.T) {
+	desc, err := nonstandard.OutputDescriptor([]byte("wpkh(xpub6DiYrfRwNnjeX4vHsWMajJVFKrbEEnu8gAW9vDuQzgTWEsEHE16sGWeXXUV1LBWQE1yCTmeprSNcqZ3W74hqVdgDbtYHUv3eM4W2TEUhpan)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDeriver(desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := []string{
+		"",
+		"bitcoin:",
+		// Corrupted checksum.
+		"bc1qkwl5qpx6k93cqmnygn6kgucgka8q3z4kur2nm9",
+		// Testnet address.
+		"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+	}
+	for _, addr := range invalid {
+		if _, _, err := d.Search(addr, 0, 1); err == nil {
+			t.Errorf("%q: searched invalid address", addr)
+		}
+	}
+}
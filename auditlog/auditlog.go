@@ -0,0 +1,294 @@
+// package auditlog implements the engraving audit log of the
+// controller. The log is an append-only file with one signed JSON
+// record per engraved plate side. Records are chained by hash, so
+// removed or reordered records are detected. Entries must never
+// contain secrets such as seeds or descriptors.
+//
+// The signing key is derived from a secret bound to the device, and
+// must not be stored next to the log, or anyone holding the log
+// could forge records.
+package auditlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Entry describes the engraving of a plate side.
+type Entry struct {
+	// Seq is the position of the entry in the log.
+	Seq uint64 `json:"seq"`
+	// Prev is the hex encoded SHA-256 hash of the previous
+	// record, or empty for the first record.
+	Prev string `json:"prev,omitempty"`
+	// Time is the start of the engraving.
+	Time time.Time `json:"time"`
+	// Version is the firmware version.
+	Version string `json:"version"`
+	// Plate is the name of the plate size.
+	Plate string `json:"plate"`
+	// Side is the index of the engraved side.
+	Side int `json:"side"`
+	// Share is the index of the share engraved on the plate,
+	// out of Shares.
+	Share  int `json:"share"`
+	Shares int `json:"shares"`
+	// MasterFingerprint is the hex encoded master fingerprint
+	// of the share.
+	MasterFingerprint string `json:"mfp"`
+	// DescriptorChecksum is the hex encoded checksum of the
+	// wallet descriptor, if any.
+	DescriptorChecksum string `json:"descriptor_checksum,omitempty"`
+	// Title is the title of the wallet, if any.
+	Title  string `json:"title,omitempty"`
+	DryRun bool   `json:"dry_run,omitempty"`
+	// Estimated and Actual are the estimated and actual
+	// durations of the engraving.
+	Estimated time.Duration `json:"estimated_ns"`
+	Actual    time.Duration `json:"actual_ns"`
+	// Error is the driver error that ended the engraving, if
+	// any.
+	Error string `json:"error,omitempty"`
+}
+
+// Record is a verified entry of the log.
+type Record struct {
+	Entry Entry
+	// line is the encoding of the record.
+	line []byte
+}
+
+// Log is the verified contents of a log.
+type Log struct {
+	// Key is the device key that signed the records.
+	Key     ed25519.PublicKey
+	Records []Record
+}
+
+// line is the encoding of a record. The signature covers the
+// encoded entry.
+type line struct {
+	Entry json.RawMessage `json:"entry"`
+	Sig   []byte          `json:"sig"`
+}
+
+// export is the encoding of an exported log.
+type export struct {
+	DeviceKey string            `json:"device_key"`
+	Records   []json.RawMessage `json:"records"`
+}
+
+var (
+	ErrCorrupt   = errors.New("auditlog: corrupt record")
+	ErrSignature = errors.New("auditlog: invalid signature")
+	ErrChain     = errors.New("auditlog: broken record chain")
+	// ErrNoKey is reported by devices whose key is not yet set up.
+	ErrNoKey = errors.New("auditlog: device key not set up")
+)
+
+// DeriveKey derives the device key from a device secret.
+func DeriveKey(secret []byte) ed25519.PrivateKey {
+	mac := hmac.New(sha256.New, []byte("seedhammer audit log key"))
+	mac.Write(secret)
+	return ed25519.NewKeyFromSeed(mac.Sum(nil))
+}
+
+// Load reads and verifies the log at path. A missing file results
+// in an empty log. An incomplete final record, left by an
+// interrupted write, is ignored.
+func Load(path string, key ed25519.PublicKey) (Log, error) {
+	l, _, err := load(path, key)
+	return l, err
+}
+
+// load the log at path and return the size of its complete
+// records.
+func load(path string, key ed25519.PublicKey) (Log, int64, error) {
+	l := Log{Key: key}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return l, 0, nil
+		}
+		return Log{}, 0, fmt.Errorf("auditlog: %w", err)
+	}
+	// Records are written with their terminating newline, so
+	// anything after the last newline is incomplete.
+	b = b[:bytes.LastIndexByte(b, '\n')+1]
+	var lines [][]byte
+	for _, ln := range bytes.Split(b, []byte("\n")) {
+		if len(ln) > 0 {
+			lines = append(lines, ln)
+		}
+	}
+	l.Records, err = verify(key, lines)
+	return l, int64(len(b)), err
+}
+
+// Append signs entries with key and appends them to the log at
+// path. The sequence numbers and hashes of the entries are
+// overwritten to continue the existing records, which are verified
+// first. An incomplete final record is discarded.
+func Append(path string, key ed25519.PrivateKey, entries ...Entry) error {
+	l, size, err := load(path, key.Public().(ed25519.PublicKey))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	prev := l.last()
+	for _, e := range entries {
+		e.Seq = uint64(len(l.Records))
+		e.Prev = prev
+		r, err := sign(key, e)
+		if err != nil {
+			return err
+		}
+		l.Records = append(l.Records, r)
+		prev = r.hash()
+		buf.Write(r.line)
+		buf.WriteByte('\n')
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("auditlog: %w", err)
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return fmt.Errorf("auditlog: %w", err)
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return fmt.Errorf("auditlog: %w", err)
+	}
+	if err := writeSync(f, buf.Bytes()); err != nil {
+		return fmt.Errorf("auditlog: %w", err)
+	}
+	return nil
+}
+
+// Export writes l as a JSON document that includes the device key.
+func Export(w io.Writer, l Log) error {
+	exp := export{
+		DeviceKey: hex.EncodeToString(l.Key),
+		Records:   []json.RawMessage{},
+	}
+	for _, r := range l.Records {
+		exp.Records = append(exp.Records, r.line)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(exp); err != nil {
+		return fmt.Errorf("auditlog: %w", err)
+	}
+	return nil
+}
+
+// ReadExport reads and verifies a log written by Export. Note that
+// the records are verified against the device key included in the
+// document; it is up to the caller to check that it matches the
+// key of the expected device.
+func ReadExport(r io.Reader) (Log, error) {
+	var exp export
+	if err := json.NewDecoder(r).Decode(&exp); err != nil {
+		return Log{}, fmt.Errorf("auditlog: %w", err)
+	}
+	key, err := hex.DecodeString(exp.DeviceKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return Log{}, errors.New("auditlog: invalid device key")
+	}
+	var lines [][]byte
+	for _, rec := range exp.Records {
+		// Undo any re-indentation of the document.
+		var ln bytes.Buffer
+		if err := json.Compact(&ln, rec); err != nil {
+			return Log{}, ErrCorrupt
+		}
+		lines = append(lines, ln.Bytes())
+	}
+	recs, err := verify(key, lines)
+	if err != nil {
+		return Log{}, err
+	}
+	return Log{Key: key, Records: recs}, nil
+}
+
+// Fingerprint returns a short identifier for the device key.
+func Fingerprint(key ed25519.PublicKey) string {
+	h := sha256.Sum256(key)
+	return hex.EncodeToString(h[:4])
+}
+
+// last returns the hash of the last record, or the empty string.
+func (l Log) last() string {
+	if len(l.Records) == 0 {
+		return ""
+	}
+	return l.Records[len(l.Records)-1].hash()
+}
+
+// hash returns the hex encoded hash of the record.
+func (r Record) hash() string {
+	h := sha256.Sum256(r.line)
+	return hex.EncodeToString(h[:])
+}
+
+func sign(key ed25519.PrivateKey, e Entry) (Record, error) {
+	enc, err := json.Marshal(e)
+	if err != nil {
+		return Record{}, fmt.Errorf("auditlog: %w", err)
+	}
+	ln, err := json.Marshal(line{
+		Entry: enc,
+		Sig:   ed25519.Sign(key, enc),
+	})
+	if err != nil {
+		return Record{}, fmt.Errorf("auditlog: %w", err)
+	}
+	return Record{Entry: e, line: ln}, nil
+}
+
+// verify the signatures and chain of the encoded records.
+func verify(key ed25519.PublicKey, lines [][]byte) ([]Record, error) {
+	var recs []Record
+	prev := ""
+	for i, ln := range lines {
+		var l line
+		if err := json.Unmarshal(ln, &l); err != nil {
+			return nil, ErrCorrupt
+		}
+		if !ed25519.Verify(key, l.Entry, l.Sig) {
+			return nil, ErrSignature
+		}
+		r := Record{line: ln}
+		if err := json.Unmarshal(l.Entry, &r.Entry); err != nil {
+			return nil, ErrCorrupt
+		}
+		if r.Entry.Seq != uint64(i) || r.Entry.Prev != prev {
+			return nil, ErrChain
+		}
+		recs = append(recs, r)
+		prev = r.hash()
+	}
+	return recs, nil
+}
+
+func writeSync(f *os.File, data []byte) error {
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
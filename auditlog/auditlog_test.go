@@ -0,0 +1,178 @@
+package auditlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntries() []Entry {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []Entry{
+		{
+			Time:               start,
+			Version:            "v1.2.3",
+			Plate:              "SH02",
+			Side:               0,
+			Share:              1,
+			Shares:             3,
+			MasterFingerprint:  "dc567276",
+			DescriptorChecksum: "1b7a2c9e",
+			Title:              "Satoshi's <Stash>",
+			Estimated:          20 * time.Minute,
+			Actual:             3 * time.Minute,
+			Error:              "engraver disconnected",
+		},
+		{
+			Time:               start.Add(10 * time.Minute),
+			Version:            "v1.2.3",
+			Plate:              "SH02",
+			Side:               0,
+			Share:              1,
+			Shares:             3,
+			MasterFingerprint:  "dc567276",
+			DescriptorChecksum: "1b7a2c9e",
+			Title:              "Satoshi's <Stash>",
+			DryRun:             true,
+			Estimated:          20 * time.Minute,
+			Actual:             21 * time.Minute,
+		},
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	dir := t.TempDir()
+	key := DeriveKey([]byte("device secret"))
+	if !key.Equal(DeriveKey([]byte("device secret"))) {
+		t.Fatal("device key not deterministic")
+	}
+	if key.Equal(DeriveKey([]byte("other secret"))) {
+		t.Fatal("device key independent of the secret")
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	path := filepath.Join(dir, "audit.log")
+	l, err := Load(path, pub)
+	if err != nil || len(l.Records) != 0 {
+		t.Fatalf("missing log loaded as %v, %v", l.Records, err)
+	}
+	entries := testEntries()
+	// Append in two batches.
+	if err := Append(path, key, entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(path, key, entries[1:]...); err != nil {
+		t.Fatal(err)
+	}
+	l, err = Load(path, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Records) != len(entries) {
+		t.Fatalf("loaded %d records, want %d", len(l.Records), len(entries))
+	}
+	for i, r := range l.Records {
+		want := entries[i]
+		want.Seq = uint64(i)
+		if i > 0 {
+			want.Prev = l.Records[i-1].hash()
+		}
+		if !reflect.DeepEqual(r.Entry, want) {
+			t.Errorf("record %d is %+v, want %+v", i, r.Entry, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, l); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := ReadExport(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.Key.Equal(pub) || !reflect.DeepEqual(exp.Records, l.Records) {
+		t.Error("exported log doesn't match")
+	}
+}
+
+func TestTamper(t *testing.T) {
+	dir := t.TempDir()
+	key := DeriveKey([]byte("device secret"))
+	pub := key.Public().(ed25519.PublicKey)
+	path := filepath.Join(dir, "audit.log")
+	if err := Append(path, key, testEntries()...); err != nil {
+		t.Fatal(err)
+	}
+	orig, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(orig), "\n")
+	tests := []struct {
+		name string
+		log  string
+		err  error
+	}{
+		{"edited", strings.Replace(string(orig), `"SH02"`, `"SH03"`, 1), ErrSignature},
+		{"removed", lines[1], ErrChain},
+		{"reordered", lines[1] + lines[0], ErrChain},
+		{"garbage", "{\n", ErrCorrupt},
+	}
+	for _, test := range tests {
+		if err := os.WriteFile(path, []byte(test.log), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path, pub); !errors.Is(err, test.err) {
+			t.Errorf("%s log: got error %v, want %v", test.name, err, test.err)
+		}
+	}
+	// Records signed by another key.
+	if err := os.WriteFile(path, orig, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	other := DeriveKey([]byte("other secret"))
+	if _, err := Load(path, other.Public().(ed25519.PublicKey)); !errors.Is(err, ErrSignature) {
+		t.Errorf("foreign key: got error %v, want %v", err, ErrSignature)
+	}
+	if err := Append(path, other, testEntries()...); err == nil {
+		t.Error("appended to a log signed by another key")
+	}
+}
+
+func TestIncompleteRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := DeriveKey([]byte("device secret"))
+	pub := key.Public().(ed25519.PublicKey)
+	entries := testEntries()
+	if err := Append(path, key, entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a power loss during the next write.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"entry":{"seq":1,`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	l, err := Load(path, pub)
+	if err != nil || len(l.Records) != 1 {
+		t.Fatalf("loaded %d records, %v; want the complete record", len(l.Records), err)
+	}
+	if err := Append(path, key, entries[1]); err != nil {
+		t.Fatal(err)
+	}
+	l, err = Load(path, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Records) != 2 || l.Records[1].Entry.Seq != 1 {
+		t.Fatalf("loaded %d records after append, want 2", len(l.Records))
+	}
+}
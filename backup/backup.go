@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/kortschak/qr"
+	"seedhammer.com/bc/bytewords"
 	"seedhammer.com/bc/fountain"
 	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
@@ -33,6 +34,25 @@ func (p PlateSize) Bounds() image.Rectangle {
 	return image.Rect(x, y, x+w, y+h)
 }
 
+// SafeArea returns the area of the plate, in millimeters, that
+// engravings must stay within.
+func (p PlateSize) SafeArea() image.Rectangle {
+	return p.Bounds().Inset(int(outerMargin))
+}
+
+// KeepOuts returns the areas around the screw holes in the plate
+// corners, in millimeters.
+func (p PlateSize) KeepOuts() []image.Rectangle {
+	b := p.Bounds()
+	m := int(innerMargin)
+	return []image.Rectangle{
+		image.Rect(b.Min.X, b.Min.Y, b.Min.X+m, b.Min.Y+m),
+		image.Rect(b.Max.X-m, b.Min.Y, b.Max.X, b.Min.Y+m),
+		image.Rect(b.Min.X, b.Max.Y-m, b.Min.X+m, b.Max.Y),
+		image.Rect(b.Max.X-m, b.Max.Y-m, b.Max.X, b.Max.Y),
+	}
+}
+
 func (p PlateSize) dims() (int, int) {
 	switch p {
 	case SmallPlate:
@@ -63,6 +83,10 @@ type Seed struct {
 	MasterFingerprint uint32
 	Font              *vector.Face
 	Size              PlateSize
+	// ConstantTime forces every element of the side to be
+	// engraved in a timing insensitive way, not just the
+	// seed words.
+	ConstantTime bool
 }
 
 type Descriptor struct {
@@ -70,6 +94,9 @@ type Descriptor struct {
 	KeyIdx     int
 	Font       *vector.Face
 	Size       PlateSize
+	// ConstantTime forces the QR codes to be engraved in a
+	// timing insensitive way.
+	ConstantTime bool
 }
 
 func dims(c engrave.Command) (engrave.Command, image.Point) {
@@ -81,6 +108,12 @@ var ErrDescriptorTooLarge = errors.New("output descriptor is too large to backup
 
 const MaxTitleLen = 18
 
+// maxQRModules is the size of the densest descriptor QR code, version
+// 20. Denser codes result from descriptors that splitUR can't divide
+// among the shares, and their modules are unreliable to scan from an
+// engraved plate.
+const maxQRModules = 97
+
 const outerMargin float32 = 3
 const innerMargin float32 = 10
 
@@ -132,11 +165,17 @@ func EngraveDescriptor(scale, strokeWidth float32, plate Descriptor) (engrave.Co
 	return engraveSide(scale, plate.Size, func(scale func(v float32) int, plateDims image.Point) (engrave.Command, error) {
 		sw := scale(strokeWidth)
 		urs := splitUR(plate.Descriptor, plate.KeyIdx)
-		return descriptorSide(scale, sw, plate.Font, urs, plate.Size, plateDims)
+		return descriptorSide(scale, sw, plate.Font, urs, plate.Size, plateDims, plate.ConstantTime)
 	})
 }
 
 
+// URs returns the UR fragments engraved as QR codes on the
+// descriptor side of share keyIdx.
+func URs(desc urtypes.OutputDescriptor, keyIdx int) []string {
+	return splitUR(desc, keyIdx)
+}
+
 // splitUR searches for the appropriate seqNum in the [UR] encoding
 // that makes m-of-n backups recoverable regardless of
 // which m-sized subset is used. To achieve that, we're exploiting the
@@ -178,9 +217,20 @@ func EngraveDescriptor(scale, strokeWidth float32, plate Descriptor) (engrave.Co
 //
 // [UR]: https://github.com/BlockchainCommons/Research/blob/master/papers/bcr-2020-005-ur.md
 func splitUR(desc urtypes.OutputDescriptor, keyIdx int) (urs []string) {
-	var shares [][]int
-	var seqLen int
-	m, n := desc.Threshold, len(desc.Keys)
+	seqLen, shares := shareParts(desc.Threshold, len(desc.Keys), keyIdx)
+	data := desc.Encode()
+	check := fountain.Checksum(data)
+	for _, frag := range shares {
+		seqNum := fountain.SeqNumFor(seqLen, check, frag)
+		qr := strings.ToUpper(ur.Encode("crypto-output", data, seqNum, seqLen))
+		urs = append(urs, qr)
+	}
+	return
+}
+
+// shareParts returns the sequence length and the fragments of every
+// part assigned to share keyIdx of an m-of-n backup. See splitUR.
+func shareParts(m, n, keyIdx int) (seqLen int, shares [][]int) {
 	switch {
 	case n-m <= 1:
 		// Optimal: 1 part per share, seqLen m.
@@ -223,16 +273,106 @@ func splitUR(desc urtypes.OutputDescriptor, keyIdx int) (urs []string) {
 		seqLen = 1
 		shares = [][]int{{0}}
 	}
-	data := desc.Encode()
-	check := fountain.Checksum(data)
-	for _, frag := range shares {
-		seqNum := fountain.SeqNumFor(seqLen, check, frag)
-		qr := strings.ToUpper(ur.Encode("crypto-output", data, seqNum, seqLen))
-		urs = append(urs, qr)
-	}
 	return
 }
 
+// ScannedPlates determines the plates scanned during the recovery of a
+// descriptor from the QR codes urs, before the descriptor is known. It
+// returns the number of plates of the backup, and the indices of the
+// plates whose QR codes are all in urs. The number of plates is zero if
+// urs contain no multi-part QR codes of the same descriptor.
+//
+// The scanned QR codes may fit several backups, such as a plate of a
+// 2-of-4 backup and two plates of a 4-of-4 backup. The backup with the
+// fewest plates is assumed, and the result is refined as more plates are
+// scanned. Only backups of at most maxKeys plates are considered.
+func ScannedPlates(urs []string) (plates int, scanned []int) {
+	seqNums := make(map[int]bool)
+	seqLen := 0
+	var check uint32
+	for _, u := range urs {
+		sn, sl, c, ok := urHeader(u)
+		if !ok || seqLen != 0 && (sl != seqLen || c != check) {
+			return 0, nil
+		}
+		seqLen, check = sl, c
+		seqNums[sn] = true
+	}
+	if seqLen < 2 {
+		return 0, nil
+	}
+	// Every multi-part backup has at most one plate more than its
+	// number of fragments.
+	for n := 1; n <= seqLen+1 && n <= maxKeys; n++ {
+	schemes:
+		for m := 1; m <= n; m++ {
+			if sl, _ := shareParts(m, n, 0); sl != seqLen {
+				continue
+			}
+			assigned := make(map[int]bool)
+			scanned = nil
+			for k := 0; k < n; k++ {
+				_, shares := shareParts(m, n, k)
+				found := true
+				for _, frag := range shares {
+					sn := fountain.SeqNumFor(seqLen, check, frag)
+					assigned[sn] = true
+					found = found && seqNums[sn]
+				}
+				if found {
+					scanned = append(scanned, k)
+				}
+			}
+			for sn := range seqNums {
+				if !assigned[sn] {
+					continue schemes
+				}
+			}
+			return n, scanned
+		}
+	}
+	return 0, nil
+}
+
+// urHeader returns the sequence number, sequence length and checksum
+// of a multi-part UR.
+func urHeader(u string) (seqNum, seqLen int, check uint32, ok bool) {
+	parts := strings.Split(strings.ToLower(u), "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, false
+	}
+	data, err := bytewords.Decode(parts[2])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	seqNum, seqLen, check, err = fountain.ParseHeader(data)
+	return seqNum, seqLen, check, err == nil
+}
+
+// Shares returns the indices of the shares of desc whose descriptor QR
+// codes are all present in urs. It is used for determining the plates
+// that were scanned for recovering desc.
+func Shares(desc urtypes.OutputDescriptor, urs []string) []int {
+	scanned := make(map[string]bool)
+	for _, u := range urs {
+		scanned[strings.ToUpper(u)] = true
+	}
+	var shares []int
+	for k := range desc.Keys {
+		found := true
+		for _, u := range splitUR(desc, k) {
+			if !scanned[u] {
+				found = false
+				break
+			}
+		}
+		if found {
+			shares = append(shares, k)
+		}
+	}
+	return shares
+}
+
 func Recoverable(desc urtypes.OutputDescriptor) bool {
 	var shares [][]string
 	for k := range desc.Keys {
@@ -305,27 +445,26 @@ func frontSideSeed(scale func(float32) int, strokeWidth int, plate Seed, plateDi
 	mfp := strings.ToUpper(fmt.Sprintf("%.8x", plate.MasterFingerprint))
 	switch plate.Size {
 	case SmallPlate:
-		pagec, _ := dims(engrave.String(plate.Font, scale(plateSmallFontSize), page))
-		cmd(engrave.Offset(margin, plateDims.Y-innerMargin, engrave.Rotate(-math.Pi/2, pagec)))
-		mfpc, sz := dims(engrave.Rotate(-math.Pi/2, engrave.String(plate.Font, scale(plateSmallFontSize), mfp)))
-		cmd(engrave.Offset(margin, (plateDims.Y-sz.Y)/2, mfpc))
+		pagec, _ := metaString(scale, plate, pageAlphabet, 3, maxPageLen, page, false)
+		cmd(engrave.Label("page", engrave.Offset(margin, plateDims.Y-innerMargin, engrave.Rotate(-math.Pi/2, pagec))))
+		mfpc, sz := metaString(scale, plate, engrave.Hex, 8, 8, mfp, true)
+		cmd(engrave.Label("fingerprint", engrave.Offset(margin, (plateDims.Y-sz.Y)/2, mfpc)))
 		txt, sz := dims(engrave.Rotate(-math.Pi/2, engrave.String(plate.Font, scale(plateSmallFontSize), version)))
-		cmd(engrave.Offset(margin, innerMargin, txt))
+		cmd(engrave.Label("version", engrave.Offset(margin, innerMargin, txt)))
 	default:
 		offy := (plateDims.Y-col1b.Y)/2 - metaMargin
-		pagec, sz := dims(engrave.String(plate.Font, scale(plateSmallFontSize), page))
-		cmd(engrave.Offset(innerMargin, offy-sz.Y, pagec))
-		mfpc, sz := dims(engrave.String(plate.Font, scale(plateSmallFontSize), mfp))
-		cmd(engrave.Offset((plateDims.X-sz.X)/2, offy-sz.Y, mfpc))
+		pagec, sz := metaString(scale, plate, pageAlphabet, 3, maxPageLen, page, false)
+		cmd(engrave.Label("page", engrave.Offset(innerMargin, offy-sz.Y, pagec)))
+		mfpc, sz := metaString(scale, plate, engrave.Hex, 8, 8, mfp, false)
+		cmd(engrave.Label("fingerprint", engrave.Offset((plateDims.X-sz.X)/2, offy-sz.Y, mfpc)))
 		txt, sz := dims(engrave.String(plate.Font, scale(plateSmallFontSize), version))
-		cmd(engrave.Offset(plateDims.X-sz.X-innerMargin, offy-sz.Y, txt))
+		cmd(engrave.Label("version", engrave.Offset(plateDims.X-sz.X-innerMargin, offy-sz.Y, txt)))
 	}
 
 	// Engrave column with words 4 to 9 only.
 	cmd(engrave.Offset(innerMargin, (plateDims.Y-col1b.Y)/2, col1))
 
 	/* Skip engraving QR for the SH01 Plate*/
-	fmt.Println(strokeWidth)
 	// Engrave seed QR.
 	// qrCmd, err := engrave.ConstantQR(strokeWidth, 3, qr.Q, seedqr.CompactQR(plate.Mnemonic))
 	// if err != nil {
@@ -336,14 +475,21 @@ func frontSideSeed(scale func(float32) int, strokeWidth int, plate Seed, plateDi
 
 	// Engrave title.
 	title := strings.ToUpper(plate.Title)
-	switch plate.Size {
-	case SmallPlate:
-		title, sz := dims(engrave.Rotate(-math.Pi/2, engrave.String(plate.Font, scale(plateSmallFontSize), title)))
-		cmd(engrave.Offset(plateDims.X-margin-sz.X, (plateDims.Y-sz.Y)/2, title))
-	default:
-		offy := (plateDims.Y+col1b.Y)/2 + metaMargin
-		title, sz := dims(engrave.String(plate.Font, scale(plateSmallFontSize), title))
-		cmd(engrave.Offset((plateDims.X-sz.X)/2, offy, title))
+	if plate.ConstantTime {
+		title = constantTitle(title)
+	}
+	// An empty title is not engraved, not even in constant time. Its
+	// absence is no secret.
+	if title != "" {
+		switch plate.Size {
+		case SmallPlate:
+			title, sz := metaString(scale, plate, titleAlphabet, 1, MaxTitleLen, title, true)
+			cmd(engrave.Label("title", engrave.Offset(plateDims.X-margin-sz.X, (plateDims.Y-sz.Y)/2, title)))
+		default:
+			offy := (plateDims.Y+col1b.Y)/2 + metaMargin
+			title, sz := metaString(scale, plate, titleAlphabet, 1, MaxTitleLen, title, false)
+			cmd(engrave.Label("title", engrave.Offset((plateDims.X-sz.X)/2, offy, title)))
+		}
 	}
 	if plate.Size == LargePlate {
 		// Avoid the middle holes.
@@ -352,6 +498,52 @@ func frontSideSeed(scale func(float32) int, strokeWidth int, plate Seed, plateDi
 	return cmds, nil
 }
 
+const (
+	// pageAlphabet contains the runes of page numbers such as "1/3".
+	pageAlphabet = "0123456789/"
+	// maxKeys is the maximum number of multisig keys.
+	maxKeys = 20
+	// maxPageLen is the length of the longest page number, "20/20",
+	// for maxKeys.
+	maxPageLen = 5
+	// titleAlphabet contains the runes of titles that can be engraved
+	// in constant time.
+	titleAlphabet = engrave.Alphanumeric + "'(),-./@[]{}"
+)
+
+// metaString is like dims for plate metadata text, optionally rotated
+// by -90 degrees. Text is engraved in constant time if the plate requires
+// it, in which case its size is independent of the text to avoid leaking
+// it through its placement.
+func metaString(scale func(float32) int, plate Seed, alphabet string, shortest, longest int, txt string, rotate bool) (engrave.Command, image.Point) {
+	em := scale(plateSmallFontSize)
+	if !plate.ConstantTime {
+		var c engrave.Command = engrave.String(plate.Font, em, txt)
+		if rotate {
+			c = engrave.Rotate(-math.Pi/2, c)
+		}
+		return dims(c)
+	}
+	cs := engrave.NewConstantStringerAlphabet(plate.Font, em, alphabet, shortest, longest)
+	c, sz := cs.String(txt), cs.Measure()
+	if rotate {
+		c = engrave.Offset(0, sz.X, engrave.Rotate(-math.Pi/2, c))
+		sz = image.Pt(sz.Y, sz.X)
+	}
+	return c, sz
+}
+
+// constantTitle replaces the runes of title that cannot be engraved
+// in constant time, such as spaces, with dashes.
+func constantTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if !strings.ContainsRune(titleAlphabet, r) {
+			return '-'
+		}
+		return r
+	}, title)
+}
+
 
 /*
 Good code / original code only qr part is modifield
@@ -538,10 +730,10 @@ func wordColumn(constant *engrave.ConstantStringer, font *vector.Face, fontSize
 		w := mnemonic[i]
 		word := strings.ToUpper(bip39.LabelFor(w))
 		txt := constant.String(word)
-		cmds = append(cmds,
+		cmds = append(cmds, engrave.Label(fmt.Sprintf("word %d", i+1), engrave.Commands{
 			engrave.Offset(0, y, num),
 			engrave.Offset(d.X, y, txt),
-		)
+		}))
 		y += d.Y
 	}
 	return cmds
@@ -627,7 +819,7 @@ func wordColumn(constant *engrave.ConstantStringer, font *vector.Face, fontSize
 // 	return cmds, nil
 // }
 
-func descriptorSide(scale func(float32) int, strokeWidth int, fnt *vector.Face, urs []string, size PlateSize, plateDims image.Point) (engrave.Commands, error) {
+func descriptorSide(scale func(float32) int, strokeWidth int, fnt *vector.Face, urs []string, size PlateSize, plateDims image.Point, constantTime bool) (engrave.Commands, error) {
 	var cmds engrave.Commands
 	cmd := func(c engrave.Command) {
 		cmds = append(cmds, c)
@@ -637,8 +829,10 @@ func descriptorSide(scale func(float32) int, strokeWidth int, fnt *vector.Face,
 		return engrave.String(fnt, fontSize, s)
 	}
 
-	// Fixed URL to engrave
-	fixedURL := "www.seedcard.xyz" // Replace with the actual URL you want to engrave
+	// Fixed URL to engrave. It is the same for every plate and need not
+	// be engraved in constant time. Upper case, because the engraving font
+	// has no lower case letters.
+	fixedURL := "WWW.SEEDCARD.XYZ"
 
 	// Compute character width, assuming the font is fixed width.
 	charWidthf, _, ok := fnt.Decode('W')
@@ -659,7 +853,14 @@ func descriptorSide(scale func(float32) int, strokeWidth int, fnt *vector.Face,
 
 	// Loop over the URLs and create QR codes for each one.
 	for i, ur := range urs {
-		qrcmd, err := engrave.QR(strokeWidth, 2, qr.M, []byte(ur))
+		if c, err := qr.Encode(ur, qr.M); err != nil || c.Size > maxQRModules {
+			return nil, ErrDescriptorTooLarge
+		}
+		qrFunc := engrave.QR
+		if constantTime {
+			qrFunc = engrave.ConstantQR
+		}
+		qrcmd, err := qrFunc(strokeWidth, 2, qr.M, []byte(ur))
 		if err != nil {
 			return nil, err
 		}
@@ -702,6 +903,11 @@ func descriptorSide(scale func(float32) int, strokeWidth int, fnt *vector.Face,
 		qrx := plateDims.X - qrsz.X - margin - qrBorder
 		qry := qrLineStart*fontSize + (qrLines*fontSize-qrsz.Y)/2
 		cmd(engrave.Offset(qrx, offy+qry, qr))
+		// The URL is engraved only once, so later sections are as
+		// tall as their QR codes.
+		if end := qrLineStart + qrLines; lineno < end {
+			lineno = end
+		}
 		offy += lineno * fontSize
 		if i != len(urs)-1 {
 			// Space UR sections.
@@ -9,10 +9,12 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
+	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
@@ -222,6 +224,176 @@ func TestSplitUR(t *testing.T) {
 	}
 }
 
+func TestShares(t *testing.T) {
+	desc := urtypes.OutputDescriptor{
+		Script:    urtypes.P2WSH,
+		Threshold: 3,
+		Type:      urtypes.SortedMulti,
+		Keys:      make([]urtypes.KeyDescriptor, 5),
+	}
+	genTestPlate(t, desc, desc.Script.DerivationPath(), 12, 0, LargePlate)
+	var urs []string
+	for _, k := range []int{0, 2, 3} {
+		urs = append(urs, splitUR(desc, k)...)
+	}
+	// A partial share.
+	urs = append(urs, splitUR(desc, 4)[0])
+	d := new(ur.Decoder)
+	for _, u := range urs {
+		if err := d.Add(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	typ, enc, err := d.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc == nil {
+		t.Fatal("failed to recover descriptor")
+	}
+	got, err := urtypes.Parse(typ, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := Shares(got.(urtypes.OutputDescriptor), urs)
+	if want := []int{0, 2, 3}; !reflect.DeepEqual(shares, want) {
+		t.Errorf("got shares %v, want %v", shares, want)
+	}
+}
+
+func TestScannedPlates(t *testing.T) {
+	tests := []struct {
+		threshold, keys int
+		shares          []int
+		// partial is a share with only its first QR code
+		// scanned, or -1.
+		partial int
+		plates  int
+		scanned []int
+	}{
+		{2, 3, []int{2}, -1, 3, []int{2}},
+		{2, 3, []int{0, 2}, -1, 3, []int{0, 2}},
+		// Indistinguishable from the first plate of a 2-of-2
+		// backup.
+		{2, 3, []int{0}, -1, 2, []int{0}},
+		{3, 5, []int{1}, -1, 5, []int{1}},
+		{3, 5, []int{0, 3}, 4, 5, []int{0, 3}},
+		{2, 4, []int{3}, -1, 4, []int{3}},
+		{1, 5, []int{0}, -1, 0, nil},
+	}
+	for _, test := range tests {
+		desc := urtypes.OutputDescriptor{
+			Script:    urtypes.P2WSH,
+			Threshold: test.threshold,
+			Type:      urtypes.SortedMulti,
+			Keys:      make([]urtypes.KeyDescriptor, test.keys),
+		}
+		genTestPlate(t, desc, desc.Script.DerivationPath(), 12, 0, LargePlate)
+		var urs []string
+		for _, k := range test.shares {
+			urs = append(urs, splitUR(desc, k)...)
+		}
+		if test.partial >= 0 {
+			urs = append(urs, splitUR(desc, test.partial)[0])
+		}
+		plates, scanned := ScannedPlates(urs)
+		if plates != test.plates || !reflect.DeepEqual(scanned, test.scanned) {
+			t.Errorf("%d-of-%d shares %v: got %d plates, scanned %v; want %d plates, scanned %v",
+				test.threshold, test.keys, test.shares, plates, scanned, test.plates, test.scanned)
+		}
+	}
+	// Parts of a sequence longer than any backup.
+	var urs []string
+	for i := 1; i <= 3; i++ {
+		urs = append(urs, ur.Encode("crypto-output", make([]byte, 2048), i, 1024))
+	}
+	if plates, scanned := ScannedPlates(urs); plates != 0 || scanned != nil {
+		t.Errorf("long sequence: got %d plates, scanned %v; want none", plates, scanned)
+	}
+}
+
+func TestConstantTime(t *testing.T) {
+	const h = hdkeychain.HardenedKeyStart
+	for _, size := range []PlateSize{SmallPlate, SquarePlate, LargePlate} {
+		var seeds, descs [2]*timingProgram
+		for i, title := range []string{"A", "Satoshi's Stash"} {
+			desc := urtypes.OutputDescriptor{
+				Title:     TitleString(constant.Font, title),
+				Script:    urtypes.P2WSH,
+				Threshold: 1,
+				Type:      urtypes.SortedMulti,
+				Keys:      make([]urtypes.KeyDescriptor, 2),
+			}
+			path := []uint32{h + 48, h + 0, h + uint32(i), h + 2}
+			seedDesc, descDesc := genTestPlate(t, desc, path, 12, i, size)
+			seedDesc.ConstantTime = true
+			descDesc.ConstantTime = true
+			seedSide, err := EngraveSeed(mjolnir.Millimeter, mjolnir.StrokeWidth, seedDesc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seeds[i] = new(timingProgram)
+			seedSide.Engrave(seeds[i])
+			descSide, err := EngraveDescriptor(mjolnir.Millimeter, mjolnir.StrokeWidth, descDesc)
+			if errors.Is(err, ErrDescriptorTooLarge) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			descs[i] = new(timingProgram)
+			descSide.Engrave(descs[i])
+		}
+		if !reflect.DeepEqual(seeds[0], seeds[1]) {
+			t.Errorf("%v: seed side timing depends on its content", size)
+		}
+		if !reflect.DeepEqual(descs[0], descs[1]) {
+			t.Errorf("%v: descriptor side timing depends on its content", size)
+		}
+	}
+}
+
+// timingProgram records the sequence of move and line distances, which
+// determines the timing of an engraving. Like the constant time
+// engraving, distances are measured along the longest axis.
+type timingProgram struct {
+	pos   image.Point
+	moves []timingMove
+}
+
+type timingMove struct {
+	line bool
+	dist int
+}
+
+func (t *timingProgram) Move(p image.Point) {
+	t.add(p, false)
+}
+
+func (t *timingProgram) Line(p image.Point) {
+	t.add(p, true)
+}
+
+func (t *timingProgram) add(p image.Point, line bool) {
+	d := p.Sub(t.pos)
+	if d.X < 0 {
+		d.X = -d.X
+	}
+	if d.Y < 0 {
+		d.Y = -d.Y
+	}
+	dist := d.X
+	if d.Y > dist {
+		dist = d.Y
+	}
+	t.pos = p
+	if n := len(t.moves); n > 0 && t.moves[n-1].line == line {
+		t.moves[n-1].dist += dist
+		return
+	}
+	t.moves = append(t.moves, timingMove{line: line, dist: dist})
+}
+
 func TestTitleString(t *testing.T) {
 	tests := []struct {
 		test  string
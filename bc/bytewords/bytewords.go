@@ -7,64 +7,230 @@ package bytewords
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"hash/crc32"
+	"strings"
 )
 
 type byteview interface {
 	~string | ~[]byte
 }
 
+// Style is a bytewords encoding style.
+type Style int
+
+const (
+	// Minimal encodes every byte as the first and last
+	// letter of its word, with no separators. It is the style
+	// used by UR.
+	Minimal Style = iota
+	// Standard encodes every byte as its full word, separated
+	// by spaces.
+	Standard
+	// URI encodes every byte as its full word, separated by
+	// dashes.
+	URI
+)
+
+// ErrChecksum is returned when the checksum of the decoded
+// data doesn't match its encoded checksum.
+var ErrChecksum = errors.New("bytewords: crc32 checksum mismatch")
+
+// UnknownWordError is returned by the decoding functions when
+// a word is not in the bytewords table.
+type UnknownWordError struct {
+	// Word is the unknown word.
+	Word string
+	// Index is the position of the word in the input.
+	Index int
+}
+
+func (e *UnknownWordError) Error() string {
+	return fmt.Sprintf("bytewords: unknown word %q at position %d", e.Word, e.Index)
+}
+
+// Encode encodes data and its checksum in the minimal style.
 func Encode(data []byte) string {
-	buf := make([]byte, 0, (len(data)+4)*2)
-	for _, b := range data {
-		w := words[b]
-		buf = append(buf, w[0], w[3])
-	}
+	return EncodeStyle(Minimal, data)
+}
+
+// EncodeStyle encodes data and its checksum in the given style.
+func EncodeStyle(style Style, data []byte) string {
 	check := crc32.ChecksumIEEE(data)
-	var checkb [4]byte
-	binary.BigEndian.PutUint32(checkb[:], check)
-	for _, b := range checkb {
-		w := words[b]
-		buf = append(buf, w[0], w[3])
+	data = binary.BigEndian.AppendUint32(append([]byte(nil), data...), check)
+	switch style {
+	case Minimal:
+		buf := make([]byte, 0, len(data)*2)
+		for _, b := range data {
+			w := words[b]
+			buf = append(buf, w[0], w[3])
+		}
+		return string(buf)
+	case Standard, URI:
+		sep := byte(' ')
+		if style == URI {
+			sep = '-'
+		}
+		buf := make([]byte, 0, len(data)*5)
+		for i, b := range data {
+			if i > 0 {
+				buf = append(buf, sep)
+			}
+			buf = append(buf, words[b]...)
+		}
+		return string(buf)
+	default:
+		panic("bytewords: invalid style")
 	}
-	return string(buf)
 }
 
+// Decode decodes minimal style bytewords and verifies their
+// checksum.
 func Decode[T byteview](src T) ([]byte, error) {
 	if len(src)%2 == 1 {
-		return nil, errors.New("truncated input")
+		return nil, errors.New("bytewords: truncated input")
 	}
 	dst := make([]byte, len(src)/2)
-	if len(dst) < 4 {
-		return nil, errors.New("input too short")
-	}
-	n := 0
-	for i := 0; i < len(src); i += 2 {
-		w, ok := invMinWords[toU16(src[i], src[i+1])]
+	for i := range dst {
+		w, ok := invMinWords[toU16(src[i*2], src[i*2+1])]
 		if !ok {
-			return nil, errors.New("invalid word")
+			return nil, &UnknownWordError{Word: string(src[i*2 : i*2+2]), Index: i}
 		}
-		dst[n] = w
-		n++
+		dst[i] = w
+	}
+	return verify(dst)
+}
+
+// DecodeStyle decodes bytewords in the given style and verifies
+// their checksum. Full words are matched without regard to case.
+func DecodeStyle(style Style, src string) ([]byte, error) {
+	var sep string
+	switch style {
+	case Minimal:
+		return Decode(src)
+	case Standard:
+		sep = " "
+	case URI:
+		sep = "-"
+	default:
+		panic("bytewords: invalid style")
+	}
+	var dst []byte
+	if src != "" {
+		for i, w := range strings.Split(src, sep) {
+			b, ok := invWords[strings.ToLower(w)]
+			if !ok {
+				return nil, &UnknownWordError{Word: w, Index: i}
+			}
+			dst = append(dst, b)
+		}
+	}
+	return verify(dst)
+}
+
+func verify(dst []byte) ([]byte, error) {
+	if len(dst) < 4 {
+		return nil, errors.New("bytewords: input too short")
 	}
 	res := dst[:len(dst)-4]
 	got := binary.BigEndian.Uint32(dst[len(dst)-4:])
 	want := crc32.ChecksumIEEE(res)
 	if got != want {
-		return nil, errors.New("crc32 checksum mismatch")
+		return nil, ErrChecksum
 	}
 	return res, nil
 }
 
+// Word returns the full word for b.
+func Word(b byte) string {
+	return words[b]
+}
+
+// ClosestWord returns the byte whose word best matches word. The word
+// may be in full or minimal form, and may contain '?' for letters that
+// are unreadable, such as from a damaged plate. A full word matches if
+// it is at most one edit away from a bytewords word; a minimal word must
+// match exactly, except for '?'. ClosestWord returns false if no word is
+// close enough, or if more than one word is equally close.
+func ClosestWord(word string) (byte, bool) {
+	word = strings.ToLower(word)
+	maxDist := 1
+	var dist func(w string) int
+	if len(word) == 2 {
+		maxDist = 0
+		dist = func(w string) int {
+			d := 0
+			if !letterMatch(word[0], w[0]) {
+				d++
+			}
+			if !letterMatch(word[1], w[3]) {
+				d++
+			}
+			return d
+		}
+	} else {
+		dist = func(w string) int {
+			return editDistance(word, w)
+		}
+	}
+	best, bestDist, unique := 0, maxDist+1, false
+	for i, w := range words {
+		d := dist(w)
+		switch {
+		case d < bestDist:
+			best, bestDist, unique = i, d, true
+		case d == bestDist:
+			unique = false
+		}
+	}
+	return byte(best), unique && bestDist <= maxDist
+}
+
+func letterMatch(typed, want byte) bool {
+	return typed == '?' || typed == want
+}
+
+// editDistance computes the Levenshtein distance between
+// a and b, where '?' in a matches any letter.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if letterMatch(a[i-1], b[j-1]) {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
 func toU16(first, last byte) uint16 {
 	return uint16(first)<<8 | uint16(last)
 }
 
-var invMinWords = make(map[uint16]byte)
+var (
+	invMinWords = make(map[uint16]byte)
+	invWords    = make(map[string]byte)
+)
 
 func init() {
 	for i, w := range words {
 		invMinWords[toU16(w[0], w[3])] = byte(i)
+		invWords[w] = byte(i)
 	}
 }
 
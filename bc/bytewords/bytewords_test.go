@@ -3,6 +3,7 @@ package bytewords
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -45,3 +46,108 @@ func TestEncoding(t *testing.T) {
 		}
 	}
 }
+
+func TestStyles(t *testing.T) {
+	data, err := hex.DecodeString("00010280ff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		style Style
+		bw    string
+	}{
+		{Minimal, "aeadaolazmjendeoti"},
+		{Standard, "able acid also lava zoom jade need echo taxi"},
+		{URI, "able-acid-also-lava-zoom-jade-need-echo-taxi"},
+	}
+	for _, test := range tests {
+		if got := EncodeStyle(test.style, data); got != test.bw {
+			t.Errorf("style %d encoding got %q, expected %q", test.style, got, test.bw)
+		}
+		got, err := DecodeStyle(test.style, test.bw)
+		if err != nil {
+			t.Errorf("style %d failed to decode %q: %v", test.style, test.bw, err)
+			continue
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("style %d decoding %q got %#x, expected %#x", test.style, test.bw, got, data)
+		}
+	}
+	if _, err := DecodeStyle(Standard, "ABLE Acid also lava zoom jade need echo taxi"); err != nil {
+		t.Errorf("case insensitive decoding failed: %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		style Style
+		bw    string
+		// index of the unknown word, or -1 for a checksum error.
+		index int
+	}{
+		{Minimal, "aeadaolazmjendeotx", 8},
+		{Minimal, "aeadqqlazmjendeoti", 2},
+		{Standard, "able acid also lava zoom jade need echo tax", 8},
+		{Standard, "able acid also lava zoom jade need echo tent", -1},
+		{URI, "able-acid-alsa-lava-zoom-jade-need-echo-taxi", 2},
+		{Minimal, "aeadaolazmjendeoto", -1},
+	}
+	for _, test := range tests {
+		_, err := DecodeStyle(test.style, test.bw)
+		if test.index == -1 {
+			if !errors.Is(err, ErrChecksum) {
+				t.Errorf("decoding %q returned %v, expected checksum error", test.bw, err)
+			}
+			continue
+		}
+		var werr *UnknownWordError
+		if !errors.As(err, &werr) || werr.Index != test.index {
+			t.Errorf("decoding %q returned %v, expected unknown word at %d", test.bw, err, test.index)
+		}
+	}
+}
+
+func TestClosestWord(t *testing.T) {
+	tests := []struct {
+		word  string
+		want  string
+		valid bool
+	}{
+		{"able", "able", true},
+		{"ae", "able", true},
+		{"AE", "able", true},
+		{"abe", "able", true},
+		{"ab?e", "able", true},
+		{"zoon", "", false},
+		{"zo?m", "zoom", true},
+		{"a?", "", false},
+		{"qq", "", false},
+		{"xxxx", "", false},
+	}
+	for _, test := range tests {
+		got, valid := ClosestWord(test.word)
+		if valid != test.valid {
+			t.Errorf("ClosestWord(%q) reported %v, expected %v", test.word, valid, test.valid)
+			continue
+		}
+		if valid && Word(got) != test.want {
+			t.Errorf("ClosestWord(%q) = %q, expected %q", test.word, Word(got), test.want)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add("aeadaolazmjendeoti")
+	f.Add("able acid also lava zoom jade need echo taxi")
+	f.Add("able-acid-also-lava-zoom-jade-need-echo-taxi")
+	f.Fuzz(func(t *testing.T, bw string) {
+		if data, err := Decode(bw); err == nil {
+			if enc := Encode(data); enc != bw {
+				t.Errorf("%q decoded and re-encoded to %q", bw, enc)
+			}
+		}
+		DecodeStyle(Standard, bw)
+		DecodeStyle(URI, bw)
+		ClosestWord(bw)
+	})
+}
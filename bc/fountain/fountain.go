@@ -7,24 +7,75 @@ package fountain
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"hash/crc32"
+	"math/bits"
 	"reflect"
 	"sort"
-	"strconv"
-	"strings"
 
 	"github.com/fxamacker/cbor/v2"
 	"seedhammer.com/bc/xoshiro256"
 )
 
+// Decoder reassembles messages from fountain encoded parts.
 type Decoder struct {
-	header    partHeader
-	queue     []*part
-	mixed     map[string]*part
-	completed map[int]*part
+	// Limits bound the resources used by the decoder. The
+	// zero value means DefaultLimits.
+	Limits Limits
+
+	header partHeader
+	// rows contains the received parts in reduced row echelon
+	// form, sorted by pivot fragment. A row with a single
+	// fragment is a recovered fragment.
+	rows []*part
+	// mixed is the number of rows with more than one fragment.
+	mixed int
+}
+
+// Limits bound the message size and the memory used by
+// a Decoder, to protect against malicious or corrupt parts.
+type Limits struct {
+	// MaxMessageLen is the maximum length of a message.
+	MaxMessageLen int
+	// MaxSeqLen is the maximum number of fragments in a message.
+	MaxSeqLen int
+	// MaxMixed is the maximum number of pending parts that
+	// contain more than one unrecovered fragment.
+	MaxMixed int
+}
+
+// DefaultLimits are large enough for any output descriptor
+// or other wallet data. MaxMixed is no less than MaxSeqLen,
+// because every message within the limits may need as many
+// pending parts as it has fragments.
+var DefaultLimits = Limits{
+	MaxMessageLen: 64 * 1024,
+	MaxSeqLen:     1024,
+	MaxMixed:      1024,
+}
+
+// LimitError is returned by [Decoder.Add] for parts that
+// exceed the decoder limits.
+type LimitError struct {
+	// Limit is the name of the exceeded limit.
+	Limit string
+	Value int
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("fountain: %s %d exceeds the limit of %d", e.Limit, e.Value, e.Max)
 }
 
+// ErrInvalidPart is returned by [Decoder.Add] for parts with
+// invalid or inconsistent headers.
+var ErrInvalidPart = errors.New("fountain: invalid part")
+
+// ErrIncompatiblePart is returned by [Decoder.Add] for parts that
+// belong to a different message than the previous parts.
+var ErrIncompatiblePart = errors.New("fountain: incompatible fragment")
+
 func Encode(message []byte, seqNum, seqLen int) []byte {
 	if seqLen == 1 {
 		return message
@@ -77,7 +128,8 @@ type part struct {
 	partHeader
 	Data []byte
 
-	fragments []int
+	fragments fragmentSet
+	pivot     int
 }
 
 type partHeader struct {
@@ -86,128 +138,159 @@ type partHeader struct {
 	Checksum   uint32
 }
 
+// Progress returns the fraction of the message that can be
+// recovered from the parts received so far. It is 1 exactly when
+// the complete message is available.
 func (d *Decoder) Progress() float32 {
-	estimated := float32(d.header.SeqLen) * 1.75
-	p := float32(len(d.completed)+len(d.mixed)) / estimated
-	if p > 1 {
-		p = 1
+	if d.header.SeqLen == 0 {
+		return 0
 	}
-	return p
+	return float32(len(d.rows)) / float32(d.header.SeqLen)
 }
 
-func (d *Decoder) Add(data []byte) error {
+// SeqLen returns the number of fragments in the message, or
+// zero if no parts have been received.
+func (d *Decoder) SeqLen() int {
+	return d.header.SeqLen
+}
+
+// Recovered returns the sorted indices of the recovered
+// fragments.
+func (d *Decoder) Recovered() []int {
+	var frags []int
+	for _, r := range d.rows {
+		if r.fragments.count() == 1 {
+			frags = append(frags, r.pivot)
+		}
+	}
+	return frags
+}
+
+// ParseHeader returns the sequence number, sequence length
+// and message checksum of an encoded part.
+func ParseHeader(data []byte) (seqNum, seqLen int, checksum uint32, err error) {
+	p, err := decodePart(data)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return int(p.SeqNum), p.SeqLen, p.Checksum, nil
+}
+
+func decodePart(data []byte) (*part, error) {
 	mode, err := cbor.DecOptions{
 		ExtraReturnErrors: cbor.ExtraDecErrorUnknownField,
 	}.DecMode()
 	if err != nil {
-		return fmt.Errorf("fountain: failed to initialize decoder: %w", err)
+		return nil, fmt.Errorf("fountain: failed to initialize decoder: %w", err)
 	}
 
 	p := new(part)
 	if err := mode.Unmarshal(data, p); err != nil {
-		return fmt.Errorf("fountain: failed to decode fragment: %w", err)
+		return nil, fmt.Errorf("fountain: failed to decode fragment: %w", err)
 	}
-	if d.header.SeqLen > 0 {
-		if d.header != p.partHeader {
-			return fmt.Errorf("fountain: incompatible fragment")
-		}
-	} else {
-		d.header = p.partHeader
+	return p, nil
+}
+
+func (d *Decoder) limits() Limits {
+	if d.Limits == (Limits{}) {
+		return DefaultLimits
 	}
-	p.fragments = chooseFragments(p.SeqNum, p.SeqLen, p.Checksum)
-	d.queue = append(d.queue, p)
-
-	for len(d.queue) > 0 {
-		p := d.queue[len(d.queue)-1]
-		d.queue = d.queue[:len(d.queue)-1]
-		if len(p.fragments) == 1 {
-			if d.completed == nil {
-				d.completed = make(map[int]*part)
-			}
-			d.completed[p.fragments[0]] = p
-			d.reduceMixed(p)
-		} else {
-			if d.mixed == nil {
-				d.mixed = make(map[string]*part)
-			}
-			for _, other := range d.completed {
-				reducePart(p, other)
-			}
-			for _, other := range d.mixed {
-				reducePart(p, other)
-			}
-			if len(p.fragments) == 1 {
-				d.queue = append(d.queue, p)
-			} else {
-				d.reduceMixed(p)
-				d.mixed[mixedKey(p.fragments)] = p
-			}
-		}
+	return d.Limits
+}
+
+// validate checks the part header against the limits and
+// for consistency.
+func (l Limits) validate(p *part) error {
+	switch {
+	case p.SeqLen > l.MaxSeqLen:
+		return &LimitError{Limit: "sequence length", Value: p.SeqLen, Max: l.MaxSeqLen}
+	case p.MessageLen > l.MaxMessageLen:
+		return &LimitError{Limit: "message length", Value: p.MessageLen, Max: l.MaxMessageLen}
+	case p.SeqNum == 0, p.SeqLen < 1, p.MessageLen < 1:
+		return ErrInvalidPart
+	}
+	if fragLen := (p.MessageLen + p.SeqLen - 1) / p.SeqLen; len(p.Data) != fragLen {
+		return ErrInvalidPart
 	}
 	return nil
 }
 
-func reducePart(a, b *part) {
-	// return if b is not a strict subset of a.
-	if len(b.fragments) >= len(a.fragments) {
-		return
+func (d *Decoder) Add(data []byte) error {
+	p, err := decodePart(data)
+	if err != nil {
+		return err
 	}
-	m := make(map[int]bool)
-	for _, f := range a.fragments {
-		m[f] = true
+	limits := d.limits()
+	if err := limits.validate(p); err != nil {
+		return err
 	}
-	for _, f := range b.fragments {
-		if !m[f] {
-			return
+	if d.header.SeqLen > 0 {
+		if d.header != p.partHeader {
+			return ErrIncompatiblePart
 		}
-		delete(m, f)
+	} else {
+		d.header = p.partHeader
 	}
-
-	// Subtract b from a.
-	a.fragments = nil
-	for f := range m {
-		a.fragments = append(a.fragments, f)
+	p.fragments = newFragmentSet(p.SeqLen)
+	for _, f := range chooseFragments(p.SeqNum, p.SeqLen, p.Checksum) {
+		p.fragments.add(f)
 	}
-	for i := range a.Data {
-		a.Data[i] ^= b.Data[i]
+	// Reduce the part by the existing rows. Because the rows are
+	// in reduced echelon form, every row pivot is only present in
+	// its own row and a single pass is sufficient.
+	for _, r := range d.rows {
+		if p.fragments.has(r.pivot) {
+			p.subtract(r)
+		}
 	}
-}
-
-func (d *Decoder) reduceMixed(p *part) {
-	for k, other := range d.mixed {
-		delete(d.mixed, k)
-		reducePart(other, p)
-		if len(other.fragments) == 1 {
-			d.queue = append(d.queue, other)
-		} else {
-			d.mixed[mixedKey(other.fragments)] = other
+	n := p.fragments.count()
+	if n == 0 {
+		// Redundant part.
+		return nil
+	}
+	if n > 1 && d.mixed >= limits.MaxMixed {
+		return &LimitError{Limit: "pending parts", Value: d.mixed + 1, Max: limits.MaxMixed}
+	}
+	p.pivot = p.fragments.first()
+	// Eliminate the new pivot from the existing rows.
+	d.mixed = 0
+	for _, r := range d.rows {
+		if r.fragments.has(p.pivot) {
+			r.subtract(p)
 		}
+		if r.fragments.count() > 1 {
+			d.mixed++
+		}
+	}
+	if n > 1 {
+		d.mixed++
 	}
+	idx := sort.Search(len(d.rows), func(i int) bool {
+		return d.rows[i].pivot > p.pivot
+	})
+	d.rows = append(d.rows, nil)
+	copy(d.rows[idx+1:], d.rows[idx:])
+	d.rows[idx] = p
+	return nil
 }
 
-func mixedKey(ids []int) string {
-	sort.Ints(ids)
-	strs := make([]string, len(ids))
-	for i, id := range ids {
-		strs[i] = strconv.Itoa(id)
+// subtract removes the fragments of o from p.
+func (p *part) subtract(o *part) {
+	p.fragments.xor(o.fragments)
+	for i := range p.Data {
+		p.Data[i] ^= o.Data[i]
 	}
-	return strings.Join(strs, "|")
 }
 
 func (d *Decoder) Result() ([]byte, error) {
-	if len(d.completed) != d.header.SeqLen {
+	if d.header.SeqLen == 0 || len(d.rows) != d.header.SeqLen {
 		return nil, nil
 	}
-	var sorted []*part
-	for _, p := range d.completed {
-		sorted = append(sorted, p)
-	}
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].fragments[0] < sorted[j].fragments[0]
-	})
+	// A full rank matrix in reduced row echelon form is the identity
+	// matrix, so the rows are the fragments in order.
 	var msg []byte
-	for _, p := range sorted {
-		msg = append(msg, p.Data...)
+	for _, r := range d.rows {
+		msg = append(msg, r.Data...)
 	}
 	if len(msg) < d.header.MessageLen {
 		return nil, fmt.Errorf("fountain: message too short")
@@ -220,6 +303,46 @@ func (d *Decoder) Result() ([]byte, error) {
 	return msg, nil
 }
 
+// fragmentSet is a bit set of fragment indices.
+type fragmentSet []uint64
+
+func newFragmentSet(seqLen int) fragmentSet {
+	return make(fragmentSet, (seqLen+63)/64)
+}
+
+func (s fragmentSet) add(f int) {
+	s[f/64] |= 1 << (f % 64)
+}
+
+func (s fragmentSet) has(f int) bool {
+	return s[f/64]&(1<<(f%64)) != 0
+}
+
+func (s fragmentSet) xor(o fragmentSet) {
+	for i := range s {
+		s[i] ^= o[i]
+	}
+}
+
+func (s fragmentSet) count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// first returns the lowest fragment in the set, or -1 if
+// the set is empty.
+func (s fragmentSet) first() int {
+	for i, w := range s {
+		if w != 0 {
+			return i*64 + bits.TrailingZeros64(w)
+		}
+	}
+	return -1
+}
+
 func Checksum(data []byte) uint32 {
 	return crc32.ChecksumIEEE(data)
 }
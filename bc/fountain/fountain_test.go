@@ -58,12 +58,12 @@ func TestDecoding(t *testing.T) {
 		},
 		{
 			[]string{
-				"850002011ad202ef8d4100",
 				"850102011ad202ef8d4100",
+				"850202011ad202ef8d4100",
 			},
 			"00",
 			2,
-			[]int{0, 1},
+			[]int{1, 2},
 		},
 	}
 	for _, test := range tests {
@@ -161,3 +161,144 @@ func TestChooseFragments(t *testing.T) {
 		t.Errorf("mismatched fragment indexes")
 	}
 }
+
+func FuzzDecoder(f *testing.F) {
+	parts := []string{
+		"8505091901031aeda0ae73581dd60b3ec4bbff1b9ffe8a9e7240129377b9d3711ed38d412fbb4442256f",
+		"850c091901031aeda0ae73581db7808bff2e4ccec832643eed6ff0af2598cfc3e31a52fe92e2e380b829",
+		"850d091901031aeda0ae73581d967bd87a541717f538efe54f485b524df71fa3fba8b608a717165b8240",
+	}
+	for i := 0; i < len(parts)-1; i++ {
+		p1, err := hex.DecodeString(parts[i])
+		if err != nil {
+			f.Fatal(err)
+		}
+		p2, err := hex.DecodeString(parts[i+1])
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(p1, p2)
+	}
+	f.Fuzz(func(t *testing.T, p1, p2 []byte) {
+		var d Decoder
+		d.Add(p1)
+		d.Add(p2)
+		d.Progress()
+		d.Result()
+	})
+}
+
+func TestLimits(t *testing.T) {
+	msg := make([]byte, 100)
+	tests := []struct {
+		limits Limits
+		seqLen int
+	}{
+		{Limits{MaxMessageLen: 99, MaxSeqLen: 10, MaxMixed: 10}, 2},
+		{Limits{MaxMessageLen: 100, MaxSeqLen: 9, MaxMixed: 10}, 10},
+	}
+	for _, test := range tests {
+		d := Decoder{Limits: test.limits}
+		err := d.Add(Encode(msg, 1, test.seqLen))
+		if _, ok := err.(*LimitError); !ok {
+			t.Errorf("limits %+v: got error %v, expected a limit error", test.limits, err)
+		}
+	}
+	// Exceed the pending mixed parts limit.
+	d := Decoder{Limits: Limits{MaxMessageLen: 100, MaxSeqLen: 10, MaxMixed: 1}}
+	var err error
+	for seqNum := 11; seqNum < 100 && err == nil; seqNum++ {
+		err = d.Add(Encode(msg, seqNum, 10))
+	}
+	if _, ok := err.(*LimitError); !ok {
+		t.Errorf("got error %v, expected a limit error for mixed parts", err)
+	}
+	if d.mixed > 1 {
+		t.Errorf("decoder kept %d mixed parts, exceeding the limit", d.mixed)
+	}
+}
+
+func TestLongSequence(t *testing.T) {
+	const seqLen = 512
+	msg := make([]byte, seqLen*10)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	var d Decoder
+	// Use only mixed parts, which accumulate more than 256 pending
+	// parts before the message is recovered.
+	for seqNum := seqLen + 1; ; seqNum++ {
+		if seqNum > 10*seqLen {
+			t.Fatal("message not recovered")
+		}
+		if err := d.Add(Encode(msg, seqNum, seqLen)); err != nil {
+			t.Fatalf("part %d: %v", seqNum, err)
+		}
+		res, err := d.Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != nil {
+			if !bytes.Equal(res, msg) {
+				t.Error("mismatched decoded value")
+			}
+			break
+		}
+	}
+}
+
+func TestInvalidPart(t *testing.T) {
+	// Part with sequence number 0.
+	p, err := hex.DecodeString("850002011ad202ef8d4100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decoder
+	if err := d.Add(p); err != ErrInvalidPart {
+		t.Errorf("got error %v, expected %v", err, ErrInvalidPart)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	const seqLen = 10
+	var d Decoder
+	prev := float32(0)
+	// Use only mixed parts to exercise the elimination.
+	for seqNum := seqLen + 1; ; seqNum++ {
+		if err := d.Add(Encode(msg, seqNum, seqLen)); err != nil {
+			t.Fatal(err)
+		}
+		p := d.Progress()
+		if p < prev {
+			t.Fatalf("progress decreased from %v to %v", prev, p)
+		}
+		prev = p
+		res, err := d.Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (res != nil) != (p == 1) {
+			t.Fatalf("progress %v with result %v", p, res != nil)
+		}
+		rec := d.Recovered()
+		if p == 1 {
+			if len(rec) != seqLen {
+				t.Errorf("recovered %v at completion", rec)
+			}
+			if !bytes.Equal(res, msg) {
+				t.Error("mismatched decoded value")
+			}
+			break
+		}
+		if len(rec) == seqLen {
+			t.Fatalf("all fragments recovered at progress %v", p)
+		}
+	}
+	if got := d.SeqLen(); got != seqLen {
+		t.Errorf("SeqLen returned %d, expected %d", got, seqLen)
+	}
+}
@@ -21,7 +21,12 @@ func Encode(_type string, message []byte, seqNum, seqLen int) string {
 	return fmt.Sprintf("ur:%s/%d-%d/%s", _type, seqNum, seqLen, bytewords.Encode(data))
 }
 
+// Decoder decodes single and multi-part URs.
 type Decoder struct {
+	// Limits bound the resources used for decoding multi-part
+	// URs and the size of single-part URs.
+	Limits fountain.Limits
+
 	typ  string
 	data []byte
 
@@ -35,6 +40,23 @@ func (d *Decoder) Progress() float32 {
 	return d.fountain.Progress()
 }
 
+// SeqLen returns the number of fragments of the UR being decoded, or
+// zero if no parts have been added.
+func (d *Decoder) SeqLen() int {
+	if d.data != nil {
+		return 1
+	}
+	return d.fountain.SeqLen()
+}
+
+// Recovered returns the indices of the fragments recovered so far.
+func (d *Decoder) Recovered() []int {
+	if d.data != nil {
+		return []int{0}
+	}
+	return d.fountain.Recovered()
+}
+
 func (d *Decoder) Result() (string, []byte, error) {
 	if d.data != nil {
 		return d.typ, d.data, nil
@@ -59,7 +81,7 @@ func (d *Decoder) Add(ur string) error {
 	}
 	typ := parts[0]
 	if d.typ != "" && d.typ != typ {
-		return errors.New("ur: incompatible fragment")
+		return fmt.Errorf("ur: %w: type %q", fountain.ErrIncompatiblePart, typ)
 	}
 	d.typ = typ
 	var seqAndLen string
@@ -78,10 +100,18 @@ func (d *Decoder) Add(ur string) error {
 		if _, err := fmt.Sscanf(seqAndLen, "%d-%d", &seq, &n); err != nil {
 			return fmt.Errorf("ur: invalid sequence %q", seqAndLen)
 		}
+		d.fountain.Limits = d.Limits
 		if err := d.fountain.Add(enc); err != nil {
 			return err
 		}
 	} else {
+		limits := d.Limits
+		if limits == (fountain.Limits{}) {
+			limits = fountain.DefaultLimits
+		}
+		if len(enc) > limits.MaxMessageLen {
+			return &fountain.LimitError{Limit: "message length", Value: len(enc), Max: limits.MaxMessageLen}
+		}
 		d.data = enc
 	}
 	return nil
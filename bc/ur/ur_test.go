@@ -117,3 +117,21 @@ func TestDecode(t *testing.T) {
 		}
 	}
 }
+
+func FuzzDecoder(f *testing.F) {
+	f.Add(
+		"ur:bytes/1-9/lpadascfadaxcywenbpljkhdcahkadaemejtswhhylkepmykhhtsytsnoyoyaxaedsuttydmmhhpktpmsrjtdkgslpgh",
+		"ur:bytes/2-9/lpaoascfadaxcywenbpljkhdcagwdpfnsboxgwlbaawzuefywkdplrsrjynbvygabwjldapfcsgmghhkhstlrdcxaefz",
+	)
+	f.Add(
+		"ur:crypto-seed/oyadgdiywlamaejszswdwytltifeenftlnmnwkbdhnssro",
+		"ur:crypto-seed/oyadgdiywlamaejszswdwytltifeenftlnmnwkbdhnssro",
+	)
+	f.Fuzz(func(t *testing.T, ur1, ur2 string) {
+		var d Decoder
+		d.Add(ur1)
+		d.Add(ur2)
+		d.Progress()
+		d.Result()
+	})
+}
@@ -321,3 +321,24 @@ func TestIncompleteCryptoAccount(t *testing.T) {
 		t.Fatalf("invalid crypto-account %s parsed succesfully", enc)
 	}
 }
+
+func FuzzParse(f *testing.F) {
+	seeds := []struct {
+		typ string
+		enc string
+	}{
+		{"crypto-seed", "a1015066e9060071faeaeed5d045363a868ef4"},
+		{"crypto-account", "a2011a4bbaa8010281d90194d9012fa403582102a1e9cd9efc051f3e0374bf213990d23bf3d77fddf172bcc62343c4d782e780ec0458203fac4d00922802a9f2bd520cc4512230cf290b4a5d297e5d3a69b99f06577f6606d90130a301861854f500f500f5021a4bbaa8010303081a43ecdeeb"},
+		{"crypto-output", "d90191d90196a201010282d9012fa403582103cbcaa9c98c877a26977d00825c956a238e8dddfbd322cce4f74b0b5bd6ace4a704582060499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd968906d90130a20180030007d90130a1018601f400f480f4d9012fa403582102fc9e5af0ac8d9b3cecfe2a888e2117ba3d089d8585886c9c826b6b22a98d12ea045820f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c06d90130a2018200f4021abd16bee507d90130a1018600f400f480f4"},
+	}
+	for _, s := range seeds {
+		enc, err := hex.DecodeString(s.enc)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(s.typ, enc)
+	}
+	f.Fuzz(func(t *testing.T, typ string, enc []byte) {
+		Parse(typ, enc)
+	})
+}
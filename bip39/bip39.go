@@ -125,6 +125,25 @@ func (m Mnemonic) FixChecksum() Mnemonic {
 	return m2
 }
 
+// LastWords returns every final word that completes the
+// mnemonic with a valid checksum. The final word of m is
+// ignored. There are 128 candidates for 12 word mnemonics
+// and 8 for 24 word mnemonics.
+func (m Mnemonic) LastWords() []Word {
+	const wordBits = 11
+	checkBits := len(m) / 3
+	m2 := make(Mnemonic, len(m))
+	copy(m2, m)
+	words := make([]Word, 1<<(wordBits-checkBits))
+	for i := range words {
+		// Fill in the entropy bits of the final word and
+		// compute its checksum bits.
+		m2[len(m2)-1] = Word(i << checkBits)
+		words[i] = m2.FixChecksum()[len(m2)-1]
+	}
+	return words
+}
+
 // Entropy returns the entropy represented by the mnemonic. It
 // panics if the mnemonic is invalid.
 func (m Mnemonic) Entropy() []byte {
@@ -176,6 +195,55 @@ func ChecksumWord(entropy []byte) Word {
 	return w % Word(len(index))
 }
 
+// New returns the mnemonic that encodes entropy, including the
+// checksum word. The length of entropy must be a multiple of 4
+// bytes from 16 to 32.
+func New(entropy []byte) Mnemonic {
+	if n := len(entropy); n%4 != 0 || n < 16 || n > 32 {
+		panic("invalid entropy length")
+	}
+	const wordBits = 11
+	checkBits := len(entropy) / 4
+	m := make(Mnemonic, (len(entropy)*8+checkBits)/wordBits)
+	bit := func(i int) Word {
+		return Word(entropy[i/8]>>(7-i%8)) & 1
+	}
+	for i := range m[:len(m)-1] {
+		for j := 0; j < wordBits; j++ {
+			m[i] = m[i]<<1 | bit(i*wordBits+j)
+		}
+	}
+	m[len(m)-1] = ChecksumWord(entropy)
+	return m
+}
+
+// EntropySize returns the number of entropy bytes of a mnemonic
+// with n words.
+func EntropySize(n int) int {
+	return n * 4 / 3
+}
+
+// DiceRolls returns the number of die rolls for the entropy of
+// a mnemonic of n words, as hashed by DiceEntropy.
+func DiceRolls(n int) int {
+	if n == 12 {
+		return 50
+	}
+	return 99
+}
+
+// DiceEntropy hashes die rolls, each from 1 to 6, into the
+// entropy for a mnemonic of n words. The rolls are hashed as
+// a string of digits, compatible with the Coldcard dice method.
+func DiceEntropy(rolls []int, n int) []byte {
+	digits := make([]byte, len(rolls))
+	for i, r := range rolls {
+		digits[i] = '0' + byte(r)
+	}
+	h := sha256.Sum256(digits)
+	return h[:EntropySize(n)]
+}
+
 func MnemonicSeed(m Mnemonic, password string) []byte {
 	var sentence strings.Builder
 	for i, w := range m {
@@ -2,7 +2,10 @@ package bip39
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -30,6 +33,43 @@ func TestVectors(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	for _, v := range testVectors {
+		e, err := hex.DecodeString(v.entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ParseMnemonic(v.mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := New(e)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("New(%s) = %v, want %v", v.entropy, got, want)
+		}
+	}
+}
+
+func TestDiceEntropy(t *testing.T) {
+	for _, n := range []int{12, 24} {
+		rolls := make([]int, DiceRolls(n))
+		digits := ""
+		for i := range rolls {
+			rolls[i] = i%6 + 1
+			digits += strconv.Itoa(rolls[i])
+		}
+		h := sha256.Sum256([]byte(digits))
+		want := h[:EntropySize(n)]
+		got := DiceEntropy(rolls, n)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%d words: got entropy %x, want %x", n, got, want)
+		}
+		if m := New(got); len(m) != n || !m.Valid() {
+			t.Errorf("%d words: invalid mnemonic %v", n, m)
+		}
+	}
+}
+
 func TestInvalidSeeds(t *testing.T) {
 	tests := []string{
 		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
@@ -56,6 +96,40 @@ func TestChecksumWord(t *testing.T) {
 	}
 }
 
+func TestLastWords(t *testing.T) {
+	for _, v := range testVectors {
+		m, err := ParseMnemonic(v.mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[int]int{12: 128, 24: 8}[len(m)]
+		if want == 0 {
+			continue
+		}
+		words := m.LastWords()
+		if len(words) != want {
+			t.Errorf("%d words: got %d candidates, want %d", len(m), len(words), want)
+		}
+		seen := make(map[Word]bool)
+		found := false
+		for _, w := range words {
+			if seen[w] {
+				t.Errorf("%v: duplicate candidate %v", m, w)
+			}
+			seen[w] = true
+			m2 := append(Mnemonic{}, m...)
+			m2[len(m2)-1] = w
+			if !m2.Valid() {
+				t.Errorf("%v: invalid candidate %v", m, w)
+			}
+			found = found || w == m[len(m)-1]
+		}
+		if !found {
+			t.Errorf("%v: candidates don't include the last word", m)
+		}
+	}
+}
+
 var testVectors = []struct {
 	entropy  string
 	mnemonic string
@@ -3,15 +3,19 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"image"
 	"image/png"
+	"math"
+	"math/rand"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -19,20 +23,33 @@ import (
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
+	"seedhammer.com/driver"
+	"seedhammer.com/driver/grbl"
 	"seedhammer.com/driver/mjolnir"
 	"seedhammer.com/engrave"
+	"seedhammer.com/engrave/analysis"
 	"seedhammer.com/font/constant"
 	"seedhammer.com/nonstandard"
 )
 
 var (
 	serialDev  = flag.String("device", "", "serial device")
+	driverName = flag.String("driver", "mjolnir", "engraver driver (mjolnir, grbl)")
 	dryrun     = flag.Bool("n", false, "dry run")
 	output     = flag.String("o", "plates", "output plates to directory")
 	side       = flag.String("side", "front", "plate side, front or back")
 	size       = flag.String("size", "SH02", "plate size (SH01, SH02, SH03)")
 	descriptor = flag.String("descriptor", "wpkh([97a6d3c2/84h/1h/0h]tpubDD5cTgxiP4qYJgBgkS6arjQH3GsJEHExFZWvumhNGGe4gBShn9u3b4TdpG2DvRg3knNXV7fBdmaw6cH2kKYdk2aXjQZYsnTchA4aFsZWehG)", "output descriptor")
 	mnemonic   = flag.String("mnemonic", "vocal tray giggle tool duck letter category pattern train magnet excite swamp", "seed phrase")
+	constTime  = flag.Bool("constant", false, "engrave every element in constant time")
+	rounds     = flag.Int("rounds", 1000, "number of random mnemonics to audit")
+	optimize   = flag.Bool("optimize", false, "reorder strokes to minimize travel")
+	runLog     = flag.String("log", "", "log engraving durations to file, for calibration")
+	format     = flag.String("format", "png", "output format (png, svg, pdf, gcode)")
+	feedRate   = flag.Float64("feed", float64(engrave.DefaultGCode.FeedRate), "G-code feed rate in mm/min")
+	travelRate = flag.Float64("travel", float64(engrave.DefaultGCode.TravelRate), "G-code travel rate in mm/min")
+	penDown    = flag.String("pendown", engrave.DefaultGCode.PenDown, "G-code pen down macro")
+	penUp      = flag.String("penup", engrave.DefaultGCode.PenUp, "G-code pen up macro")
 )
 
 func main() {
@@ -51,6 +68,15 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("invalid mnemonic: %w", err)
 	}
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+	case "audit":
+		return audit(m)
+	case "calibrate":
+		return calibrate(*runLog)
+	default:
+		return fmt.Errorf("unknown command: %s", cmd)
+	}
 	seed := bip39.MnemonicSeed(m, "")
 	var desc urtypes.OutputDescriptor
 	if *descriptor != "" {
@@ -113,47 +139,70 @@ func run() error {
 	if keyIdx == -1 {
 		return errors.New("seed is not among the descriptor keys")
 	}
-	var psz backup.PlateSize
-	switch *size {
-	case "SH01":
-		psz = backup.SmallPlate
-	case "SH02":
-		psz = backup.SquarePlate
-	case "SH03":
-		psz = backup.LargePlate
-	default:
-		return fmt.Errorf("-size must be 'SH01', 'SH02' or 'SH03'")
+	psz, err := plateSize(*size)
+	if err != nil {
+		return err
+	}
+	seedPlate := backup.Seed{
+		Title:             desc.Title,
+		KeyIdx:            keyIdx,
+		Mnemonic:          m,
+		Keys:              len(desc.Keys),
+		MasterFingerprint: desc.Keys[keyIdx].MasterFingerprint,
+		Font:              constant.Font,
+		Size:              psz,
+		ConstantTime:      *constTime,
+	}
+	descPlate := backup.Descriptor{
+		Descriptor:   desc,
+		KeyIdx:       keyIdx,
+		Font:         constant.Font,
+		Size:         psz,
+		ConstantTime: *constTime,
 	}
+	front, frontErr := backup.EngraveDescriptor(mjolnir.Millimeter, mjolnir.StrokeWidth, descPlate)
+	back, backErr := backup.EngraveSeed(mjolnir.Millimeter, mjolnir.StrokeWidth, seedPlate)
 	var sideCmd engrave.Command
 	switch *side {
 	case "back":
-		desc := backup.Seed{
-			Title:             desc.Title,
-			KeyIdx:            keyIdx,
-			Mnemonic:          m,
-			Keys:              len(desc.Keys),
-			MasterFingerprint: desc.Keys[keyIdx].MasterFingerprint,
-			Font:              constant.Font,
-			Size:              psz,
-		}
-		sideCmd, err = backup.EngraveSeed(mjolnir.Millimeter, mjolnir.StrokeWidth, desc)
+		sideCmd, err = back, backErr
 	case "front":
-		desc := backup.Descriptor{
-			Descriptor: desc,
-			KeyIdx:     keyIdx,
-			Font:       constant.Font,
-			Size:       psz,
-		}
-		sideCmd, err = backup.EngraveDescriptor(mjolnir.Millimeter, mjolnir.StrokeWidth, desc)
+		sideCmd, err = front, frontErr
 	default:
 		return fmt.Errorf("-side must be 'front' or 'back'")
 	}
 	if err != nil {
 		return err
 	}
+	if *optimize {
+		var travel engrave.Travel
+		sideCmd, travel = engrave.Optimize(sideCmd)
+		fmt.Printf("travel: %.1fmm before, %.1fmm after optimization\n",
+			float64(travel.Before)/mjolnir.Millimeter, float64(travel.After)/mjolnir.Millimeter)
+		if frontErr == nil {
+			front, _ = engrave.Optimize(front)
+		}
+		if backErr == nil {
+			back, _ = engrave.Optimize(back)
+		}
+	}
+	var total time.Duration
+	for _, s := range []struct {
+		name string
+		cmd  engrave.Command
+		err  error
+	}{{"front", front, frontErr}, {"back", back, backErr}} {
+		if s.err != nil {
+			continue
+		}
+		d := estimate(s.cmd).Duration()
+		total += d
+		fmt.Printf("estimated time, %s side: %v\n", s.name, d.Round(time.Second))
+	}
+	fmt.Printf("estimated time, total: %v\n", total.Round(time.Second))
 
 	if *serialDev != "" {
-		err = hammer(sideCmd, *serialDev)
+		err = hammer(sideCmd, psz, *serialDev)
 	} else {
 		if err := os.MkdirAll(*output, 0o755); err != nil {
 			return err
@@ -163,40 +212,173 @@ func run() error {
 	return err
 }
 
-func dump(sideCmd engrave.Command, size backup.PlateSize, keyIdx int, output string) error {
-	const ppmm = 24
-	bounds := size.Bounds()
-	bounds = image.Rectangle{
-		Min: bounds.Min.Mul(ppmm),
-		Max: bounds.Max.Mul(ppmm),
-	}
-	img := image.NewNRGBA(bounds)
-	r := engrave.NewRasterizer(img, img.Bounds(), ppmm/mjolnir.Millimeter, mjolnir.StrokeWidth*ppmm)
-	sideCmd.Engrave(r)
-	r.Rasterize()
-	buf := new(bytes.Buffer)
-	if err := png.Encode(buf, img); err != nil {
+func plateSize(size string) (backup.PlateSize, error) {
+	switch size {
+	case "SH01":
+		return backup.SmallPlate, nil
+	case "SH02":
+		return backup.SquarePlate, nil
+	case "SH03":
+		return backup.LargePlate, nil
+	default:
+		return 0, fmt.Errorf("-size must be 'SH01', 'SH02' or 'SH03'")
+	}
+}
+
+// audit engraves the seed side for random mnemonics and reports
+// the first element whose engraving timing depends on them.
+func audit(m bip39.Mnemonic) error {
+	psz, err := plateSize(*size)
+	if err != nil {
 		return err
 	}
-	file := filepath.Join(output, fmt.Sprintf("plate-%d-side-%s.png", keyIdx, *side))
-	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+	plate := backup.Seed{
+		Title:        backup.TitleString(constant.Font, "Satoshi's Nice Stash"),
+		Mnemonic:     m,
+		Keys:         1,
+		Font:         constant.Font,
+		Size:         psz,
+		ConstantTime: *constTime,
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r, err := analysis.Seed(rng, mjolnir.Millimeter, mjolnir.StrokeWidth, plate, *rounds)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		return fmt.Errorf("engraving depends on the seed: %s", r)
+	}
+	fmt.Printf("%d engravings are identical in timing\n", *rounds+1)
+	return nil
+}
+
+func estimate(cmd engrave.Command) *mjolnir.Estimator {
+	e := &mjolnir.Estimator{DryRun: *dryrun}
+	cmd.Engrave(e)
+	return e
+}
+
+// calibrate fits an engraver timing model to the runs logged
+// in file.
+func calibrate(file string) error {
+	if file == "" {
+		return errors.New("specify a run log with -log")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	runs, err := mjolnir.ReadRuns(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	model, err := mjolnir.Fit(runs)
+	if err != nil {
 		return err
 	}
+	// Print the model as a DefaultModel literal.
+	fmt.Printf("Model{\n\tStep:  %d * time.Nanosecond,\n\tDelay: %d * time.Microsecond,\n\tBatch: %d * time.Millisecond,\n\tSetup: %d * time.Millisecond,\n}\n",
+		model.Step.Nanoseconds(), model.Delay.Microseconds(), model.Batch.Milliseconds(), model.Setup.Milliseconds())
 	return nil
 }
 
-func hammer(side engrave.Command, dev string) error {
-	s, err := mjolnir.Open(dev)
+// logRun appends a completed engraving to the run log.
+func logRun(file string, r mjolnir.Run) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 	if err != nil {
 		return err
 	}
-	defer s.Close()
+	if err := json.NewEncoder(f).Encode(r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// plateBounds returns the bounds of a plate in machine units.
+func plateBounds(size backup.PlateSize) image.Rectangle {
+	mm := size.Bounds()
+	return image.Rect(
+		int(math.Round(float64(mm.Min.X)*mjolnir.Millimeter)), int(math.Round(float64(mm.Min.Y)*mjolnir.Millimeter)),
+		int(math.Round(float64(mm.Max.X)*mjolnir.Millimeter)), int(math.Round(float64(mm.Max.Y)*mjolnir.Millimeter)),
+	)
+}
+
+func gcodeConfig() engrave.GCodeConfig {
+	return engrave.GCodeConfig{
+		FeedRate:   float32(*feedRate),
+		TravelRate: float32(*travelRate),
+		PenDown:    *penDown,
+		PenUp:      *penUp,
+	}
+}
 
-	prog := &mjolnir.Program{
-		DryRun: *dryrun,
+func dump(sideCmd engrave.Command, size backup.PlateSize, keyIdx int, output string) error {
+	mm := size.Bounds()
+	bounds := plateBounds(size)
+	buf := new(bytes.Buffer)
+	var err error
+	switch *format {
+	case "png":
+		const ppmm = 24
+		img := image.NewNRGBA(image.Rectangle{
+			Min: mm.Min.Mul(ppmm),
+			Max: mm.Max.Mul(ppmm),
+		})
+		r := engrave.NewRasterizer(img, img.Bounds(), ppmm/mjolnir.Millimeter, mjolnir.StrokeWidth*ppmm)
+		sideCmd.Engrave(r)
+		r.Rasterize()
+		err = png.Encode(buf, img)
+	case "svg":
+		svg := engrave.NewSVG(buf, bounds, mjolnir.Step, mjolnir.StrokeWidth)
+		sideCmd.Engrave(svg)
+		err = svg.Flush()
+	case "pdf":
+		pdf := engrave.NewPDF(buf, bounds, mjolnir.Step, mjolnir.StrokeWidth)
+		sideCmd.Engrave(pdf)
+		err = pdf.Flush()
+	case "gcode":
+		gcode := engrave.NewGCode(buf, bounds, mjolnir.Step, gcodeConfig())
+		sideCmd.Engrave(gcode)
+		err = gcode.Flush()
+	default:
+		return fmt.Errorf("-format must be 'png', 'svg', 'pdf' or 'gcode'")
 	}
-	side.Engrave(prog)
-	prog.Prepare()
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(output, fmt.Sprintf("plate-%d-side-%s.%s", keyIdx, *side, *format))
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return nil
+}
+
+func hammer(side engrave.Command, size backup.PlateSize, dev string) error {
+	var d driver.Device
+	switch *driverName {
+	case "mjolnir":
+		s, err := mjolnir.Open(dev)
+		if err != nil {
+			return err
+		}
+		d = mjolnir.NewEngraver(s)
+	case "grbl":
+		s, err := grbl.Open(dev)
+		if err != nil {
+			return err
+		}
+		d = grbl.New(s, grbl.Config{
+			Unit:   mjolnir.Step,
+			Bounds: plateBounds(size),
+			GCode:  gcodeConfig(),
+		})
+	default:
+		return fmt.Errorf("-driver must be 'mjolnir' or 'grbl'")
+	}
+	defer d.Close()
+
 	quit := make(chan os.Signal, 1)
 	cancel := make(chan struct{})
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -208,9 +390,18 @@ func hammer(side engrave.Command, dev string) error {
 		<-engraveErr
 		os.Exit(1)
 	}()
+	start := time.Now()
 	go func() {
-		engraveErr <- mjolnir.Engrave(s, prog, nil, cancel)
+		engraveErr <- d.Run(driver.Job{Command: side, DryRun: *dryrun}, nil, cancel)
 	}()
-	side.Engrave(prog)
-	return <-engraveErr
+	if err := <-engraveErr; err != nil {
+		return err
+	}
+	if *runLog == "" || *dryrun || *driverName != "mjolnir" {
+		return nil
+	}
+	return logRun(*runLog, mjolnir.Run{
+		Usage:    estimate(side).Usage(),
+		Duration: time.Since(start),
+	})
 }
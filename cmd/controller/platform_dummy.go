@@ -6,9 +6,11 @@ import (
 	"errors"
 	"image"
 	"image/draw"
-	"io"
 
+	"seedhammer.com/auditlog"
+	"seedhammer.com/driver"
 	"seedhammer.com/gui"
+	"seedhammer.com/settings"
 )
 
 type Platform struct{}
@@ -17,7 +19,7 @@ func Init() (*Platform, error) {
 	return new(Platform), nil
 }
 
-func (p *Platform) Engraver() (io.ReadWriteCloser, error) {
+func (p *Platform) Engraver() (driver.Device, error) {
 	return nil, errors.New("Engraver not implemented")
 }
 
@@ -46,3 +48,31 @@ func (p *Platform) CameraFrame(dims image.Point) {
 func (p *Platform) ScanQR(img *image.Gray) ([][]byte, error) {
 	return nil, errors.New("ScanQR not implemented")
 }
+
+func (p *Platform) LoadSettings() (settings.Settings, error) {
+	return settings.Default(), nil
+}
+
+func (p *Platform) StoreSettings(s settings.Settings) error {
+	return errors.New("StoreSettings not implemented")
+}
+
+func (p *Platform) AppendAudit(entries []auditlog.Entry) error {
+	return errors.New("AppendAudit not implemented")
+}
+
+func (p *Platform) LoadAudit() (auditlog.Log, error) {
+	return auditlog.Log{}, nil
+}
+
+func (p *Platform) ExportAudit() error {
+	return errors.New("ExportAudit not implemented")
+}
+
+func (p *Platform) CheckAuditKey() error {
+	return errors.New("CheckAuditKey not implemented")
+}
+
+func (p *Platform) SetupAuditKey() error {
+	return errors.New("SetupAuditKey not implemented")
+}
@@ -4,6 +4,10 @@ package main
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"image"
 	"image/draw"
@@ -15,11 +19,14 @@ import (
 	"unsafe"
 
 	"golang.org/x/sys/unix"
+	"seedhammer.com/auditlog"
+	"seedhammer.com/driver"
 	"seedhammer.com/driver/drm"
 	"seedhammer.com/driver/libcamera"
 	"seedhammer.com/driver/mjolnir"
 	"seedhammer.com/driver/wshat"
 	"seedhammer.com/gui"
+	"seedhammer.com/settings"
 	"seedhammer.com/zbar"
 )
 
@@ -33,7 +40,10 @@ type Platform struct {
 	display *drm.LCD
 	events  chan gui.Event
 	wakeups chan struct{}
-	camera  struct {
+	audit   struct {
+		key ed25519.PrivateKey
+	}
+	camera struct {
 		frames chan gui.FrameEvent
 		out    chan gui.FrameEvent
 		frame  gui.FrameEvent
@@ -126,11 +136,15 @@ func (p *Platform) NextChunk() (draw.RGBA64Image, bool) {
 	return p.display.NextChunk()
 }
 
-func (p *Platform) Engraver() (io.ReadWriteCloser, error) {
+func (p *Platform) Engraver() (driver.Device, error) {
 	if engraverHook != nil {
-		return engraverHook(), nil
+		return mjolnir.NewEngraver(engraverHook()), nil
 	}
-	return mjolnir.Open("")
+	dev, err := mjolnir.Open("")
+	if err != nil {
+		return nil, err
+	}
+	return mjolnir.NewEngraver(dev), nil
 }
 
 func (p *Platform) ScanQR(img *image.Gray) ([][]byte, error) {
@@ -145,6 +159,226 @@ func (p *Platform) CameraFrame(dims image.Point) {
 	c.active = true
 }
 
+const (
+	// settingsDev is the small writable partition that holds
+	// the settings file.
+	settingsDev  = "/dev/mmcblk0p2"
+	settingsFile = "settings"
+	// The audit log and its export are stored next to the
+	// settings. The signing key is not; see auditKey.
+	auditLogFile    = "audit.log"
+	auditExportFile = "audit.json"
+)
+
+func (p *Platform) LoadSettings() (settings.Settings, error) {
+	var s settings.Settings
+	err := withSettingsFS(func(dir string) error {
+		var err error
+		s, err = settings.Load(filepath.Join(dir, settingsFile))
+		return err
+	})
+	return s, err
+}
+
+func (p *Platform) StoreSettings(s settings.Settings) error {
+	return withSettingsFS(func(dir string) error {
+		return settings.Store(filepath.Join(dir, settingsFile), s)
+	})
+}
+
+func (p *Platform) AppendAudit(entries []auditlog.Entry) error {
+	key, err := p.auditKey()
+	if err != nil {
+		return err
+	}
+	return withSettingsFS(func(dir string) error {
+		return auditlog.Append(filepath.Join(dir, auditLogFile), key, entries...)
+	})
+}
+
+func (p *Platform) LoadAudit() (auditlog.Log, error) {
+	key, err := p.auditKey()
+	if err != nil {
+		return auditlog.Log{}, err
+	}
+	var l auditlog.Log
+	err = withSettingsFS(func(dir string) error {
+		var err error
+		l, err = auditlog.Load(filepath.Join(dir, auditLogFile), key.Public().(ed25519.PublicKey))
+		return err
+	})
+	return l, err
+}
+
+func (p *Platform) ExportAudit() error {
+	key, err := p.auditKey()
+	if err != nil {
+		return err
+	}
+	return withSettingsFS(func(dir string) error {
+		l, err := auditlog.Load(filepath.Join(dir, auditLogFile), key.Public().(ed25519.PublicKey))
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(filepath.Join(dir, auditExportFile))
+		if err != nil {
+			return fmt.Errorf("platform: %w", err)
+		}
+		defer f.Close()
+		if err := auditlog.Export(f, l); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("platform: %w", err)
+		}
+		return f.Close()
+	})
+}
+
+func (p *Platform) CheckAuditKey() error {
+	_, err := p.auditKey()
+	return err
+}
+
+// SetupAuditKey programs the audit key secret into the customer OTP
+// rows of the SoC. Programming OTP is irreversible, so the rows must
+// be blank; rows programmed by other software are left alone.
+func (p *Platform) SetupAuditKey() error {
+	rows, err := customerOTP()
+	if err != nil {
+		return err
+	}
+	switch {
+	case rows[otpTagRow] == otpTag:
+		return nil
+	case rows != [customerOTPRows]uint32{}:
+		return errOTPReserved
+	}
+	var secret [otpTagRow * 4]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return fmt.Errorf("platform: %w", err)
+	}
+	for i := 0; i < otpTagRow; i++ {
+		rows[i] = binary.LittleEndian.Uint32(secret[i*4:])
+	}
+	rows[otpTagRow] = otpTag
+	if err := programCustomerOTP(rows); err != nil {
+		return err
+	}
+	stored, err := customerOTP()
+	if err != nil {
+		return err
+	}
+	if stored != rows {
+		return errors.New("platform: customer OTP programming failed")
+	}
+	return nil
+}
+
+// auditKey returns the audit log signing key. The key is derived
+// from a secret in the customer OTP rows of the SoC, so it never
+// leaves the device and can't be read from the removable SD card.
+// It returns auditlog.ErrNoKey until SetupAuditKey programs the
+// secret.
+func (p *Platform) auditKey() (ed25519.PrivateKey, error) {
+	if p.audit.key != nil {
+		return p.audit.key, nil
+	}
+	rows, err := customerOTP()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case rows == [customerOTPRows]uint32{}:
+		return nil, auditlog.ErrNoKey
+	case rows[otpTagRow] != otpTag:
+		return nil, errOTPReserved
+	}
+	var secret [otpTagRow * 4]byte
+	for i := 0; i < otpTagRow; i++ {
+		binary.LittleEndian.PutUint32(secret[i*4:], rows[i])
+	}
+	p.audit.key = auditlog.DeriveKey(secret[:])
+	return p.audit.key, nil
+}
+
+var errOTPReserved = errors.New("platform: customer OTP is used by other software")
+
+const (
+	// customerOTPRows is the number of 32-bit customer OTP
+	// rows. The audit key secret occupies the rows before
+	// otpTagRow, which holds otpTag to mark the rows as ours.
+	customerOTPRows = 8
+	otpTagRow       = customerOTPRows - 1
+	otpTag          = 0x4c414853 // "SHAL"
+	// Mailbox property tags for reading and programming the
+	// customer OTP rows.
+	tagGetCustomerOTP = 0x00030021
+	tagSetCustomerOTP = 0x00038021
+	// vcioProperty is the IOCTL_MBOX_PROPERTY request of
+	// /dev/vcio, _IOWR(100, 0, char *).
+	vcioProperty = 0xc0046400
+)
+
+func customerOTP() ([customerOTPRows]uint32, error) {
+	var rows [customerOTPRows]uint32
+	resp, err := mailboxProperty(tagGetCustomerOTP, append([]uint32{0, customerOTPRows}, rows[:]...))
+	if err != nil {
+		return rows, err
+	}
+	copy(rows[:], resp[2:])
+	return rows, nil
+}
+
+func programCustomerOTP(rows [customerOTPRows]uint32) error {
+	_, err := mailboxProperty(tagSetCustomerOTP, append([]uint32{0, customerOTPRows}, rows[:]...))
+	return err
+}
+
+// mailboxProperty sends a property tag with values to the
+// VideoCore firmware and returns the response values.
+func mailboxProperty(tag uint32, values []uint32) ([]uint32, error) {
+	f, err := os.OpenFile("/dev/vcio", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("platform: %w", err)
+	}
+	defer f.Close()
+	// Buffer size, request code, tag, value buffer size, tag
+	// request code, values and end tag.
+	buf := make([]uint32, 5+len(values)+1)
+	buf[0] = uint32(len(buf) * 4)
+	buf[2] = tag
+	buf[3] = uint32(len(values) * 4)
+	copy(buf[5:], values)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), vcioProperty, uintptr(unsafe.Pointer(&buf[0]))); errno != 0 {
+		return nil, fmt.Errorf("platform: mailbox: %w", errno)
+	}
+	const responseOK = 0x80000000
+	if buf[1] != responseOK || buf[4]&responseOK == 0 {
+		return nil, fmt.Errorf("platform: mailbox tag %#x failed", tag)
+	}
+	return buf[5 : 5+len(values)], nil
+}
+
+// withSettingsFS mounts the settings partition for the duration
+// of f. The partition is not kept mounted, because the SD card
+// is usually removed after boot.
+func withSettingsFS(f func(dir string) error) (ferr error) {
+	const mntDir = "/config"
+	if err := os.MkdirAll(mntDir, 0o644); err != nil {
+		return fmt.Errorf("platform: %w", err)
+	}
+	if err := syscall.Mount(settingsDev, mntDir, "vfat", unix.MS_NOATIME, ""); err != nil {
+		return fmt.Errorf("platform: mount %s: %w", settingsDev, err)
+	}
+	defer func() {
+		if err := syscall.Unmount(mntDir, 0); ferr == nil && err != nil {
+			ferr = fmt.Errorf("platform: unmount %s: %w", settingsDev, err)
+		}
+	}()
+	return f(mntDir)
+}
+
 func (p *Platform) initSDCardNotifier() error {
 	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
 	if err != nil {
@@ -66,29 +66,25 @@ func engrave(dev string, coords []image.Point) error {
 		PrintSpeed: 0,   // If commented out, use default from mjolnir/driver.go
 		End:        coords[len(coords)-1],
 	}
-	design := func() {
-		for i := 0; i < *repeat; i++ {
-			for _, c := range coords {
-				szf := 2.0 * mjolnir.Millimeter
-				sz := int(szf)
+	for i := 0; i < *repeat; i++ {
+		for _, c := range coords {
+			szf := 2.0 * mjolnir.Millimeter
+			sz := int(szf)
 
-				left := c.Add(image.Pt(-sz, 0))
-				if left.X < 0 {
-					left.X = 0
-				}
-				prog.Move(left)
-				prog.Line(c.Add(image.Pt(+sz, 0)))
-				top := c.Add(image.Pt(0, -sz))
-				if top.Y < 0 {
-					top.Y = 0
-				}
-				prog.Move(top)
-				prog.Line(c.Add(image.Pt(0, +sz)))
+			left := c.Add(image.Pt(-sz, 0))
+			if left.X < 0 {
+				left.X = 0
 			}
+			prog.Move(left)
+			prog.Line(c.Add(image.Pt(+sz, 0)))
+			top := c.Add(image.Pt(0, -sz))
+			if top.Y < 0 {
+				top.Y = 0
+			}
+			prog.Move(top)
+			prog.Line(c.Add(image.Pt(0, +sz)))
 		}
 	}
-	design()
-	prog.Prepare()
 	quit := make(chan os.Signal, 1)
 	cancel := make(chan struct{})
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -100,9 +96,20 @@ func engrave(dev string, coords []image.Point) error {
 		<-engraveErr
 		os.Exit(1)
 	}()
+	d := mjolnir.NewDevice(s)
 	go func() {
-		engraveErr <- mjolnir.Engrave(s, prog, nil, cancel)
+		engraveErr <- d.Engrave(prog, nil, cancel)
 	}()
-	design()
-	return <-engraveErr
+	if err := <-engraveErr; err != nil {
+		return err
+	}
+	// Sanity check that the needle returned to the end point.
+	pos, err := d.Position()
+	if err != nil {
+		return err
+	}
+	if pos != prog.End {
+		return fmt.Errorf("needle at %v after engraving, expected %v", pos, prog.End)
+	}
+	return nil
 }
@@ -0,0 +1,134 @@
+// package driver defines the interface to engraving machines.
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"seedhammer.com/engrave"
+)
+
+// Device is a connection to an engraving machine. Positions are
+// in machine units.
+type Device interface {
+	// Home moves the needle to the origin of the machine.
+	Home() error
+	// Run engraves job. Progress, if not nil, receives the
+	// fraction of the job completed, and closing cancel aborts
+	// the engraving with ErrCancelled.
+	Run(job Job, progress chan float32, cancel <-chan struct{}) error
+	// Position queries the position of the needle.
+	Position() (image.Point, error)
+	Close() error
+}
+
+// Job describes an engraving.
+type Job struct {
+	Command engrave.Command
+	// DryRun replaces lines with moves.
+	DryRun bool
+	// MoveSpeed and PrintSpeed are the speeds from 0 (lowest)
+	// to 1 (highest) for devices that support them. Zero
+	// selects the device default.
+	MoveSpeed  float32
+	PrintSpeed float32
+	// Resume is the number of instructions of Command to skip,
+	// typically from an InterruptedError.
+	Resume int
+}
+
+var ErrCancelled = errors.New("cancelled")
+
+// Parker is implemented by devices that park the needle at a
+// known position after completing a job.
+type Parker interface {
+	ParkPosition() image.Point
+}
+
+// PositionError reports a needle found at an unexpected position.
+type PositionError struct {
+	Want, Got image.Point
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("needle at %v, expected %v", e.Got, e.Want)
+}
+
+// CheckParked verifies that the needle of a Parker is at its
+// park position. It does nothing for other devices.
+func CheckParked(d Device) error {
+	p, ok := d.(Parker)
+	if !ok {
+		return nil
+	}
+	pos, err := d.Position()
+	if err != nil {
+		return err
+	}
+	if want := p.ParkPosition(); pos != want {
+		return &PositionError{Want: want, Got: pos}
+	}
+	return nil
+}
+
+// InterruptedError is returned by Run when a job fails after
+// instructions were acknowledged by the machine.
+type InterruptedError struct {
+	// Completed is the number of instructions of the job
+	// acknowledged, counting from the start of the job even if
+	// it was resumed.
+	Completed int
+	Err       error
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted after %d instructions: %v", e.Completed, e.Err)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// Resume returns a program that skips the first n instructions
+// sent to it. Before the first instruction that is not skipped,
+// it moves to the position of the last skipped instruction.
+func Resume(p engrave.Program, n int) engrave.Program {
+	if n == 0 {
+		return p
+	}
+	return &resumeProgram{prog: p, skip: n}
+}
+
+type resumeProgram struct {
+	prog engrave.Program
+	skip int
+	pos  image.Point
+}
+
+func (r *resumeProgram) Move(p image.Point) {
+	if r.skipped(p) {
+		return
+	}
+	r.prog.Move(p)
+}
+
+func (r *resumeProgram) Line(p image.Point) {
+	if r.skipped(p) {
+		return
+	}
+	r.prog.Line(p)
+}
+
+func (r *resumeProgram) skipped(p image.Point) bool {
+	switch {
+	case r.skip > 0:
+		r.skip--
+		r.pos = p
+		return true
+	case r.skip == 0:
+		r.skip--
+		r.prog.Move(r.pos)
+	}
+	return false
+}
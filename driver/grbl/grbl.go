@@ -0,0 +1,285 @@
+// package grbl implements a driver for GRBL based engravers, such
+// as CNC machines and diode lasers.
+package grbl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tarm/serial"
+	"seedhammer.com/driver"
+	"seedhammer.com/engrave"
+)
+
+// Config describes the machine.
+type Config struct {
+	// Unit is the size of a machine unit in millimeters.
+	Unit float32
+	// Bounds is the work area, in machine units. The GRBL y
+	// axis points up from Bounds.Max.Y.
+	Bounds image.Rectangle
+	// GCode configures feed rates and tool macros.
+	GCode engrave.GCodeConfig
+	// Planner is the size of the planner buffer, the number of
+	// lines the controller acknowledges before executing them.
+	// Zero means the GRBL default.
+	Planner int
+}
+
+// defaultPlanner is the size of the planner buffer of GRBL 1.1 for
+// the ATmega328p.
+const defaultPlanner = 16
+
+// Device implements driver.Device for a GRBL controller.
+type Device struct {
+	dev    io.ReadWriteCloser
+	r      *bufio.Reader
+	config Config
+}
+
+// Open a serial connection to a GRBL controller.
+func Open(dev string) (io.ReadWriteCloser, error) {
+	const baudRate = 115200
+	return serial.OpenPort(&serial.Config{Name: dev, Baud: baudRate})
+}
+
+// New creates a Device that communicates through dev.
+func New(dev io.ReadWriteCloser, config Config) *Device {
+	return &Device{
+		dev:    dev,
+		r:      bufio.NewReader(dev),
+		config: config,
+	}
+}
+
+// Error is an error reported by the controller.
+type Error struct {
+	// Line is the G-code that failed, if any.
+	Line  string
+	Reply string
+}
+
+func (e *Error) Error() string {
+	if e.Line == "" {
+		return fmt.Sprintf("grbl: %s", e.Reply)
+	}
+	return fmt.Sprintf("grbl: %q: %s", e.Line, e.Reply)
+}
+
+// softReset is the real-time command for resetting the controller.
+const softReset = 0x18
+
+// send a line of G-code and wait for its acknowledgement.
+func (d *Device) send(line string) error {
+	if _, err := io.WriteString(d.dev, line+"\n"); err != nil {
+		return err
+	}
+	for {
+		reply, err := d.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case reply == "ok":
+			return nil
+		case strings.HasPrefix(reply, "error:"), strings.HasPrefix(reply, "ALARM:"):
+			return &Error{Line: line, Reply: reply}
+		}
+		// Ignore welcome messages, feedback and status reports.
+	}
+}
+
+func (d *Device) readLine() (string, error) {
+	line, err := d.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Home runs the homing cycle.
+func (d *Device) Home() error {
+	return d.send("$H")
+}
+
+func (d *Device) Run(job driver.Job, progress chan float32, cancel <-chan struct{}) error {
+	buf := new(bytes.Buffer)
+	gcode := engrave.NewGCode(buf, d.config.Bounds, d.config.Unit, d.config.GCode)
+	var prog engrave.Program = gcode
+	if job.DryRun {
+		prog = dryRun{gcode}
+	}
+	// Record the G-code lines of every instruction, for
+	// reporting the acknowledged instructions.
+	marks := &markProgram{prog: driver.Resume(prog, job.Resume), gcode: gcode}
+	job.Command.Engrave(marks)
+	if err := gcode.Flush(); err != nil {
+		return err
+	}
+	if job.Resume > 0 {
+		// Re-establish the machine position lost in the
+		// interruption.
+		if err := d.Home(); err != nil {
+			return err
+		}
+	}
+	planner := d.config.Planner
+	if planner == 0 {
+		planner = defaultPlanner
+	}
+	// Wait for the final motions before reporting completion.
+	lines := strings.Split(buf.String()+syncLine, "\n")
+	// synced is the number of lines known to be executed, because
+	// a later dwell was acknowledged.
+	synced := 0
+	// motions are the acknowledged motion lines after synced.
+	var motions []int
+	for i, l := range lines {
+		var err error
+		select {
+		case <-cancel:
+			// Stop motion and clear the planner buffer.
+			d.dev.Write([]byte{softReset})
+			err = driver.ErrCancelled
+		default:
+			err = d.send(l)
+		}
+		if err != nil {
+			// The most recently acknowledged motions may still be
+			// waiting in the planner buffer, and are lost in a
+			// reset or power loss.
+			executed := synced
+			if n := len(motions); n > planner {
+				executed = motions[n-planner-1] + 1
+			}
+			// The marks include the skipped instructions, which
+			// were completed before the job was resumed.
+			completed := sort.SearchInts(marks.lines, executed+1)
+			if completed < job.Resume {
+				completed = job.Resume
+			}
+			return &driver.InterruptedError{
+				Completed: completed,
+				Err:       err,
+			}
+		}
+		switch {
+		case hasWord(l, "G4"):
+			synced = i + 1
+			motions = motions[:0]
+		case hasWord(l, "G0"), hasWord(l, "G1"):
+			motions = append(motions, i)
+		}
+		if progress != nil {
+			select {
+			case <-progress:
+			default:
+			}
+			progress <- float32(i+1) / float32(len(lines))
+		}
+	}
+	return nil
+}
+
+// syncLine is a dwell without delay. GRBL acknowledges a dwell
+// only after executing the motions before it.
+const syncLine = "G4 P0"
+
+// hasWord reports whether a G-code line contains word.
+func hasWord(line, word string) bool {
+	for _, w := range strings.Fields(line) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// markProgram records the number of G-code lines after
+// every instruction.
+type markProgram struct {
+	prog  engrave.Program
+	gcode *engrave.GCode
+	lines []int
+}
+
+func (m *markProgram) Move(p image.Point) {
+	m.prog.Move(p)
+	m.lines = append(m.lines, m.gcode.Lines())
+}
+
+func (m *markProgram) Line(p image.Point) {
+	m.prog.Line(p)
+	m.lines = append(m.lines, m.gcode.Lines())
+}
+
+// dryRun replaces lines with moves.
+type dryRun struct {
+	engrave.Program
+}
+
+func (d dryRun) Line(p image.Point) {
+	d.Program.Move(p)
+}
+
+// Position queries the machine position of the tool.
+func (d *Device) Position() (image.Point, error) {
+	// The status report query is a real-time command and
+	// needs no newline.
+	if _, err := d.dev.Write([]byte{'?'}); err != nil {
+		return image.Point{}, err
+	}
+	for {
+		reply, err := d.readLine()
+		if err != nil {
+			return image.Point{}, err
+		}
+		if !strings.HasPrefix(reply, "<") {
+			continue
+		}
+		x, y, err := parseStatus(reply)
+		if err != nil {
+			return image.Point{}, err
+		}
+		cfg := d.config
+		return image.Point{
+			X: cfg.Bounds.Min.X + int(math.Round(x/float64(cfg.Unit))),
+			Y: cfg.Bounds.Max.Y - int(math.Round(y/float64(cfg.Unit))),
+		}, nil
+	}
+}
+
+// parseStatus extracts the machine position from a status
+// report such as <Idle|MPos:1.000,2.000,0.000|FS:0,0>.
+func parseStatus(report string) (x, y float64, err error) {
+	report = strings.TrimSuffix(strings.TrimPrefix(report, "<"), ">")
+	for _, field := range strings.Split(report, "|") {
+		pos, ok := strings.CutPrefix(field, "MPos:")
+		if !ok {
+			continue
+		}
+		coords := strings.Split(pos, ",")
+		if len(coords) < 2 {
+			break
+		}
+		x, err1 := strconv.ParseFloat(coords[0], 64)
+		y, err2 := strconv.ParseFloat(coords[1], 64)
+		if err := errors.Join(err1, err2); err != nil {
+			return 0, 0, fmt.Errorf("grbl: status report %q: %w", report, err)
+		}
+		return x, y, nil
+	}
+	return 0, 0, fmt.Errorf("grbl: status report without position: %q", report)
+}
+
+func (d *Device) Close() error {
+	return d.dev.Close()
+}
@@ -0,0 +1,178 @@
+package grbl
+
+import (
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+
+	"seedhammer.com/driver"
+	"seedhammer.com/engrave"
+)
+
+var testConfig = Config{
+	Unit:   0.01,
+	Bounds: image.Rect(0, 0, 10000, 10000),
+	GCode:  engrave.DefaultGCode,
+}
+
+func TestRun(t *testing.T) {
+	s := NewSimulator()
+	d := New(s, testConfig)
+	defer d.Close()
+
+	if err := d.Home(); err != nil {
+		t.Fatal(err)
+	}
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+	}
+	progress := make(chan float32, 1)
+	if err := d.Run(job, progress, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p := <-progress; p != 1 {
+		t.Errorf("final progress %v, want 1", p)
+	}
+	lines := 0
+	for _, c := range s.Cmds {
+		if c.Type == LineTo {
+			lines++
+		}
+	}
+	if lines == 0 {
+		t.Error("job engraved no lines")
+	}
+	last := s.Cmds[len(s.Cmds)-1]
+	pos, err := d.Position()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := image.Pt(int(last.X/0.01+.5), 10000-int(last.Y/0.01+.5))
+	if pos != want {
+		t.Errorf("got position %v, want %v", pos, want)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	s := NewSimulator()
+	d := New(s, testConfig)
+	defer d.Close()
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+		DryRun:  true,
+	}
+	if err := d.Run(job, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range s.Cmds {
+		if c.Type == LineTo {
+			t.Fatal("dry run engraved a line")
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	s := NewSimulator()
+	cfg := testConfig
+	cfg.GCode.PenDown = "M7"
+	d := New(s, cfg)
+	defer d.Close()
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+	}
+	err := d.Run(job, nil, nil)
+	var gerr *Error
+	if !errors.As(err, &gerr) || gerr.Line != "M7" {
+		t.Fatalf("got error %v for an unsupported command", err)
+	}
+}
+
+func TestResumeError(t *testing.T) {
+	s := NewSimulator()
+	cfg := testConfig
+	cfg.GCode.PenDown = "M7"
+	d := New(s, cfg)
+	defer d.Close()
+	// Resume after the initial move and two lines, and fail
+	// at the pen down of the third.
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+		Resume:  3,
+	}
+	err := d.Run(job, nil, nil)
+	var ierr *driver.InterruptedError
+	if !errors.As(err, &ierr) || ierr.Completed != job.Resume {
+		t.Fatalf("got error %v, want %d completed instructions", err, job.Resume)
+	}
+}
+
+func TestPlannedDisconnect(t *testing.T) {
+	const planner = 15
+	s := NewSimulator()
+	s.Planner = planner
+	s.Disconnect = 60
+	cfg := testConfig
+	cfg.Planner = planner
+	// Pen macros without dwells, to keep motions in the
+	// planner.
+	cfg.GCode.PenDown = "M3 S90"
+	cfg.GCode.PenUp = "M3 S30"
+	d := New(s, cfg)
+	defer d.Close()
+	var rects engrave.Commands
+	for i := 0; i < 10; i++ {
+		off := image.Pt(i*400, 0)
+		rects = append(rects, engrave.Rect{Min: off.Add(image.Pt(100, 200)), Max: off.Add(image.Pt(300, 500))})
+	}
+	err := d.Run(driver.Job{Command: rects}, nil, nil)
+	var ierr *driver.InterruptedError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("got error %v, want an interruption", err)
+	}
+	// Every instruction is a single motion.
+	if executed := len(s.Cmds); ierr.Completed != executed || executed == 0 {
+		t.Errorf("%d instructions reported completed, %d executed", ierr.Completed, executed)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	s := NewSimulator()
+	d := New(s, testConfig)
+	defer d.Close()
+	cancel := make(chan struct{})
+	close(cancel)
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+	}
+	if err := d.Run(job, nil, cancel); !errors.Is(err, driver.ErrCancelled) {
+		t.Fatalf("got error %v, want %v", err, driver.ErrCancelled)
+	}
+}
+
+func TestResume(t *testing.T) {
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 200), Max: image.Pt(300, 500)},
+	}
+	full := NewSimulator()
+	if err := New(full, testConfig).Run(job, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Skip the initial move and the first line.
+	job.Resume = 2
+	s := NewSimulator()
+	d := New(s, testConfig)
+	defer d.Close()
+	if err := d.Run(job, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Lines) == 0 || s.Lines[0] != "$H" {
+		t.Errorf("resumed job didn't home first: %q", s.Lines)
+	}
+	// The resumed job homes and moves to the end of the
+	// first line before engraving the rest.
+	want := append([]Cmd{{MoveTo, 0, 0}, {MoveTo, full.Cmds[1].X, full.Cmds[1].Y}}, full.Cmds[2:]...)
+	if !reflect.DeepEqual(s.Cmds, want) {
+		t.Errorf("resumed job motions:\n%v\nwant:\n%v", s.Cmds, want)
+	}
+}
@@ -0,0 +1,196 @@
+package grbl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Simulator simulates a GRBL controller.
+type Simulator struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	in     []byte
+	out    bytes.Buffer
+	closed bool
+	x, y   float64
+
+	// Cmds are the executed motions, in millimeters.
+	Cmds []Cmd
+	// Lines are the received lines of G-code.
+	Lines []string
+	// Planner is the number of acknowledged motions that wait in
+	// the simulated planner buffer before they are executed.
+	Planner int
+	// Disconnect is the number of received lines after which the
+	// connection and power are lost, or zero.
+	Disconnect int
+	// planned are the motions waiting for execution.
+	planned []Cmd
+}
+
+type Cmd struct {
+	Type CmdType
+	X, Y float64
+}
+
+type CmdType int
+
+const (
+	MoveTo CmdType = iota
+	LineTo
+)
+
+const welcome = "Grbl 1.1h ['$' for help]\r\n"
+
+func NewSimulator() *Simulator {
+	s := new(Simulator)
+	s.cond = sync.NewCond(&s.mu)
+	s.out.WriteString(welcome)
+	return s
+}
+
+func (s *Simulator) Read(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.out.Len() == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return 0, io.EOF
+	}
+	return s.out.Read(data)
+}
+
+func (s *Simulator) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, errors.New("grbl: simulator closed")
+	}
+	for _, b := range data {
+		switch b {
+		case '?':
+			fmt.Fprintf(&s.out, "<Idle|MPos:%.3f,%.3f,0.000|FS:0,0>\r\n", s.x, s.y)
+		case softReset:
+			s.in = s.in[:0]
+			s.planned = nil
+			s.out.WriteString(welcome)
+		case '\n':
+			if s.Disconnect > 0 && len(s.Lines) == s.Disconnect {
+				// The planned motions are lost with the power.
+				s.closed = true
+				s.planned = nil
+				s.cond.Broadcast()
+				return 0, errors.New("grbl: simulated disconnect")
+			}
+			line := strings.TrimSpace(string(s.in))
+			s.in = s.in[:0]
+			s.Lines = append(s.Lines, line)
+			if err := s.execute(line); err != nil {
+				fmt.Fprintf(&s.out, "error:%s\r\n", err)
+			} else {
+				s.out.WriteString("ok\r\n")
+			}
+		default:
+			s.in = append(s.in, b)
+		}
+	}
+	s.cond.Broadcast()
+	return len(data), nil
+}
+
+// Errors codes from the GRBL documentation.
+var (
+	errUnsupported  = errors.New("20")
+	errInvalidValue = errors.New("2")
+)
+
+func (s *Simulator) execute(line string) error {
+	if line == "" {
+		return nil
+	}
+	if line == "$H" {
+		s.sync()
+		s.x, s.y = 0, 0
+		s.Cmds = append(s.Cmds, Cmd{MoveTo, 0, 0})
+		return nil
+	}
+	words := strings.Fields(line)
+	var motion CmdType = -1
+	dwell := false
+	x, y := s.x, s.y
+	if n := len(s.planned); n > 0 {
+		last := s.planned[n-1]
+		x, y = last.X, last.Y
+	}
+	for _, w := range words {
+		v, err := strconv.ParseFloat(w[1:], 64)
+		if err != nil {
+			return errInvalidValue
+		}
+		switch w[0] {
+		case 'G':
+			switch v {
+			case 0:
+				motion = MoveTo
+			case 1:
+				motion = LineTo
+			case 4:
+				dwell = true
+			case 21, 90:
+			default:
+				return errUnsupported
+			}
+		case 'M':
+			switch v {
+			case 2, 3, 5:
+			default:
+				return errUnsupported
+			}
+		case 'X':
+			x = v
+		case 'Y':
+			y = v
+		case 'F', 'S', 'P':
+		default:
+			return errUnsupported
+		}
+	}
+	if dwell {
+		s.sync()
+	}
+	if motion != -1 {
+		s.planned = append(s.planned, Cmd{motion, x, y})
+		if len(s.planned) > s.Planner {
+			s.exec(s.planned[0])
+			s.planned = s.planned[1:]
+		}
+	}
+	return nil
+}
+
+// sync executes the planned motions.
+func (s *Simulator) sync() {
+	for _, c := range s.planned {
+		s.exec(c)
+	}
+	s.planned = nil
+}
+
+func (s *Simulator) exec(c Cmd) {
+	s.x, s.y = c.X, c.Y
+	s.Cmds = append(s.Cmds, c)
+}
+
+func (s *Simulator) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+	return nil
+}
@@ -0,0 +1,316 @@
+package mjolnir
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+)
+
+// Device speaks the serial protocol of the engraver. Its methods
+// must not be called concurrently, except for Cancel.
+type Device struct {
+	dev io.ReadWriter
+	// mu guards writes to dev.
+	mu    sync.Mutex
+	w     *bufio.Writer
+	r     *bufio.Reader
+	homed bool
+}
+
+// NewDevice creates a Device that communicates through dev.
+func NewDevice(dev io.ReadWriter) *Device {
+	return &Device{
+		dev: dev,
+		w:   bufio.NewWriterSize(dev, progBatchSize*cmdSize),
+		r:   bufio.NewReaderSize(dev, 100),
+	}
+}
+
+// nopBatch is a batch of padding commands.
+var nopBatch = func() (b [progBatchSize * cmdSize]byte) {
+	for i := range b {
+		b[i] = nopCmd
+	}
+	return
+}()
+
+// speedExtra is the undocumented third parameter of the set
+// speed command.
+const speedExtra = 0xe6
+
+func (d *Device) write(data ...byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.w.Write(data)
+	return err
+}
+
+func (d *Device) flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.w.Flush()
+}
+
+// read flushes buffered commands and reads at most n bytes.
+func (d *Device) read(n int) ([]byte, error) {
+	if err := d.flush(); err != nil {
+		return nil, err
+	}
+	data := make([]byte, n)
+	n, err := d.r.Read(data)
+	if err == nil && n == 0 {
+		err = io.ErrNoProgress
+	}
+	return data[:n], err
+}
+
+func (d *Device) expect(exp ...byte) error {
+	for len(exp) > 0 {
+		got, err := d.read(len(exp))
+		if err != nil {
+			return err
+		}
+		n := len(got)
+		if !bytes.Equal(exp[:n], got) {
+			return fmt.Errorf("unexpected reply\nexp: %#x\ngot: %#x", exp, got)
+		}
+		exp = exp[n:]
+	}
+	return nil
+}
+
+// Cancel the running program. Unlike the other methods, Cancel
+// may be called concurrently.
+func (d *Device) Cancel() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.dev.Write([]byte{cancelCmd})
+	return err
+}
+
+// Init cancels any running program and initializes the engraver.
+func (d *Device) Init() error {
+	d.homed = false
+	if err := d.write(cancelCmd, initCmd); err != nil {
+		return err
+	}
+	for {
+		status, err := d.read(1)
+		if err != nil {
+			return err
+		}
+		switch status[0] {
+		case initializedStatus:
+			return nil
+		case cancelledStatus:
+			// Re-initialize.
+			if err := d.write(initCmd); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Home moves the needle to the origin and resets the machine
+// position.
+func (d *Device) Home() error {
+	if err := d.write(moveToOriginCmd, moveToOriginCmdExtra); err != nil {
+		return err
+	}
+	if err := d.expect(moveToOriginCmd, moveToOriginCmdResponse); err != nil {
+		return err
+	}
+	d.homed = true
+	return nil
+}
+
+// Homed reports whether Home completed since the engraver was
+// initialized.
+func (d *Device) Homed() bool {
+	return d.homed
+}
+
+// SetSpeeds sets the speed of lines and moves, in the range
+// [1000,30] from slowest to fastest.
+func (d *Device) SetSpeeds(print, move int) error {
+	err := d.write(setSpeedCmd,
+		byte(print), byte(print>>8),
+		byte(move), byte(move>>8),
+		byte(speedExtra), byte(speedExtra>>8),
+	)
+	if err != nil {
+		return err
+	}
+	return d.expect(setSpeedCmd)
+}
+
+// SetDelays sets the delays for lowering and raising the needle,
+// in the range [0,255].
+func (d *Device) SetDelays(penDown, penUp int) error {
+	if err := d.write(setDelaysCmd, byte(penDown), byte(penUp)); err != nil {
+		return err
+	}
+	return d.expect(setDelaysCmd)
+}
+
+// Position queries the position of the needle.
+func (d *Device) Position() (image.Point, error) {
+	if err := d.write(queryPosCmd); err != nil {
+		return image.Point{}, err
+	}
+	if err := d.expect(queryPosCmd); err != nil {
+		return image.Point{}, err
+	}
+	var coords [9]byte
+	if _, err := io.ReadFull(d.r, coords[:]); err != nil {
+		return image.Point{}, err
+	}
+	x, y := coordsFromCmd(coords[:])
+	return image.Pt(int(x), int(y)), nil
+}
+
+// MoveTo moves the needle to p, by running a single move program.
+func (d *Device) MoveTo(p image.Point) error {
+	move := new(Program)
+	move.Move(p)
+	return d.Run(move, nil)
+}
+
+// Run streams the commands of a program to the engraver, one
+// batch at a time. Progress, if not nil, receives the fraction of
+// the commands completed.
+func (d *Device) Run(p *Program, progress chan float32) error {
+	p.sent = 0
+	// Round up to nearest batch size. Note that the rounding
+	// adds another, empty, batch in case we fill up the last one.
+	// Otherwise, the engraver won't send a completed status.
+	count := p.Len()
+	nbatches := (count + progBatchSize) / progBatchSize
+	if nbatches > 0xffff {
+		return errors.New("engrave: program too large")
+	}
+	if err := d.write(initProgramCmd, byte(nbatches), byte(nbatches>>8)); err != nil {
+		return err
+	}
+	paddedCount := nbatches * progBatchSize
+	completed := 0
+	for {
+		status, err := d.read(1)
+		if err != nil {
+			return err
+		}
+		switch status[0] {
+		case bufferProgramStatus:
+			if p.sent == paddedCount {
+				break
+			}
+			ncmd := progBatchSize
+			if rem := count - p.sent; ncmd > rem {
+				ncmd = rem
+			}
+			if ncmd > 0 {
+				batch := p.cmds[p.sent*cmdSize : (p.sent+ncmd)*cmdSize]
+				p.sent += ncmd
+				if err := d.write(batch...); err != nil {
+					return err
+				}
+			}
+			// Pad with 0xff.
+			npad := progBatchSize - ncmd
+			p.sent += npad
+			if err := d.write(nopBatch[:npad*cmdSize]...); err != nil {
+				return err
+			}
+		case programStepStatus:
+			completed++
+			p.acked = completed
+			if progress == nil {
+				break
+			}
+			// Don't spam the progress channel.
+			if completed%10 != 0 && completed < paddedCount {
+				break
+			}
+			select {
+			case <-progress:
+			default:
+			}
+			progress <- float32(completed) / float32(paddedCount)
+		case programCompleteStatus:
+			return nil
+		case cancellingStatus:
+		case cancelledStatus:
+			return ErrCancelled
+		default:
+			return fmt.Errorf("unexpected status: %#x", status[0])
+		}
+	}
+}
+
+// Engrave a program, framed by the setup and parking
+// moves. Closing quit cancels the program.
+func (d *Device) Engrave(prog *Program, progress chan float32, quit <-chan struct{}) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-quit:
+			d.Cancel()
+		case <-done:
+		}
+	}()
+	if err := d.Init(); err != nil {
+		return err
+	}
+	if err := d.SetDelays(penDelay, penDelay); err != nil {
+		return err
+	}
+	if err := d.SetSpeeds(setupSpeed, setupSpeed); err != nil {
+		return err
+	}
+	// Prepare the machine: (1) reset the origin and
+	// (2) move to safe point. The first is necessary because
+	// the absolute position of the needle is not known at startup.
+	// The second is to avoid needle collision with the tightening
+	// nuts.
+	if err := d.Home(); err != nil {
+		return err
+	}
+	// Avoid a false home by moving out and re-homing.
+	falseHome := float32(5 * Millimeter)
+	if err := d.MoveTo(image.Pt(int(falseHome), int(falseHome))); err != nil {
+		return err
+	}
+	if err := d.Home(); err != nil {
+		return err
+	}
+	sp := SafePoint()
+	if err := d.MoveTo(sp); err != nil {
+		return err
+	}
+
+	mms, mps := prog.speeds()
+	if err := d.SetSpeeds(mps, mms); err != nil {
+		return err
+	}
+	// Park the needle, even if the program was cancelled.
+	rerr := d.Run(prog, progress)
+	if rerr != nil && rerr != ErrCancelled {
+		return rerr
+	}
+	if err := d.SetSpeeds(setupSpeed, setupSpeed); err != nil {
+		return err
+	}
+	end := prog.End
+	if end == (image.Point{}) {
+		end = sp
+	}
+	if err := d.MoveTo(end); err != nil {
+		return err
+	}
+	return rerr
+}
@@ -3,8 +3,6 @@
 package mjolnir
 
 import (
-	"bufio"
-	"bytes"
 	"errors"
 	"fmt"
 	"image"
@@ -12,6 +10,7 @@ import (
 	"runtime"
 
 	"github.com/tarm/serial"
+	"seedhammer.com/driver"
 )
 
 type Program struct {
@@ -19,9 +18,12 @@ type Program struct {
 	MoveSpeed  float32
 	PrintSpeed float32
 	End        image.Point
-	cmds       chan [cmdSize]byte
-	count      int
-	sent       int
+	// cmds are the recorded commands, cmdSize bytes each.
+	cmds []byte
+	sent int
+	// acked is the number of commands acknowledged
+	// by the engraver.
+	acked int
 }
 
 const (
@@ -40,6 +42,12 @@ const (
 
 	defaultMoveSpeed  = .5
 	defaultPrintSpeed = .1
+
+	// setupSpeed is the speed value for moves outside programs.
+	setupSpeed = 300
+	// penDelay is the delay value for lowering and raising the
+	// needle.
+	penDelay = 0x14
 )
 
 func Open(dev string) (io.ReadWriteCloser, error) {
@@ -95,6 +103,7 @@ const (
 	moveCmd                 = 0x80
 	lineCmd                 = 0x00
 	nopCmd                  = 0xff
+	queryPosCmd             = 0x16
 )
 
 const (
@@ -109,260 +118,107 @@ const (
 // The engraver expects program commands in batches.
 const progBatchSize = 80
 
-func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-chan struct{}) (eerr error) {
-	bufw := bufio.NewWriterSize(dev, progBatchSize*cmdSize)
-	defer func() {
-		for i := prog.sent; i < prog.count; i++ {
-			<-prog.cmds
-		}
-	}()
-	writeMut := make(chan struct{}, 1)
-	writeMut <- struct{}{}
-	flush := func() {
-		<-writeMut
-		defer func() { writeMut <- struct{}{} }()
-		if eerr != nil {
-			return
-		}
-		eerr = bufw.Flush()
-	}
-	defer flush()
-	wr := func(data ...byte) {
-		<-writeMut
-		defer func() { writeMut <- struct{}{} }()
-		if eerr != nil {
-			return
-		}
-		_, eerr = bufw.Write(data)
-	}
-	done := make(chan struct{})
-	defer close(done)
-	go func() {
-		select {
-		case <-quit:
-			select {
-			case <-writeMut:
-			case <-done:
-				return
-			}
-			dev.Write([]byte{cancelCmd})
-			writeMut <- struct{}{}
-			<-done
-		case <-done:
-		}
-	}()
-	bufr := bufio.NewReaderSize(dev, 100)
-	r := func(c int) []byte {
-		flush()
-		if eerr != nil {
-			return nil
-		}
-		data := make([]byte, c)
-		n, err := bufr.Read(data)
-		eerr = err
-		data = data[:n]
-		return data
-	}
-	expect := func(exp ...byte) {
-		for len(exp) > 0 && eerr == nil {
-			got := r(len(exp))
-			n := len(got)
-			if !bytes.Equal(exp[:n], got) {
-				eerr = fmt.Errorf("unexpected reply\nexp: %#x\ngot: %#x", exp, got)
-				return
-			}
-			exp = exp[n:]
-		}
-	}
-	atleast := func(n int) []byte {
-		var res []byte
-		for n > 0 {
-			data := r(n)
-			res = append(res, data...)
-			n -= len(data)
-		}
-		return res
-	}
-	origin := func() {
-		wr(moveToOriginCmd, moveToOriginCmdExtra)
-		expect(moveToOriginCmd, moveToOriginCmdResponse)
+// Engrave prog on the engraver connected through dev. It is a
+// shorthand for NewDevice(dev).Engrave(prog, progress, quit).
+func Engrave(dev io.ReadWriter, prog *Program, progress chan float32, quit <-chan struct{}) error {
+	return NewDevice(dev).Engrave(prog, progress, quit)
+}
+
+var ErrCancelled = driver.ErrCancelled
+
+// Engraver implements driver.Device for a MarkingWay engraver.
+type Engraver struct {
+	dev    io.ReadWriteCloser
+	device *Device
+}
+
+// NewEngraver creates an Engraver that communicates through
+// dev, which is typically the result of Open.
+func NewEngraver(dev io.ReadWriteCloser) *Engraver {
+	return &Engraver{dev: dev, device: NewDevice(dev)}
+}
+
+// Home initializes the engraver and moves the needle to the
+// origin.
+func (e *Engraver) Home() error {
+	if err := e.device.Init(); err != nil {
+		return err
 	}
-	cancel := func() {
-		wr(cancelCmd)
+	return e.device.Home()
+}
+
+func (e *Engraver) Run(job driver.Job, progress chan float32, cancel <-chan struct{}) error {
+	prog := &Program{
+		DryRun:     job.DryRun,
+		MoveSpeed:  job.MoveSpeed,
+		PrintSpeed: job.PrintSpeed,
 	}
-	initialize := func() {
-		cancel()
-		wr(initCmd)
-		for {
-			status := r(1)
-			if eerr != nil {
-				break
-			}
-			switch status[0] {
-			case initializedStatus:
-				return
-			case cancelledStatus:
-				// Re-initialize.
-				wr(initCmd)
-			}
-		}
+	job.Command.Engrave(driver.Resume(prog, job.Resume))
+	err := e.device.Engrave(prog, progress, cancel)
+	if err == nil {
+		return nil
 	}
-	parseCoords := func(coords []byte) (x int, y int, z int) {
-		x = int(coords[0]) | int(coords[1])<<8 | int(coords[2])<<16
-		y = int(coords[3]) | int(coords[4])<<8 | int(coords[5])<<16
-		z = int(coords[6]) | int(coords[7])<<8 | int(coords[8])<<16
-		return
+	completed := prog.acked
+	remaining := prog.Len()
+	if job.Resume > 0 {
+		// Don't count the move to the resume position.
+		completed--
+		remaining--
 	}
-	queryPos := func() (x int, y int, z int) {
-		wr(0x16)
-		expect(0x16)
-		x, y, z = parseCoords(atleast(9))
-		return
+	// Don't count padding.
+	if completed > remaining {
+		completed = remaining
 	}
-	_, _ = atleast, queryPos
-
-	initialize()
-
-	// Speed range: [1000,30].
-	setSpeeds := func(print, move, xxx int) {
-		wr(setSpeedCmd, byte(print), byte(print>>8), byte(move), byte(move>>8), byte(xxx), byte(xxx>>8))
-		expect(setSpeedCmd)
+	if completed < 0 {
+		completed = 0
 	}
-
-	// Delay range: 0-255.
-	setDelays := func(penDown, penUp int) {
-		wr(setDelaysCmd, byte(penDown), byte(penUp))
-		expect(setDelaysCmd)
+	return &driver.InterruptedError{
+		Completed: job.Resume + completed,
+		Err:       err,
 	}
-	setDelays(0x14, 0x14)
-
-	// Init done.
+}
 
-	runProgram := func(p *Program, progress chan float32) {
-		p.sent = 0
-		// Round up to nearest batch size. Note that the rounding
-		// adds another, empty, batch in case we fill up the last one.
-		// Otherwise, the engraver won't send a completed status.
-		nbatches := (p.count + progBatchSize) / progBatchSize
-		if nbatches > 0xffff {
-			eerr = errors.New("engrave: program too large")
-			return
-		}
-		wr(initProgramCmd, byte(nbatches), byte(nbatches>>8))
-		completed := 0
-	done:
-		for {
-			status := r(1)
-			if eerr != nil {
-				return
-			}
-			paddedCount := nbatches * progBatchSize
-			switch status[0] {
-			case bufferProgramStatus:
-				if p.sent == paddedCount {
-					break
-				}
-				ncmd := progBatchSize
-				if rem := p.count - p.sent; ncmd > rem {
-					ncmd = rem
-				}
-				for i := 0; i < ncmd; i++ {
-					cmd := <-p.cmds
-					p.sent++
-					wr(cmd[:]...)
-				}
-				// Pad with 0xff.
-				pad := [cmdSize]byte{}
-				for i := range pad {
-					pad[i] = nopCmd
-				}
-				for i := ncmd; i < progBatchSize; i++ {
-					p.sent++
-					wr(pad[:]...)
-				}
-			case programStepStatus:
-				completed++
-				if progress == nil {
-					break
-				}
-				// Don't spam the progress channel.
-				if completed%10 != 0 && completed < paddedCount {
-					break
-				}
-				select {
-				case <-progress:
-				default:
-				}
-				progress <- float32(completed) / float32(paddedCount)
-			case programCompleteStatus:
-				break done
-			case cancellingStatus:
-			case cancelledStatus:
-				if eerr == nil {
-					eerr = ErrCancelled
-				}
-			}
-		}
-	}
+func (e *Engraver) Position() (image.Point, error) {
+	return e.device.Position()
+}
 
-	moveTo := func(p image.Point) {
-		move := &Program{
-			DryRun: prog.DryRun,
-		}
-		f := func() {
-			move.Move(p)
-		}
-		f()
-		move.Prepare()
-		go f()
-		runProgram(move, nil)
-	}
+// ParkPosition implements driver.Parker. Completed programs
+// without an End leave the needle at the safe point.
+func (e *Engraver) ParkPosition() image.Point {
+	return SafePoint()
+}
 
-	setSpeeds(300, 300, 0xe6)
+func (e *Engraver) Close() error {
+	return e.dev.Close()
+}
 
-	// Prepare the machine: (1) reset the origin and
-	// (2) move to safe point. The first is necessary because
-	// the absolute position of the needle is not known at startup.
-	// The second is to avoid needle collision with the tightening
-	// nuts.
-	origin()
-	// Avoid a false home by moving out and re-homing.
-	falseHome := float32(5 * Millimeter)
-	moveTo(image.Pt(int(falseHome), int(falseHome)))
-	origin()
-	sp := image.Point{
+// SafePoint returns the position, in machine units, where the
+// needle clears the tightening nuts of the plate.
+func SafePoint() image.Point {
+	return image.Point{
 		X: int(float32(safePoint.X) * Millimeter),
 		Y: int(float32(safePoint.Y) * Millimeter),
 	}
-	moveTo(sp)
+}
 
-	// 0 lowest, 1 highest.
-	moveSpeed := prog.MoveSpeed
-	printSpeed := prog.PrintSpeed
+// speeds returns the move and print speed values of the program.
+func (p *Program) speeds() (move, print int) {
+	moveSpeed := p.MoveSpeed
+	printSpeed := p.PrintSpeed
 	if moveSpeed == 0 {
 		moveSpeed = defaultMoveSpeed
 	}
 	if printSpeed == 0 {
 		printSpeed = defaultPrintSpeed
 	}
-	mms := int(moveSpeed*float32(30) + (1.-moveSpeed)*float32(1000))
-	mps := int(printSpeed*float32(30) + (1.-printSpeed)*float32(1000))
-	setSpeeds(mps, mms, 0xe6)
-	runProgram(prog, progress)
-	if eerr == nil || eerr == ErrCancelled {
-		setSpeeds(300, 300, 0xe6)
-		if prog.End != (image.Point{}) {
-			moveTo(prog.End)
-		} else {
-			moveTo(sp)
-			origin()
-		}
-	}
-
-	return eerr
+	return speedValue(moveSpeed), speedValue(printSpeed)
 }
 
-var ErrCancelled = errors.New("cancelled")
+// speedValue maps a speed from 0 (lowest) to 1 (highest) to the
+// speed range of the engraver, [1000,30].
+func speedValue(speed float32) int {
+	return int(speed*float32(30) + (1.-speed)*float32(1000))
+}
 
 func mkcoords(p image.Point) [9]byte {
 	x, y := p.X, p.Y
@@ -377,15 +233,12 @@ func mkcoords(p image.Point) [9]byte {
 }
 
 func (p *Program) cmd(c [cmdSize]byte) {
-	if p.cmds != nil {
-		p.cmds <- c
-	} else {
-		p.count++
-	}
+	p.cmds = append(p.cmds, c[:]...)
 }
 
-func (p *Program) Prepare() {
-	p.cmds = make(chan [cmdSize]byte)
+// Len returns the number of recorded commands.
+func (p *Program) Len() int {
+	return len(p.cmds) / cmdSize
 }
 
 func (p *Program) Move(to image.Point) {
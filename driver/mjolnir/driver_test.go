@@ -1,8 +1,18 @@
 package mjolnir
 
 import (
+	"bytes"
+	"errors"
 	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
+
+	"seedhammer.com/driver"
+	"seedhammer.com/engrave"
 )
 
 func TestEndToEnd(t *testing.T) {
@@ -10,21 +20,426 @@ func TestEndToEnd(t *testing.T) {
 	defer s.Close()
 
 	prog := &Program{}
-	design := func() {
-		for i := 0; i < 2000; i++ {
-			prog.Line(image.Pt(i, i*2))
-			prog.Line(image.Pt(i*4, i*3))
-			prog.Move(image.Pt(i, i))
-		}
-	}
-	design()
-	prog.Prepare()
-	engraveErr := make(chan error)
-	go func() {
-		engraveErr <- Engrave(s, prog, nil, nil)
-	}()
-	design()
-	if err := <-engraveErr; err != nil {
+	for i := 0; i < 2000; i++ {
+		prog.Line(image.Pt(i, i*2))
+		prog.Line(image.Pt(i*4, i*3))
+		prog.Move(image.Pt(i, i))
+	}
+	if n := prog.Len(); n != 3*2000 {
+		t.Fatalf("recorded %d commands, want %d", n, 3*2000)
+	}
+	if err := Engrave(s, prog, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	dev := &recordingDevice{ReadWriter: s}
+	prog := new(Program)
+	lineCommand(10000).Engrave(prog)
+	if err := Engrave(dev, prog, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if max := progBatchSize * cmdSize; dev.maxWrite > max {
+		t.Errorf("wrote %d bytes at once, want at most a batch of %d", dev.maxWrite, max)
+	}
+}
+
+func TestCancelLeaks(t *testing.T) {
+	job := driver.Job{Command: lineCommand(2000)}
+	t.Run("cancel", func(t *testing.T) {
+		checkGoroutines(t, func() {
+			s := NewSimulator()
+			defer s.Close()
+			dev := &cancellingDevice{
+				ReadWriteCloser: s,
+				reads:           100,
+				cancel:          make(chan struct{}),
+				cancelled:       make(chan struct{}),
+			}
+			err := NewEngraver(dev).Run(job, nil, dev.cancel)
+			if !errors.Is(err, driver.ErrCancelled) {
+				t.Errorf("got error %v, want %v", err, driver.ErrCancelled)
+			}
+		})
+	})
+	t.Run("cancel before start", func(t *testing.T) {
+		checkGoroutines(t, func() {
+			s := NewSimulator()
+			defer s.Close()
+			cancel := make(chan struct{})
+			close(cancel)
+			NewEngraver(s).Run(job, nil, cancel)
+		})
+	})
+	t.Run("error", func(t *testing.T) {
+		checkGoroutines(t, func() {
+			s := NewSimulator()
+			defer s.Close()
+			dev := &failingDevice{dev: s, reads: 100, err: errors.New("connection lost")}
+			if err := NewEngraver(dev).Run(job, nil, make(chan struct{})); err == nil {
+				t.Error("engraving succeeded despite failing device")
+			}
+		})
+	})
+}
+
+func TestFaults(t *testing.T) {
+	const n = 2000
+	job := driver.Job{Command: lineCommand(n)}
+	interrupted := func(err error) bool {
+		var ierr *driver.InterruptedError
+		return errors.As(err, &ierr) && ierr.Completed > 0 && ierr.Completed < n
+	}
+	tests := []struct {
+		name  string
+		fault Fault
+		check func(err error) bool
+	}{
+		{"drop", Fault{Type: FaultDrop, Batch: 5}, func(err error) bool {
+			return err == nil
+		}},
+		{"delay", Fault{Type: FaultDelay, Batch: 5, Delay: 10 * time.Millisecond}, func(err error) bool {
+			return err == nil
+		}},
+		{"cancel", Fault{Type: FaultCancel, Batch: 5}, func(err error) bool {
+			return interrupted(err) && errors.Is(err, driver.ErrCancelled)
+		}},
+		{"disconnect", Fault{Type: FaultDisconnect, Batch: 5}, func(err error) bool {
+			return interrupted(err) && errors.Is(err, errDisconnected)
+		}},
+		{"garbage", Fault{Type: FaultGarbage, Batch: 5}, func(err error) bool {
+			return interrupted(err) && !errors.Is(err, driver.ErrCancelled)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkGoroutines(t, func() {
+				s := NewSimulator()
+				defer s.Close()
+				s.Fault = test.fault
+				err := NewEngraver(s).Run(job, make(chan float32, 1), make(chan struct{}))
+				if !test.check(err) {
+					t.Errorf("unexpected result: %v", err)
+				}
+			})
+		})
+	}
+}
+
+func TestCancelParks(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	s.Fault = Fault{Type: FaultCancel, Batch: 5}
+	e := NewEngraver(s)
+	job := driver.Job{Command: lineCommand(2000)}
+	if err := e.Run(job, nil, make(chan struct{})); !errors.Is(err, driver.ErrCancelled) {
+		t.Fatalf("got error %v, want %v", err, driver.ErrCancelled)
+	}
+	if err := driver.CheckParked(e); err != nil {
+		t.Errorf("cancelled engraving didn't park: %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	prog := new(Program)
+	engrave.Rect{Min: image.Pt(1000, 1000), Max: image.Pt(3000, 2000)}.Engrave(prog)
+	if err := Engrave(s, prog, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	bounds := image.Rect(0, 0, 4000, 3000)
+	img := Render(s.Cmds, bounds, 4)
+	scale := 4 / Millimeter
+	inside := image.Pt(int(1500*scale), int(1500*scale))
+	edge := image.Pt(int(2000*scale), int(1000*scale))
+	if img.GrayAt(inside.X, inside.Y).Y != 0xff {
+		t.Errorf("inside of rectangle at %v is not blank", inside)
+	}
+	if img.GrayAt(edge.X, edge.Y).Y == 0xff {
+		t.Errorf("edge of rectangle at %v is blank", edge)
+	}
+	buf := new(bytes.Buffer)
+	if err := s.WritePNG(buf, bounds, 4); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkGoroutines fails the test if goroutines started
+// by f outlive it.
+func checkGoroutines(t *testing.T, f func()) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	f()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines leaked", runtime.NumGoroutine()-before)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// cancellingDevice cancels the engraving after a number
+// of reads.
+type cancellingDevice struct {
+	io.ReadWriteCloser
+	reads     int
+	cancel    chan struct{}
+	cancelled chan struct{}
+}
+
+func (c *cancellingDevice) Read(p []byte) (int, error) {
+	if c.reads == 0 {
+		close(c.cancel)
+		// Wait for the cancel command.
+		<-c.cancelled
+	}
+	c.reads--
+	return c.ReadWriteCloser.Read(p)
+}
+
+func (c *cancellingDevice) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	select {
+	case <-c.cancel:
+		if bytes.Equal(p, []byte{cancelCmd}) {
+			close(c.cancelled)
+		}
+	default:
+	}
+	return n, err
+}
+
+// recordingDevice records the largest write.
+type recordingDevice struct {
+	io.ReadWriter
+	maxWrite int
+}
+
+func (r *recordingDevice) Write(p []byte) (int, error) {
+	if len(p) > r.maxWrite {
+		r.maxWrite = len(p)
+	}
+	return r.ReadWriter.Write(p)
+}
+
+func TestEstimate(t *testing.T) {
+	var runs []Run
+	for i := 1; i <= 10; i++ {
+		e := new(Estimator)
+		for j := 0; j < i*100; j++ {
+			e.Line(image.Pt(j*i, j))
+			if j%i == 0 {
+				e.Move(image.Pt(j, j*2))
+			}
+		}
+		u := e.Usage()
+		if u.Batches != (e.count+progBatchSize)/progBatchSize {
+			t.Errorf("estimated %d batches for %d commands", u.Batches, e.count)
+		}
+		runs = append(runs, Run{Usage: u, Duration: DefaultModel.Duration(u)})
+	}
+	m, err := Fit(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range runs {
+		got := m.Duration(r.Usage)
+		if d := got - r.Duration; d < -time.Millisecond || d > time.Millisecond {
+			t.Errorf("fitted model estimated %v, want %v", got, r.Duration)
+		}
+	}
+	if _, err := Fit(runs[:1]); err == nil {
+		t.Error("fitted a model to a single run")
+	}
+}
+
+// TestDefaultModel verifies that DefaultModel matches the model
+// fitted to the logged runs. The runs are logged on an engraver by
+// the cli engrave command with the -log flag.
+func TestDefaultModel(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "runs.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	runs, err := ReadRuns(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Fit(runs)
+	if err != nil {
+		t.Fatalf("no model fitted to %d logged runs: %v", len(runs), err)
+	}
+	for _, r := range runs {
+		got, want := DefaultModel.Duration(r.Usage), m.Duration(r.Usage)
+		if d := got - want; d < -want/100 || d > want/100 {
+			t.Errorf("DefaultModel estimated %v, fitted model %v (run %+v)", got, want, r)
+		}
+	}
+}
+
+func TestEngraver(t *testing.T) {
+	s := NewSimulator()
+	e := NewEngraver(s)
+	defer e.Close()
+
+	if err := e.Home(); err != nil {
+		t.Fatal(err)
+	}
+	if pos, err := e.Position(); err != nil || pos != (image.Point{}) {
+		t.Fatalf("got position %v, %v after homing", pos, err)
+	}
+	job := driver.Job{
+		Command: engrave.Rect{Min: image.Pt(100, 100), Max: image.Pt(200, 300)},
+	}
+	progress := make(chan float32, 1)
+	s.Cmds = nil
+	if err := e.Run(job, progress, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p := <-progress; p != 1 {
+		t.Errorf("final progress %v, want 1", p)
+	}
+	lines := 0
+	for _, c := range s.Cmds {
+		if c.Type == LineTo {
+			lines++
+		}
+	}
+	if lines == 0 {
+		t.Error("job engraved no lines")
+	}
+	if err := driver.CheckParked(e); err != nil {
 		t.Error(err)
 	}
 }
+
+func TestDevice(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+	d := NewDevice(s)
+	if err := d.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if d.Homed() {
+		t.Error("homed before homing")
+	}
+	if err := d.Home(); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Homed() {
+		t.Error("not homed after homing")
+	}
+	if err := d.SetSpeeds(setupSpeed, setupSpeed); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetDelays(penDelay, penDelay); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []image.Point{SafePoint(), image.Pt(1000, 2000), {}} {
+		if err := d.MoveTo(p); err != nil {
+			t.Fatal(err)
+		}
+		pos, err := d.Position()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pos != p {
+			t.Errorf("moved to %v, got position %v", p, pos)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	const n = 500
+	job := driver.Job{Command: lineCommand(n)}
+	s := NewSimulator()
+	defer s.Close()
+	dev := &failingDevice{dev: s, reads: 200, err: errors.New("connection lost")}
+	e := NewEngraver(dev)
+	err := e.Run(job, nil, nil)
+	var ierr *driver.InterruptedError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("got error %v, want an InterruptedError", err)
+	}
+	if !errors.Is(err, dev.err) {
+		t.Errorf("%v doesn't wrap %v", err, dev.err)
+	}
+	if ierr.Completed <= 0 || ierr.Completed >= n {
+		t.Fatalf("completed %d instructions of %d", ierr.Completed, n)
+	}
+	job.Resume = ierr.Completed
+
+	s2 := NewSimulator()
+	defer s2.Close()
+	if err := NewEngraver(s2).Run(job, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	first := -1
+	var lines []image.Point
+	for i, c := range s2.Cmds {
+		if c.Type != LineTo {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		lines = append(lines, image.Pt(int(c.X), int(c.Y)))
+	}
+	if first < 1 {
+		t.Fatal("no move before resumed lines")
+	}
+	if m := s2.Cmds[first-1]; m.Type != MoveTo || image.Pt(int(m.X), int(m.Y)) != lineCommand(0).point(job.Resume-1) {
+		t.Errorf("resumed from %v, want a move to %v", m, lineCommand(0).point(job.Resume-1))
+	}
+	if got, want := len(lines), n-job.Resume; got != want {
+		t.Fatalf("resumed with %d lines, want %d", got, want)
+	}
+	for i, p := range lines {
+		if want := lineCommand(0).point(job.Resume + i); p != want {
+			t.Fatalf("resumed line %d to %v, want %v", i, p, want)
+		}
+	}
+}
+
+// lineCommand engraves a number of lines.
+type lineCommand int
+
+func (lineCommand) point(i int) image.Point {
+	return image.Pt(100+i, 100+2*i)
+}
+
+func (c lineCommand) Engrave(p engrave.Program) {
+	for i := 0; i < int(c); i++ {
+		p.Line(c.point(i))
+	}
+}
+
+// failingDevice fails reads after a number of successful reads.
+type failingDevice struct {
+	dev   io.ReadWriteCloser
+	reads int
+	err   error
+}
+
+func (f *failingDevice) Read(p []byte) (int, error) {
+	if f.reads == 0 {
+		return 0, f.err
+	}
+	f.reads--
+	return f.dev.Read(p)
+}
+
+func (f *failingDevice) Write(p []byte) (int, error) {
+	return f.dev.Write(p)
+}
+
+func (f *failingDevice) Close() error {
+	return f.dev.Close()
+}
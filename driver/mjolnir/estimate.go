@@ -0,0 +1,230 @@
+package mjolnir
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"time"
+)
+
+// Model describes the timing of the engraver.
+type Model struct {
+	// Step is the duration of a single step per unit of speed
+	// value. The engraver speed values range from 30 (fastest)
+	// to 1000 (slowest).
+	Step time.Duration
+	// Delay is the duration per unit of pen delay value.
+	Delay time.Duration
+	// Batch is the protocol overhead of transferring a batch
+	// of commands.
+	Batch time.Duration
+	// Setup is the duration of initialization and homing.
+	Setup time.Duration
+}
+
+// DefaultModel approximates the engraver timing. It must match
+// the model fitted to the runs logged in testdata/runs.jsonl;
+// after logging new runs, update it to the output of
+//
+//	cli -log driver/mjolnir/testdata/runs.jsonl calibrate
+var DefaultModel = Model{
+	Step:  800 * time.Nanosecond,
+	Delay: time.Millisecond,
+	// A batch of 80 commands at 115200 baud takes ~70ms to transfer.
+	Batch: 80 * time.Millisecond,
+	Setup: 15 * time.Second,
+}
+
+// Usage summarizes the work of engraving a program.
+type Usage struct {
+	// StepUnits is the sum of the steps of every move and line,
+	// weighted by their speed values. Steps are measured along
+	// the longest axis, because the axes move simultaneously.
+	StepUnits int64
+	// DelayUnits is the sum of the delays of every change
+	// between moves and lines.
+	DelayUnits int64
+	// Batches is the number of command batches of the program.
+	Batches int
+}
+
+// Duration estimates the duration of u.
+func (m Model) Duration(u Usage) time.Duration {
+	return time.Duration(u.StepUnits)*m.Step +
+		time.Duration(u.DelayUnits)*m.Delay +
+		time.Duration(u.Batches)*m.Batch +
+		m.Setup
+}
+
+// Estimator is an engrave.Program that estimates the duration
+// of engraving its instructions with Engrave.
+type Estimator struct {
+	// DryRun, MoveSpeed and PrintSpeed match the
+	// corresponding Program fields.
+	DryRun     bool
+	MoveSpeed  float32
+	PrintSpeed float32
+
+	started     bool
+	move, print int
+	pos         image.Point
+	line        bool
+	count       int
+	usage       Usage
+}
+
+func (e *Estimator) Move(to image.Point) {
+	e.instruct(to, false)
+}
+
+func (e *Estimator) Line(to image.Point) {
+	e.instruct(to, !e.DryRun)
+}
+
+func (e *Estimator) instruct(to image.Point, line bool) {
+	if !e.started {
+		e.started = true
+		e.pos = SafePoint()
+		e.move, e.print = (&Program{MoveSpeed: e.MoveSpeed, PrintSpeed: e.PrintSpeed}).speeds()
+	}
+	speed := e.move
+	if line {
+		speed = e.print
+	}
+	e.usage.StepUnits += int64(steps(e.pos, to) * speed)
+	if line != e.line {
+		e.usage.DelayUnits += penDelay
+	}
+	e.line = line
+	e.pos = to
+	e.count++
+}
+
+// Usage returns the usage of the instructions so far, including
+// the return to the safe point after engraving.
+func (e *Estimator) Usage() Usage {
+	u := e.usage
+	u.StepUnits += int64(steps(e.pos, SafePoint()) * setupSpeed)
+	u.Batches = (e.count + progBatchSize) / progBatchSize
+	return u
+}
+
+// Duration estimates the duration of the instructions so far
+// with DefaultModel.
+func (e *Estimator) Duration() time.Duration {
+	return DefaultModel.Duration(e.Usage())
+}
+
+func steps(from, to image.Point) int {
+	d := to.Sub(from)
+	if d.X < 0 {
+		d.X = -d.X
+	}
+	if d.Y < 0 {
+		d.Y = -d.Y
+	}
+	if d.X > d.Y {
+		return d.X
+	}
+	return d.Y
+}
+
+// Run is a logged engraving.
+type Run struct {
+	Usage    Usage
+	Duration time.Duration
+}
+
+// ReadRuns reads runs in the JSON stream format of the run log
+// written by the cli engrave command.
+func ReadRuns(r io.Reader) ([]Run, error) {
+	var runs []Run
+	d := json.NewDecoder(r)
+	for {
+		var run Run
+		if err := d.Decode(&run); err != nil {
+			if err == io.EOF {
+				return runs, nil
+			}
+			return nil, fmt.Errorf("mjolnir: run log: %w", err)
+		}
+		runs = append(runs, run)
+	}
+}
+
+// Fit a model to logged runs by least squares. At least 4 runs
+// of varying usage are required.
+func Fit(runs []Run) (Model, error) {
+	const nparams = 4
+	if len(runs) < nparams {
+		return Model{}, errors.New("mjolnir: too few runs")
+	}
+	features := func(r Run) [nparams]float64 {
+		return [nparams]float64{
+			float64(r.Usage.StepUnits),
+			float64(r.Usage.DelayUnits),
+			float64(r.Usage.Batches),
+			1,
+		}
+	}
+	// Scale features to unit magnitude to keep the
+	// system well-conditioned.
+	var scale [nparams]float64
+	for _, r := range runs {
+		for i, f := range features(r) {
+			scale[i] = math.Max(scale[i], math.Abs(f))
+		}
+	}
+	// Solve the normal equations, AᵀAx = Aᵀb, by Gauss-Jordan
+	// elimination with partial pivoting.
+	var ata [nparams][nparams + 1]float64
+	for _, r := range runs {
+		row := features(r)
+		for i := range row {
+			if scale[i] == 0 {
+				return Model{}, errors.New("mjolnir: runs don't determine a model")
+			}
+			row[i] /= scale[i]
+		}
+		for i := range row {
+			for j := range row {
+				ata[i][j] += row[i] * row[j]
+			}
+			ata[i][nparams] += row[i] * float64(r.Duration)
+		}
+	}
+	for col := 0; col < nparams; col++ {
+		pivot := col
+		for i := col + 1; i < nparams; i++ {
+			if math.Abs(ata[i][col]) > math.Abs(ata[pivot][col]) {
+				pivot = i
+			}
+		}
+		ata[col], ata[pivot] = ata[pivot], ata[col]
+		if math.Abs(ata[col][col]) < 1e-9 {
+			return Model{}, errors.New("mjolnir: runs don't determine a model")
+		}
+		for i := 0; i < nparams; i++ {
+			if i == col {
+				continue
+			}
+			f := ata[i][col] / ata[col][col]
+			for j := col; j <= nparams; j++ {
+				ata[i][j] -= f * ata[col][j]
+			}
+		}
+	}
+	var x [nparams]time.Duration
+	for i := range x {
+		x[i] = time.Duration(math.Round(ata[i][nparams] / ata[i][i] / scale[i]))
+	}
+	return Model{
+		Step:  x[0],
+		Delay: x[1],
+		Batch: x[2],
+		Setup: x[3],
+	}, nil
+}
@@ -0,0 +1,39 @@
+package mjolnir
+
+import (
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"seedhammer.com/engrave"
+)
+
+// Render rasterizes the lines of cmds into an image of bounds, in
+// machine units, at ppmm pixels per millimeter.
+func Render(cmds []Cmd, bounds image.Rectangle, ppmm float32) *image.Gray {
+	scale := ppmm / Millimeter
+	img := image.NewGray(image.Rect(
+		int(float32(bounds.Min.X)*scale), int(float32(bounds.Min.Y)*scale),
+		int(float32(bounds.Max.X)*scale), int(float32(bounds.Max.Y)*scale),
+	))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	r := engrave.NewRasterizer(img, img.Bounds(), scale, StrokeWidth*ppmm)
+	for _, c := range cmds {
+		p := image.Pt(int(c.X), int(c.Y))
+		switch c.Type {
+		case MoveTo:
+			r.Move(p)
+		case LineTo:
+			r.Line(p)
+		}
+	}
+	r.Rasterize()
+	return img
+}
+
+// WritePNG renders the recorded commands to w in PNG format.
+// See Render.
+func (s *Simulator) WritePNG(w io.Writer, bounds image.Rectangle, ppmm float32) error {
+	return png.Encode(w, Render(s.Cmds, bounds, ppmm))
+}
@@ -2,14 +2,25 @@ package mjolnir
 
 import (
 	"errors"
+	"image"
+	"time"
 )
 
 type Simulator struct {
 	state     deviceState
 	ncmds     int
 	nbuffered int
+	pos       image.Point
+	// received counts program commands, for
+	// counting batches.
+	received     int
+	batches      int
+	disconnected bool
 
-	Cmds  []Cmd
+	Cmds []Cmd
+	// Fault is injected into the simulation. It must
+	// be set before the simulator is used.
+	Fault Fault
 	close chan struct{}
 	in    chan ioRequest
 	out   chan ioResult
@@ -22,6 +33,37 @@ type Cmd struct {
 
 type CmdType int
 
+// Fault describes a scripted failure of the simulated engraver.
+type Fault struct {
+	Type FaultType
+	// Batch is the number of program batches received
+	// before the fault occurs.
+	Batch int
+	// Delay is the delay of FaultDelay.
+	Delay time.Duration
+}
+
+type FaultType int
+
+const (
+	FaultNone FaultType = iota
+	// FaultDrop drops a program step status.
+	FaultDrop
+	// FaultDelay delays a status.
+	FaultDelay
+	// FaultCancel replies with a spurious cancelled status.
+	FaultCancel
+	// FaultDisconnect fails every read and write.
+	FaultDisconnect
+	// FaultGarbage replies with an invalid status.
+	FaultGarbage
+)
+
+// garbageStatus is not a valid status.
+const garbageStatus = 0x5a
+
+var errDisconnected = errors.New("mjolnir: simulated disconnect")
+
 const (
 	MoveTo CmdType = iota
 	LineTo
@@ -46,6 +88,8 @@ const (
 	stateSetDelays
 	stateMoveToOrigin
 	stateExecuting
+	stateQueryPosition
+	stateCancelled
 )
 
 type ioRequest struct {
@@ -83,7 +127,21 @@ func coordsFromCmd(cmd []byte) (uint32, uint32) {
 	return x, y
 }
 
+// fault returns the fault type due, if any.
+func (s *Simulator) fault() FaultType {
+	if s.state != stateExecuting || s.batches < s.Fault.Batch {
+		return FaultNone
+	}
+	t := s.Fault.Type
+	// Faults occur once.
+	s.Fault = Fault{}
+	return t
+}
+
 func (s *Simulator) doRead(data []byte) (int, error) {
+	if s.disconnected {
+		return 0, errDisconnected
+	}
 	read := func(resp []byte) (int, error) {
 		if len(resp) > len(data) {
 			return 0, errors.New("read overflow")
@@ -91,6 +149,26 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 		copy(data, resp)
 		return len(resp), nil
 	}
+	switch f := s.Fault; s.fault() {
+	case FaultDrop:
+		if s.nbuffered == 0 {
+			// Wait for a step status to drop.
+			s.Fault = f
+			break
+		}
+		s.nbuffered--
+	case FaultDelay:
+		time.Sleep(f.Delay)
+	case FaultCancel:
+		s.state = stateReady
+		s.ncmds, s.nbuffered = 0, 0
+		return read([]byte{cancelledStatus})
+	case FaultDisconnect:
+		s.disconnected = true
+		return 0, errDisconnected
+	case FaultGarbage:
+		return read([]byte{garbageStatus})
+	}
 	switch s.state {
 	case stateInitializing:
 		s.state = stateReady
@@ -104,6 +182,13 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 	case stateMoveToOrigin:
 		s.state = stateReady
 		return read([]byte{moveToOriginCmd, moveToOriginCmdResponse})
+	case stateCancelled:
+		s.state = stateReady
+		return read([]byte{cancelledStatus})
+	case stateQueryPosition:
+		s.state = stateReady
+		coords := mkcoords(s.pos)
+		return read(append([]byte{queryPosCmd}, coords[:]...))
 	case stateExecuting:
 		switch {
 		case s.nbuffered == 0 && s.ncmds > 0:
@@ -120,6 +205,9 @@ func (s *Simulator) doRead(data []byte) (int, error) {
 }
 
 func (s *Simulator) doWrite(data []byte) (n int, err error) {
+	if s.disconnected {
+		return 0, errDisconnected
+	}
 	skip := func(bytes int) {
 		if len(data) < bytes {
 			err = errors.New("buffer underflow")
@@ -137,6 +225,10 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 	batchCmd := func() {
 		s.nbuffered++
 		s.ncmds--
+		s.received++
+		if s.received%progBatchSize == 0 {
+			s.batches++
+		}
 		skip(9)
 	}
 	for len(data) > 0 {
@@ -145,12 +237,16 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 		data = data[1:]
 		switch cmd {
 		case cancelCmd:
-			s.state = stateReady
+			if s.state == stateExecuting {
+				s.state = stateCancelled
+			} else {
+				s.state = stateReady
+			}
 		case initCmd:
 			if s.state == stateExecuting {
 				// 0x00 is line to in programming mode.
 				x, y := coordsFromCmd(data)
-				s.Cmds = append(s.Cmds, Cmd{LineTo, x, y})
+				s.record(Cmd{LineTo, x, y})
 				batchCmd()
 			} else {
 				s.state = stateInitializing
@@ -167,17 +263,19 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 			if err == nil && subCmd[0] != moveToOriginCmdExtra {
 				err = errors.New("invalid origin command")
 			}
-			s.Cmds = append(s.Cmds, Cmd{MoveTo, 0, 0})
+			s.record(Cmd{MoveTo, 0, 0})
 		case initProgramCmd:
 			s.state = stateExecuting
 			ncmds := read(2)
 			s.ncmds = (int(ncmds[0]) | int(ncmds[1])<<8) * progBatchSize
 		case moveCmd:
 			x, y := coordsFromCmd(data)
-			s.Cmds = append(s.Cmds, Cmd{MoveTo, x, y})
+			s.record(Cmd{MoveTo, x, y})
 			batchCmd()
 		case nopCmd:
 			batchCmd()
+		case queryPosCmd:
+			s.state = stateQueryPosition
 		default:
 			return n, errors.New("invalid command")
 		}
@@ -185,6 +283,11 @@ func (s *Simulator) doWrite(data []byte) (n int, err error) {
 	return
 }
 
+func (s *Simulator) record(c Cmd) {
+	s.Cmds = append(s.Cmds, c)
+	s.pos = image.Pt(int(c.X), int(c.Y))
+}
+
 func (s *Simulator) Read(data []byte) (int, error) {
 	s.in <- ioRequest{false, data}
 	r := <-s.out
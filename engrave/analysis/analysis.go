@@ -0,0 +1,199 @@
+// package analysis audits engravings for timing side-channels, by
+// comparing the motions of engravings of different secrets.
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math/rand"
+	"strings"
+
+	"seedhammer.com/backup"
+	"seedhammer.com/bip39"
+	"seedhammer.com/engrave"
+)
+
+// Segment is an instruction as observed through the timing of
+// the engraver.
+type Segment struct {
+	Line bool
+	// DX and DY are the absolute distances along each axis.
+	DX, DY int
+	// Command is the index of the instruction.
+	Command int
+	// Label identifies the plate element of the instructions, or
+	// is empty for unlabeled instructions.
+	Label string
+}
+
+// Recorder is a Program that records the motion trace of an
+// engraving. It implements engrave.Labeler to attribute instructions
+// to plate elements.
+type Recorder struct {
+	Trace []Segment
+
+	pos    image.Point
+	n      int
+	labels []string
+	label  string
+}
+
+func (r *Recorder) Move(p image.Point) {
+	r.instruct(p, false)
+}
+
+func (r *Recorder) Line(p image.Point) {
+	r.instruct(p, true)
+}
+
+func (r *Recorder) Begin(label string) {
+	r.labels = append(r.labels, label)
+	r.label = strings.Join(r.labels, "/")
+}
+
+func (r *Recorder) End() {
+	r.labels = r.labels[:len(r.labels)-1]
+	r.label = strings.Join(r.labels, "/")
+}
+
+func (r *Recorder) instruct(p image.Point, line bool) {
+	d := p.Sub(r.pos)
+	r.pos = p
+	if d.X < 0 {
+		d.X = -d.X
+	}
+	if d.Y < 0 {
+		d.Y = -d.Y
+	}
+	r.Trace = append(r.Trace, Segment{
+		Line:    line,
+		DX:      d.X,
+		DY:      d.Y,
+		Command: r.n,
+		Label:   r.label,
+	})
+	r.n++
+}
+
+// Len returns the length of the segment in the metric of the
+// constant time engravers, which move both axes simultaneously.
+func (s Segment) Len() int {
+	if s.DX > s.DY {
+		return s.DX
+	}
+	return s.DY
+}
+
+// Record the motion trace of cmd.
+func Record(cmd engrave.Command) []Segment {
+	r := new(Recorder)
+	cmd.Engrave(r)
+	return r.Trace
+}
+
+// Divergence describes the first difference between two motion
+// traces.
+type Divergence struct {
+	// Segment is the index of the first differing segment.
+	Segment int
+	// Want and Got are the differing segments. A segment is
+	// missing if its trace ended early.
+	Want, Got *Segment
+}
+
+func (d *Divergence) String() string {
+	seg := d.Want
+	if seg == nil {
+		seg = d.Got
+	}
+	label := seg.Label
+	if label == "" {
+		label = "unlabeled element"
+	}
+	return fmt.Sprintf("segment %d (command %d, %s): want %s, got %s",
+		d.Segment, seg.Command, label, describe(d.Want), describe(d.Got))
+}
+
+func describe(s *Segment) string {
+	switch {
+	case s == nil:
+		return "end of engraving"
+	case s.Line:
+		return fmt.Sprintf("line of length %d (%d, %d)", s.Len(), s.DX, s.DY)
+	default:
+		return fmt.Sprintf("move of length %d (%d, %d)", s.Len(), s.DX, s.DY)
+	}
+}
+
+// Diff returns the first divergence between two traces, or nil
+// if they are indistinguishable by timing. Instructions are
+// compared one by one, so different sequences of equal total
+// length diverge.
+func Diff(want, got []Segment) *Divergence {
+	for i := 0; i < len(want) || i < len(got); i++ {
+		var w, g *Segment
+		if i < len(want) {
+			w = &want[i]
+		}
+		if i < len(got) {
+			g = &got[i]
+		}
+		if w == nil || g == nil || w.Line != g.Line || w.DX != g.DX || w.DY != g.DY {
+			return &Divergence{Segment: i, Want: w, Got: g}
+		}
+	}
+	return nil
+}
+
+// SeedReport is the result of auditing a seed side.
+type SeedReport struct {
+	// Mnemonic and MasterFingerprint are the secrets whose
+	// engraving diverged from the engraving of the original plate.
+	Mnemonic          bip39.Mnemonic
+	MasterFingerprint uint32
+	Divergence        *Divergence
+}
+
+func (r *SeedReport) String() string {
+	var words []string
+	for _, w := range r.Mnemonic {
+		words = append(words, bip39.LabelFor(w))
+	}
+	return fmt.Sprintf("%s (fingerprint %.8x): %s", strings.Join(words, " "), r.MasterFingerprint, r.Divergence)
+}
+
+// Seed engraves n variants of plate with random mnemonics and master
+// fingerprints, and reports the first variant whose motion trace
+// diverges from the trace of plate. It returns a nil report if every
+// trace is identical.
+func Seed(rng *rand.Rand, scale, strokeWidth float32, plate backup.Seed, n int) (*SeedReport, error) {
+	if len(plate.Mnemonic) == 0 {
+		return nil, errors.New("analysis: empty mnemonic")
+	}
+	side, err := backup.EngraveSeed(scale, strokeWidth, plate)
+	if err != nil {
+		return nil, err
+	}
+	want := Record(side)
+	for i := 0; i < n; i++ {
+		m := make(bip39.Mnemonic, len(plate.Mnemonic))
+		for j := range m {
+			m[j] = bip39.Word(rng.Intn(int(bip39.NumWords)))
+		}
+		plate.Mnemonic = m.FixChecksum()
+		plate.MasterFingerprint = rng.Uint32()
+		side, err := backup.EngraveSeed(scale, strokeWidth, plate)
+		if err != nil {
+			return nil, err
+		}
+		if d := Diff(want, Record(side)); d != nil {
+			return &SeedReport{
+				Mnemonic:          plate.Mnemonic,
+				MasterFingerprint: plate.MasterFingerprint,
+				Divergence:        d,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"image"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"seedhammer.com/backup"
+	"seedhammer.com/bip39"
+	"seedhammer.com/driver/mjolnir"
+	"seedhammer.com/font/constant"
+)
+
+func TestSeed(t *testing.T) {
+	m, err := bip39.ParseMnemonic("vocal tray giggle tool duck letter category pattern train magnet excite swamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sz := range []backup.PlateSize{backup.SmallPlate, backup.SquarePlate, backup.LargePlate} {
+		// The constant time engravers equalize the total length of
+		// runs of moves and lines, not every instruction, so both
+		// engravings diverge at the first secret element.
+		for _, constantTime := range []bool{false, true} {
+			plate := backup.Seed{
+				Title:             "Satoshi's Stash",
+				KeyIdx:            0,
+				Keys:              1,
+				Mnemonic:          m,
+				MasterFingerprint: 0x97a6d3c2,
+				Font:              constant.Font,
+				Size:              sz,
+				ConstantTime:      constantTime,
+			}
+			rng := rand.New(rand.NewSource(1))
+			r, err := Seed(rng, mjolnir.Millimeter, mjolnir.StrokeWidth, plate, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r == nil {
+				t.Fatalf("size %d (constant time %v): fingerprint engraving didn't diverge", sz, constantTime)
+			}
+			if got := r.Divergence.Want.Label; got != "fingerprint" {
+				t.Errorf("size %d (constant time %v): divergence attributed to %q, want %q", sz, constantTime, got, "fingerprint")
+			}
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := new(Recorder)
+	r.Move(image.Pt(3, 4))
+	r.Line(image.Pt(1, 4))
+	r.Line(image.Pt(1, 9))
+	want := []Segment{
+		{Line: false, DX: 3, DY: 4, Command: 0},
+		{Line: true, DX: 2, DY: 0, Command: 1},
+		{Line: true, DX: 0, DY: 5, Command: 2},
+	}
+	if !reflect.DeepEqual(r.Trace, want) {
+		t.Errorf("recorded %+v, want %+v", r.Trace, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := []Segment{{Line: false, DX: 10}, {Line: true, DX: 3, DY: 2, Command: 1, Label: "word 1"}}
+	if d := Diff(a, a); d != nil {
+		t.Errorf("identical traces diverged: %s", d)
+	}
+	// Equal manhattan length, different direction.
+	b := []Segment{{Line: false, DX: 10}, {Line: true, DX: 2, DY: 3, Command: 1, Label: "word 1"}}
+	d := Diff(a, b)
+	if d == nil || d.Segment != 1 {
+		t.Fatalf("got divergence %v, want segment 1", d)
+	}
+	if !strings.Contains(d.String(), "word 1") {
+		t.Errorf("divergence %q doesn't mention the element", d)
+	}
+	d = Diff(a, a[:1])
+	if d == nil || d.Segment != 1 || d.Got != nil {
+		t.Errorf("got divergence %v for a truncated trace", d)
+	}
+}
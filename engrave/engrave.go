@@ -12,8 +12,10 @@ import (
 	"image/color"
 	"image/draw"
 	"math"
+	"math/bits"
 	"math/rand"
 	"sort"
+	"strings"
 
 	"github.com/kortschak/qr"
 	"github.com/srwiley/rasterx"
@@ -53,6 +55,18 @@ func (t *transformedProgram) Line(p image.Point) {
 	t.prog.Line(t.trans.transform(p))
 }
 
+func (t *transformedProgram) Begin(label string) {
+	if l, ok := t.prog.(Labeler); ok {
+		l.Begin(label)
+	}
+}
+
+func (t *transformedProgram) End() {
+	if l, ok := t.prog.(Labeler); ok {
+		l.End()
+	}
+}
+
 type offsetProgram struct {
 	prog Program
 	off  image.Point
@@ -67,6 +81,53 @@ func (o *offsetProgram) Line(p image.Point) {
 	o.prog.Line(p.Add(o.off))
 }
 
+func (o *offsetProgram) Begin(label string) {
+	if l, ok := o.prog.(Labeler); ok {
+		l.Begin(label)
+	}
+}
+
+func (o *offsetProgram) End() {
+	if l, ok := o.prog.(Labeler); ok {
+		l.End()
+	}
+}
+
+// Labeler is implemented by programs that track which element
+// of an engraving the instructions belong to.
+type Labeler interface {
+	// Begin marks the start of the instructions of the labeled
+	// element. Elements may nest.
+	Begin(label string)
+	// End marks the end of the most recently begun element.
+	End()
+}
+
+type labelCmd struct {
+	label string
+	cmd   Command
+}
+
+// Label names cmd for programs that implement Labeler. Other programs
+// see the instructions of cmd unchanged.
+func Label(label string, cmd Command) Command {
+	return labelCmd{
+		label: label,
+		cmd:   cmd,
+	}
+}
+
+func (l labelCmd) Engrave(p Program) {
+	lp, ok := p.(Labeler)
+	if ok {
+		lp.Begin(l.label)
+	}
+	l.cmd.Engrave(p)
+	if ok {
+		lp.End()
+	}
+}
+
 type transform [6]int
 
 func (m transform) transform(p image.Point) image.Point {
@@ -195,8 +256,11 @@ func constantTimeQRModules(dims int) int {
 	case 29:
 		return 385 + extra
 	}
-	// Not supported, return a low number to force error.
-	return 0
+	// Larger versions are bounded by a fraction of the modules not
+	// covered by markers. Masking keeps the proportion of dark modules
+	// close to half, and fuzzing found no more than 53% engraved.
+	_, _, static := bitmapForQRStatic(dims)
+	return (dims*dims - static.count()) * 3 / 5
 }
 
 func constantTimeStartEnd(dim int) (start, end image.Point) {
@@ -232,12 +296,8 @@ func bitmapForQRStatic(dim int) ([]image.Point, []image.Point, bitmap) {
 	switch dim {
 	case 21:
 		// No marker.
-	case 25:
-		alignMarkers = append(alignMarkers, image.Pt(16, 16))
-	case 29:
-		alignMarkers = append(alignMarkers, image.Pt(20, 20))
 	default:
-		panic("unsupported qr code version")
+		alignMarkers = alignmentMarkers(dim)
 	}
 	for _, p := range alignMarkers {
 		fillMarker(engraved, p, alignmentMarker)
@@ -245,6 +305,72 @@ func bitmapForQRStatic(dim int) ([]image.Point, []image.Point, bitmap) {
 	return posMarkers, alignMarkers, engraved
 }
 
+// alignmentMarkers returns the upper left corners of the alignment markers
+// of a QR code.
+func alignmentMarkers(dim int) []image.Point {
+	version := (dim - 17) / 4
+	if version < 2 || version > len(alignmentCenters)+1 {
+		panic("unsupported qr code version")
+	}
+	centers := alignmentCenters[version-2]
+	var markers []image.Point
+	for _, y := range centers {
+		for _, x := range centers {
+			// Skip markers that overlap the position markers.
+			first, last := centers[0], centers[len(centers)-1]
+			if x == first && (y == first || y == last) || y == first && x == last {
+				continue
+			}
+			markers = append(markers, image.Pt(x-2, y-2))
+		}
+	}
+	return markers
+}
+
+// alignmentCenters lists the row and column coordinates of the centers
+// of alignment markers for QR versions 2 and up.
+var alignmentCenters = [][]int{
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+	{6, 30, 54},
+	{6, 32, 58},
+	{6, 34, 62},
+	{6, 26, 46, 66},
+	{6, 26, 48, 70},
+	{6, 26, 50, 74},
+	{6, 30, 54, 78},
+	{6, 30, 56, 82},
+	{6, 30, 58, 86},
+	{6, 34, 62, 90},
+	{6, 28, 50, 72, 94},
+	{6, 26, 50, 74, 98},
+	{6, 30, 54, 78, 102},
+	{6, 28, 54, 80, 106},
+	{6, 32, 58, 84, 110},
+	{6, 30, 58, 86, 114},
+	{6, 34, 62, 90, 118},
+	{6, 26, 50, 74, 98, 122},
+	{6, 30, 54, 78, 102, 126},
+	{6, 26, 52, 78, 104, 130},
+	{6, 30, 56, 82, 108, 134},
+	{6, 34, 60, 86, 112, 138},
+	{6, 30, 58, 86, 114, 142},
+	{6, 34, 62, 90, 118, 146},
+	{6, 30, 54, 78, 102, 126, 150},
+	{6, 24, 50, 76, 102, 128, 154},
+	{6, 28, 54, 80, 106, 132, 158},
+	{6, 32, 58, 84, 110, 136, 162},
+	{6, 26, 54, 82, 110, 138, 166},
+	{6, 30, 58, 86, 114, 142, 170},
+}
+
 // ConstantQR is like QR that engraves the QR code in a pattern independent of content,
 // except for the QR code version (size).
 func ConstantQR(strokeWidth, scale int, level qr.Level, content []byte) (Command, error) {
@@ -501,6 +627,7 @@ func (q constantQRCmd) centerOf(p image.Point) image.Point {
 }
 
 func (q constantQRCmd) Engrave(p Program) {
+	defer beginConstant(p)()
 	for _, off := range q.posMarkers {
 		q.engravePositionMarker(p, off)
 	}
@@ -525,6 +652,10 @@ func (q constantQRCmd) Engrave(p Program) {
 func (q constantQRCmd) engraveModule(p Program, center image.Point) {
 	sw := q.strokeWidth
 	switch q.scale {
+	case 2:
+		p.Line(center.Add(image.Pt(-sw, 0)))
+		p.Line(center.Add(image.Pt(-sw, -sw)))
+		p.Line(center.Add(image.Pt(0, -sw)))
 	case 3:
 		p.Line(center.Add(image.Pt(sw, 0)))
 		p.Line(center.Add(image.Pt(sw, sw)))
@@ -562,32 +693,42 @@ func manhattanLen(v image.Point) int {
 }
 
 type bitmap struct {
-	w    int
-	bits []uint32
+	w, h   int
+	stride int
+	bits   []uint64
 }
 
 func NewBitmap(w, h int) bitmap {
-	if w > 32 {
-		panic("bitset too wide")
-	}
+	stride := (w + 63) / 64
 	return bitmap{
-		w:    w,
-		bits: make([]uint32, h),
+		w:      w,
+		h:      h,
+		stride: stride,
+		bits:   make([]uint64, stride*h),
 	}
 }
 
 func (b bitmap) Set(p image.Point) {
-	if p.X < 0 || p.Y < 0 || p.X >= b.w || p.Y >= len(b.bits) {
+	if p.X < 0 || p.Y < 0 || p.X >= b.w || p.Y >= b.h {
 		panic("out of range")
 	}
-	b.bits[p.Y] |= 1 << p.X
+	b.bits[p.Y*b.stride+p.X/64] |= 1 << (p.X % 64)
+}
+
+// count returns the number of set bits.
+func (b bitmap) count() int {
+	n := 0
+	for _, w := range b.bits {
+		n += bits.OnesCount64(w)
+	}
+	return n
 }
 
 func (b bitmap) Get(p image.Point) bool {
-	if p.X < 0 || p.Y < 0 || p.X >= b.w || p.Y >= len(b.bits) {
+	if p.X < 0 || p.Y < 0 || p.X >= b.w || p.Y >= b.h {
 		return false
 	}
-	return b.bits[p.Y]&(1<<p.X) != 0
+	return b.bits[p.Y*b.stride+p.X/64]&(1<<(p.X%64)) != 0
 }
 
 type Rect image.Rectangle
@@ -600,7 +741,16 @@ func (r Rect) Engrave(p Program) {
 	p.Line(r.Min)
 }
 
-const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+// Alphabets for constant-time strings.
+const (
+	// Uppercase is the alphabet of seed words.
+	Uppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// Alphanumeric is the alphabet of digits and upper case
+	// letters.
+	Alphanumeric = "0123456789" + Uppercase
+	// Hex is the alphabet of upper case hexadecimal digits.
+	Hex = "0123456789ABCDEF"
+)
 
 // ConstantStringer can engrave text in a timing insensitive way.
 type ConstantStringer struct {
@@ -611,7 +761,8 @@ type ConstantStringer struct {
 	wordStart   image.Point
 	wordEnd     image.Point
 	dims        image.Point
-	alphabet    [len(alphabet)]constantRune
+	alphabet    string
+	runes       []constantRune
 }
 
 type constantRune struct {
@@ -646,10 +797,20 @@ func engraveConstantRune(p Program, face *vector.Face, em int, r rune) image.Poi
 	return image.Pt(adv*em/int(m.Height), em)
 }
 
+// NewConstantStringer is like NewConstantStringerAlphabet for the
+// Uppercase alphabet.
 func NewConstantStringer(face *vector.Face, em int, shortest, longest int) *ConstantStringer {
+	return NewConstantStringerAlphabet(face, em, Uppercase, shortest, longest)
+}
+
+// NewConstantStringerAlphabet returns a ConstantStringer for strings of
+// runes from alphabet, and lengths between shortest and longest. Every
+// rune must be drawable in a single stroke.
+func NewConstantStringerAlphabet(face *vector.Face, em int, alphabet string, shortest, longest int) *ConstantStringer {
 	var runes []*collectProgram
 	cs := &ConstantStringer{
-		longest: longest,
+		longest:  longest,
+		alphabet: alphabet,
 	}
 	// Collects path for every letter.
 	for _, r := range alphabet {
@@ -674,8 +835,7 @@ func NewConstantStringer(face *vector.Face, em int, shortest, longest int) *Cons
 	cs.wordStart = image.Pt(0, cs.dims.Y/2)
 	cs.wordEnd = image.Pt(endx, cs.dims.Y/2)
 	center := image.Pt(cs.dims.X/2, cs.dims.Y/2)
-	for i, r := range alphabet {
-		c := runes[i]
+	for _, c := range runes {
 		path := c.path
 		last := len(path) - 1
 		n := c.len
@@ -719,9 +879,9 @@ func NewConstantStringer(face *vector.Face, em int, shortest, longest int) *Cons
 				dir = -dir
 			}
 		}
-		cs.alphabet[r-'A'] = constantRune{
+		cs.runes = append(cs.runes, constantRune{
 			path: path,
-		}
+		})
 		start, end := path[0], path[len(path)-1]
 		if d := manhattanDist(center, start); d > cs.moveDist {
 			cs.moveDist = d
@@ -733,7 +893,18 @@ func NewConstantStringer(face *vector.Face, em int, shortest, longest int) *Cons
 	return cs
 }
 
+// Measure returns the nominal size of every string, independent of
+// content and length.
+func (c *ConstantStringer) Measure() image.Point {
+	return image.Pt(c.longest*c.dims.X, c.dims.Y)
+}
+
 func (c *ConstantStringer) String(txt string) Command {
+	for _, r := range txt {
+		if !strings.ContainsRune(c.alphabet, r) {
+			panic(fmt.Errorf("rune not in constant alphabet: %s", string(r)))
+		}
+	}
 	cmd := &constantStringCmd{
 		cs:  c,
 		txt: txt,
@@ -818,12 +989,13 @@ type constantStringCmd struct {
 }
 
 func (s *constantStringCmd) Engrave(p Program) {
+	defer beginConstant(p)()
 	needle := s.cs.wordStart
 	p.Move(needle)
 	repeats := s.cs.longest / len(s.txt)
 	rest := s.cs.longest - repeats*len(s.txt)
 	for i, r := range s.txt {
-		l := s.cs.alphabet[r-'A']
+		l := s.cs.runes[strings.IndexRune(s.cs.alphabet, r)]
 		extra := 0
 		if rest > 0 {
 			rest--
@@ -874,6 +1046,10 @@ func (s *constantStringCmd) Engrave(p Program) {
 func constantMove(p Program, dst, src image.Point, dist int) {
 	// extra is the distance to spend.
 	extra := dist - manhattanDist(dst, src)
+	if extra == 0 {
+		p.Move(dst)
+		return
+	}
 	if dst == src {
 		if extra == 1 {
 			panic("dst and src coincides and dist allows no movement")
@@ -1060,6 +1236,7 @@ func (r *Rasterizer) Line(p image.Point) {
 		r.started = true
 	}
 	r.dasher.Line(rasterx.ToFixedP(float64(pf[0]), float64(pf[1])))
+	r.p = pf
 }
 
 func (r *Rasterizer) Move(p image.Point) {
@@ -1088,6 +1265,15 @@ func NewRasterizer(img draw.Image, dr image.Rectangle, scale, strokeWidth float3
 	return r
 }
 
+// SetColor rasterizes the lines so far and sets the color of
+// the lines that follow.
+func (r *Rasterizer) SetColor(c color.Color) {
+	r.Rasterize()
+	r.started = false
+	r.dasher.Clear()
+	r.dasher.SetColor(c)
+}
+
 func (r *Rasterizer) Rasterize() {
 	if r.started {
 		r.dasher.Stop(false)
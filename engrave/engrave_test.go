@@ -1,10 +1,14 @@
 package engrave
 
 import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
 	"image"
 	"io"
 	"math/rand"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -57,6 +61,78 @@ func TestConstantString(t *testing.T) {
 	}
 }
 
+func TestConstantQRVersions(t *testing.T) {
+	rng := rand.New(rand.NewSource(45))
+	const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	patterns := make(map[int]*pattern)
+	for i := 0; i < 50; i++ {
+		content := []byte("UR:CRYPTO-OUTPUT/")
+		n := 50 + rng.Intn(500)
+		for j := 0; j < n; j++ {
+			content = append(content, chars[rng.Intn(len(chars))])
+		}
+		lvl := qr.M
+		cmd, err := ConstantQR(3, 2, lvl, content)
+		if err != nil {
+			t.Fatalf("content: %s: %v", content, err)
+		}
+		qrc, err := qr.Encode(string(content), lvl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dim := qrc.Size
+		want := bitmapForQR(qrc)
+		_, _, got := bitmapForQRStatic(dim)
+		for _, p := range cmd.(constantQRCmd).plan {
+			got.Set(p)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("content: %s: engraving plan doesn't match QR code", content)
+		}
+		pt := new(pattern)
+		cmd.Engrave(pt)
+		if prev, ok := patterns[dim]; ok && !reflect.DeepEqual(prev, pt) {
+			t.Errorf("content: %s: engraving pattern differs from another QR code of size %d", content, dim)
+		}
+		patterns[dim] = pt
+	}
+}
+
+func TestConstantStringAlphabets(t *testing.T) {
+	rng := rand.New(rand.NewSource(46))
+	tests := []struct {
+		alphabet          string
+		shortest, longest int
+	}{
+		{Hex, 8, 8},
+		{Alphanumeric, 1, 18},
+		{"0123456789/", 3, 5},
+	}
+	for _, test := range tests {
+		s := NewConstantStringerAlphabet(constant.Font, 1000, test.alphabet, test.shortest, test.longest)
+		var first *pattern
+		for i := 0; i < 100; i++ {
+			n := test.shortest + rng.Intn(test.longest-test.shortest+1)
+			txt := make([]byte, n)
+			for j := range txt {
+				txt[j] = test.alphabet[rng.Intn(len(test.alphabet))]
+			}
+			cmd := s.String(string(txt))
+			bounds := image.Rect(0, 0, s.longest*s.dims.X, s.dims.Y)
+			if moves := measureMoves(cmd); !moves.In(bounds) {
+				t.Errorf("%s movement bounds %v are not inside bounds %v", txt, moves, bounds)
+			}
+			pt := new(pattern)
+			cmd.Engrave(pt)
+			if first == nil {
+				first = pt
+			} else if !reflect.DeepEqual(first.pattern, pt.pattern) {
+				t.Errorf("%s: engraving pattern differs", txt)
+			}
+		}
+	}
+}
+
 func FuzzConstantQR(f *testing.F) {
 	f.Fuzz(func(t *testing.T, entropy []byte) {
 		if len(entropy) < 16 {
@@ -105,3 +181,160 @@ func measureMoves(c Command) image.Rectangle {
 	}
 	return b
 }
+
+func TestOptimizer(t *testing.T) {
+	rec := new(recording)
+	o := NewOptimizer(rec)
+	o.Move(image.Pt(100, 0))
+	o.Line(image.Pt(110, 0))
+	o.Move(image.Pt(10, 0))
+	o.Line(image.Pt(20, 0))
+	o.Line(image.Pt(30, 0))
+	o.Move(image.Pt(30, 0))
+	o.Line(image.Pt(30, 10))
+	o.Move(image.Pt(50, 0))
+	o.Line(image.Pt(40, 0))
+	o.Flush()
+	want := recording{
+		{instruction: instruction{p: image.Pt(10, 0)}},
+		{instruction: instruction{line: true, p: image.Pt(30, 0)}},
+		{instruction: instruction{line: true, p: image.Pt(30, 10)}},
+		{instruction: instruction{p: image.Pt(40, 0)}},
+		{instruction: instruction{line: true, p: image.Pt(50, 0)}},
+		{instruction: instruction{p: image.Pt(100, 0)}},
+		{instruction: instruction{line: true, p: image.Pt(110, 0)}},
+	}
+	if !reflect.DeepEqual(*rec, want) {
+		t.Errorf("optimized to\n%v\nwant\n%v", *rec, want)
+	}
+	if got, want := o.Travel(), (Travel{Before: 100 + 100 + 20, After: 10 + 10 + 50}); got != want {
+		t.Errorf("got travel %+v, want %+v", got, want)
+	}
+}
+
+func TestOptimizerConstant(t *testing.T) {
+	cs := NewConstantStringer(constant.Font, 1000, bip39.ShortestWord, bip39.LongestWord)
+	cmd := Commands{
+		Offset(0, 5000, String(constant.Font, 1000, "12 3")),
+		Offset(3000, 0, cs.String("ZOO")),
+		Offset(0, 0, String(constant.Font, 1000, "4 5")),
+		Offset(3000, 2000, cs.String("ABANDON")),
+		Offset(0, 8000, String(constant.Font, 1000, "V1")),
+	}
+	orig := new(recording)
+	cmd.Engrave(orig)
+	opt, travel := Optimize(cmd)
+	if travel.After > travel.Before {
+		t.Errorf("optimization increased travel from %d to %d", travel.Before, travel.After)
+	}
+	blocks := func(r recording) []recording {
+		var blocks []recording
+		depth := 0
+		for _, inst := range r {
+			switch {
+			case inst.begin:
+				if depth == 0 {
+					blocks = append(blocks, nil)
+				}
+				depth++
+			case inst.end:
+				depth--
+			case depth > 0:
+				blocks[len(blocks)-1] = append(blocks[len(blocks)-1], inst)
+			}
+		}
+		return blocks
+	}
+	want, got := blocks(*orig), blocks(opt.(recording))
+	if len(want) != 2 {
+		t.Fatalf("got %d constant blocks, want 2", len(want))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("optimization changed constant time blocks")
+	}
+	if m1, m2 := Measure(cmd), Measure(opt); m1 != m2 {
+		t.Errorf("optimization changed bounds from %v to %v", m1, m2)
+	}
+}
+
+func TestExport(t *testing.T) {
+	cmd := String(constant.Font, 1000, "SEEDHAMMER")
+	bounds := Measure(cmd)
+	const unit, strokeWidth = 0.01, 0.3
+
+	svgBuf := new(bytes.Buffer)
+	svg := NewSVG(svgBuf, bounds, unit, strokeWidth)
+	cmd.Engrave(svg)
+	if err := svg.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Width  string `xml:"width,attr"`
+		Height string `xml:"height,attr"`
+		Path   struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+	}
+	if err := xml.Unmarshal(svgBuf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if want := fmtFloat(float32(bounds.Dx())*unit) + "mm"; doc.Width != want {
+		t.Errorf("SVG width %q, want %q", doc.Width, want)
+	}
+	if doc.Path.D == "" {
+		t.Error("SVG path is empty")
+	}
+
+	pdfBuf := new(bytes.Buffer)
+	pdf := NewPDF(pdfBuf, bounds, unit, strokeWidth)
+	cmd.Engrave(pdf)
+	if err := pdf.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	out := pdfBuf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatal("malformed PDF document")
+	}
+	// Verify the cross-reference table.
+	xref := out[strings.LastIndex(out, "startxref\n")+len("startxref\n"):]
+	xrefOff, err := strconv.Atoi(strings.TrimSuffix(xref, "\n%%EOF\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := strings.Split(out[xrefOff:], "\n")[3:7]
+	for i, e := range entries {
+		off, err := strconv.Atoi(e[:10])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(out[off:], want) {
+			t.Errorf("xref entry %d doesn't point to object", i+1)
+		}
+	}
+
+	counter := new(countingProgram)
+	cmd.Engrave(counter)
+	gcodeBuf := new(bytes.Buffer)
+	gcode := NewGCode(gcodeBuf, bounds, unit, DefaultGCode)
+	cmd.Engrave(gcode)
+	if err := gcode.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	gcodeOut := gcodeBuf.String()
+	if n := strings.Count(gcodeOut, "\nG1 "); n != counter.lines {
+		t.Errorf("G-code contains %d lines, want %d", n, counter.lines)
+	}
+	if strings.Count(gcodeOut, DefaultGCode.PenDown) >= strings.Count(gcodeOut, DefaultGCode.PenUp)+1 {
+		t.Error("G-code doesn't raise the pen after every stroke")
+	}
+}
+
+type countingProgram struct {
+	lines int
+}
+
+func (c *countingProgram) Move(p image.Point) {}
+
+func (c *countingProgram) Line(p image.Point) {
+	c.lines++
+}
@@ -0,0 +1,236 @@
+package engrave
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SVG is a Program that writes an SVG image in millimeter units.
+type SVG struct {
+	w           io.Writer
+	bounds      image.Rectangle
+	unit        float32
+	strokeWidth float32
+	path        bytes.Buffer
+	pos         image.Point
+	started     bool
+}
+
+// NewSVG creates an SVG covering bounds, in program units. Unit is
+// the size of a program unit in millimeters, and strokeWidth is the
+// width of lines in millimeters.
+func NewSVG(w io.Writer, bounds image.Rectangle, unit, strokeWidth float32) *SVG {
+	return &SVG{
+		w:           w,
+		bounds:      bounds,
+		unit:        unit,
+		strokeWidth: strokeWidth,
+	}
+}
+
+func (s *SVG) Move(p image.Point) {
+	s.pos = p
+	s.started = false
+}
+
+func (s *SVG) Line(p image.Point) {
+	if !s.started {
+		fmt.Fprintf(&s.path, "M%d %d", s.pos.X, s.pos.Y)
+		s.started = true
+	}
+	fmt.Fprintf(&s.path, "L%d %d", p.X, p.Y)
+	s.pos = p
+}
+
+// Flush writes the image.
+func (s *SVG) Flush() error {
+	b := s.bounds
+	bw := bufio.NewWriter(s.w)
+	fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%smm" height="%smm" viewBox="%d %d %d %d">
+<path fill="none" stroke="black" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round" d="`,
+		fmtFloat(float32(b.Dx())*s.unit), fmtFloat(float32(b.Dy())*s.unit),
+		b.Min.X, b.Min.Y, b.Dx(), b.Dy(), fmtFloat(s.strokeWidth/s.unit))
+	bw.Write(s.path.Bytes())
+	fmt.Fprintf(bw, "\"/>\n</svg>\n")
+	return bw.Flush()
+}
+
+// PDF is a Program that writes a single page PDF document at 1:1
+// scale.
+type PDF struct {
+	w           io.Writer
+	bounds      image.Rectangle
+	unit        float32
+	strokeWidth float32
+	content     bytes.Buffer
+	pos         image.Point
+	started     bool
+}
+
+// NewPDF is like NewSVG, but for PDF documents.
+func NewPDF(w io.Writer, bounds image.Rectangle, unit, strokeWidth float32) *PDF {
+	return &PDF{
+		w:           w,
+		bounds:      bounds,
+		unit:        unit,
+		strokeWidth: strokeWidth,
+	}
+}
+
+func (p *PDF) Move(to image.Point) {
+	p.pos = to
+	p.started = false
+}
+
+func (p *PDF) Line(to image.Point) {
+	if !p.started {
+		fmt.Fprintf(&p.content, "%d %d m\n", p.pos.X, p.pos.Y)
+		p.started = true
+	}
+	fmt.Fprintf(&p.content, "%d %d l\n", to.X, to.Y)
+	p.pos = to
+}
+
+// Flush writes the document.
+func (p *PDF) Flush() error {
+	const pointsPerMM = 72 / 25.4
+	b := p.bounds
+	// Scale program units to points and flip the y axis
+	// to place the origin at the upper left corner.
+	s := p.unit * pointsPerMM
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "%s 0 0 %s %s %s cm\n", fmtFloat(s), fmtFloat(-s), fmtFloat(-float32(b.Min.X)*s), fmtFloat(float32(b.Max.Y)*s))
+	fmt.Fprintf(&content, "%s w 1 J 1 j\n", fmtFloat(p.strokeWidth/p.unit))
+	content.Write(p.content.Bytes())
+	content.WriteString("S\n")
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Contents 4 0 R >>",
+			fmtFloat(float32(b.Dx())*s), fmtFloat(float32(b.Dy())*s)),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()),
+	}
+	var doc bytes.Buffer
+	doc.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = doc.Len()
+		fmt.Fprintf(&doc, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := doc.Len()
+	fmt.Fprintf(&doc, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&doc, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := p.w.Write(doc.Bytes())
+	return err
+}
+
+// GCodeConfig configures G-code output.
+type GCodeConfig struct {
+	// FeedRate and TravelRate are the speeds of lines and
+	// moves, in millimeters per minute.
+	FeedRate, TravelRate float32
+	// PenDown and PenUp are the G-code macros for lowering
+	// and raising the tool.
+	PenDown, PenUp string
+}
+
+// DefaultGCode is a configuration for pen plotters with a
+// servo controlled pen.
+var DefaultGCode = GCodeConfig{
+	FeedRate:   600,
+	TravelRate: 3000,
+	PenDown:    "M3 S90\nG4 P0.2",
+	PenUp:      "M3 S30\nG4 P0.2",
+}
+
+// GCode is a Program that writes G-code in absolute millimeter
+// coordinates. The y axis is flipped to place the origin at the
+// lower left corner of the bounds.
+type GCode struct {
+	w      *bufio.Writer
+	bounds image.Rectangle
+	unit   float32
+	config GCodeConfig
+	down   bool
+	lines  int
+	err    error
+}
+
+// NewGCode creates a G-code Program for bounds, in program units.
+// Unit is the size of a program unit in millimeters.
+func NewGCode(w io.Writer, bounds image.Rectangle, unit float32, config GCodeConfig) *GCode {
+	g := &GCode{
+		w:      bufio.NewWriter(w),
+		bounds: bounds,
+		unit:   unit,
+		config: config,
+	}
+	g.printf("G21\nG90\n%s\n", config.PenUp)
+	return g
+}
+
+func (g *GCode) printf(format string, args ...any) {
+	if g.err != nil {
+		return
+	}
+	out := fmt.Sprintf(format, args...)
+	g.lines += strings.Count(out, "\n")
+	_, g.err = g.w.WriteString(out)
+}
+
+// Lines returns the number of lines of G-code written so far,
+// including lines not yet flushed.
+func (g *GCode) Lines() int {
+	return g.lines
+}
+
+func (g *GCode) coords(p image.Point) (string, string) {
+	x := float32(p.X-g.bounds.Min.X) * g.unit
+	y := float32(g.bounds.Max.Y-p.Y) * g.unit
+	return fmtFloat(x), fmtFloat(y)
+}
+
+func (g *GCode) Move(p image.Point) {
+	if g.down {
+		g.printf("%s\n", g.config.PenUp)
+		g.down = false
+	}
+	x, y := g.coords(p)
+	g.printf("G0 X%s Y%s F%s\n", x, y, fmtFloat(g.config.TravelRate))
+}
+
+func (g *GCode) Line(p image.Point) {
+	if !g.down {
+		g.printf("%s\n", g.config.PenDown)
+		g.down = true
+	}
+	x, y := g.coords(p)
+	g.printf("G1 X%s Y%s F%s\n", x, y, fmtFloat(g.config.FeedRate))
+}
+
+// Flush raises the tool and writes the buffered G-code.
+func (g *GCode) Flush() error {
+	if g.down {
+		g.printf("%s\n", g.config.PenUp)
+		g.down = false
+	}
+	g.printf("M2\n")
+	if g.err != nil {
+		return g.err
+	}
+	return g.w.Flush()
+}
+
+func fmtFloat(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
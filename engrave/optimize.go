@@ -0,0 +1,380 @@
+package engrave
+
+import (
+	"image"
+)
+
+// ConstantProgram is implemented by programs that must know which
+// instructions are engraved in constant time.
+type ConstantProgram interface {
+	// BeginConstant marks the start of instructions that must be
+	// engraved exactly as given. Blocks may nest.
+	BeginConstant()
+	// EndConstant marks the end of the most recently begun block.
+	EndConstant()
+}
+
+func (t *transformedProgram) BeginConstant() {
+	if c, ok := t.prog.(ConstantProgram); ok {
+		c.BeginConstant()
+	}
+}
+
+func (t *transformedProgram) EndConstant() {
+	if c, ok := t.prog.(ConstantProgram); ok {
+		c.EndConstant()
+	}
+}
+
+func (o *offsetProgram) BeginConstant() {
+	if c, ok := o.prog.(ConstantProgram); ok {
+		c.BeginConstant()
+	}
+}
+
+func (o *offsetProgram) EndConstant() {
+	if c, ok := o.prog.(ConstantProgram); ok {
+		c.EndConstant()
+	}
+}
+
+// beginConstant marks the start of a constant time block if p
+// supports it, and returns the function for marking its end.
+func beginConstant(p Program) func() {
+	c, ok := p.(ConstantProgram)
+	if !ok {
+		return func() {}
+	}
+	c.BeginConstant()
+	return c.EndConstant
+}
+
+// Travel is the distance travelled between strokes, summed over the
+// moves with manhattanDist, the metric of the constant time engravers.
+type Travel struct {
+	Before, After int
+}
+
+// Optimizer is a Program that buffers strokes and reorders them to
+// minimize the travel between them. Consecutive collinear lines are
+// merged and zero-length moves dropped. Instructions from constant
+// time blocks are passed through unchanged and in order, and strokes
+// are never moved across them.
+//
+// Labels are not preserved.
+type Optimizer struct {
+	prog Program
+	// items are the buffered strokes and constant blocks.
+	items []optItem
+	// pos is the needle position after the buffered instructions.
+	pos      image.Point
+	constant int
+	travel   Travel
+}
+
+type optItem struct {
+	// stroke is a Move to its first point followed by Lines
+	// through the rest.
+	stroke []image.Point
+	// block is a constant time block.
+	block []instruction
+}
+
+type instruction struct {
+	line bool
+	p    image.Point
+}
+
+func NewOptimizer(p Program) *Optimizer {
+	return &Optimizer{prog: p}
+}
+
+func (o *Optimizer) BeginConstant() {
+	if o.constant == 0 {
+		o.items = append(o.items, optItem{block: []instruction{}})
+	}
+	o.constant++
+}
+
+func (o *Optimizer) EndConstant() {
+	o.constant--
+}
+
+func (o *Optimizer) Move(p image.Point) {
+	if o.constant > 0 {
+		o.appendBlock(instruction{line: false, p: p})
+		return
+	}
+	if p == o.pos {
+		return
+	}
+	o.pos = p
+	if s := o.lastStroke(); s != nil && len(*s) == 1 {
+		// Replace a move that was never followed by a line.
+		(*s)[0] = p
+		return
+	}
+	o.items = append(o.items, optItem{stroke: []image.Point{p}})
+}
+
+func (o *Optimizer) Line(p image.Point) {
+	if o.constant > 0 {
+		o.appendBlock(instruction{line: true, p: p})
+		return
+	}
+	if p == o.pos {
+		return
+	}
+	s := o.lastStroke()
+	if s == nil {
+		o.items = append(o.items, optItem{stroke: []image.Point{o.pos}})
+		s = &o.items[len(o.items)-1].stroke
+	}
+	o.pos = p
+	if n := len(*s); n >= 2 && collinear((*s)[n-2], (*s)[n-1], p) {
+		(*s)[n-1] = p
+		return
+	}
+	*s = append(*s, p)
+}
+
+func (o *Optimizer) appendBlock(inst instruction) {
+	b := &o.items[len(o.items)-1].block
+	*b = append(*b, inst)
+	o.pos = inst.p
+}
+
+// lastStroke returns the stroke being buffered, or nil if the last
+// item is a constant block.
+func (o *Optimizer) lastStroke() *[]image.Point {
+	if len(o.items) == 0 || o.items[len(o.items)-1].block != nil {
+		return nil
+	}
+	return &o.items[len(o.items)-1].stroke
+}
+
+// collinear reports whether the line from b to c continues the line
+// from a to b in the same direction.
+func collinear(a, b, c image.Point) bool {
+	d1, d2 := b.Sub(a), c.Sub(b)
+	return d1.X*d2.Y == d1.Y*d2.X && d1.X*d2.X+d1.Y*d2.Y > 0
+}
+
+// Flush optimizes and engraves the buffered instructions.
+func (o *Optimizer) Flush() {
+	pos := image.Point{}
+	start := 0
+	for i := 0; i <= len(o.items); i++ {
+		if i < len(o.items) && o.items[i].block == nil {
+			continue
+		}
+		var run, strokes [][]image.Point
+		for _, it := range o.items[start:i] {
+			run = append(run, it.stroke)
+			// Moves not followed by lines are dropped, except
+			// for the final position.
+			if len(it.stroke) > 1 {
+				strokes = append(strokes, it.stroke)
+			}
+		}
+		// end is the fixed end of the run, if any.
+		var end *image.Point
+		if i < len(o.items) {
+			if len(o.items[i].block) > 0 {
+				end = &o.items[i].block[0].p
+			}
+			o.travel.Before += tourLen(pos, run, end)
+		} else {
+			if len(run) > 0 && len(run[len(run)-1]) == 1 {
+				end = &run[len(run)-1][0]
+			}
+			o.travel.Before += tourLen(pos, run, nil)
+		}
+		strokes = optimizeTour(pos, strokes, end)
+		o.travel.After += tourLen(pos, strokes, end)
+		for _, s := range strokes {
+			if s[0] != pos {
+				o.prog.Move(s[0])
+			}
+			for _, p := range s[1:] {
+				o.prog.Line(p)
+			}
+			pos = s[len(s)-1]
+		}
+		if i == len(o.items) {
+			if end != nil && *end != pos {
+				o.prog.Move(*end)
+			}
+			break
+		}
+		endConstant := beginConstant(o.prog)
+		for _, inst := range o.items[i].block {
+			if inst.line {
+				o.prog.Line(inst.p)
+			} else {
+				o.prog.Move(inst.p)
+			}
+			pos = inst.p
+		}
+		endConstant()
+		start = i + 1
+	}
+	o.items = o.items[:0]
+}
+
+// Travel returns the travel distance of the flushed instructions
+// before and after optimization. Travel into constant blocks is
+// included, travel inside them is not.
+func (o *Optimizer) Travel() Travel {
+	return o.travel
+}
+
+// tourLen measures the travel from start through strokes, and
+// to end if not nil.
+func tourLen(start image.Point, strokes [][]image.Point, end *image.Point) int {
+	d := 0
+	pos := start
+	for _, s := range strokes {
+		d += manhattanDist(pos, s[0])
+		pos = s[len(s)-1]
+	}
+	if end != nil {
+		d += manhattanDist(pos, *end)
+	}
+	return d
+}
+
+// optimizeTour orders strokes by nearest neighbor from start,
+// then improves the order with 2-opt. Strokes may be reversed.
+func optimizeTour(start image.Point, strokes [][]image.Point, end *image.Point) [][]image.Point {
+	if len(strokes) < 2 {
+		return strokes
+	}
+	tour := make([][]image.Point, 0, len(strokes))
+	left := append([][]image.Point(nil), strokes...)
+	pos := start
+	for len(left) > 0 {
+		best, bestDist, rev := 0, -1, false
+		for i, s := range left {
+			if d := manhattanDist(pos, s[0]); bestDist == -1 || d < bestDist {
+				best, bestDist, rev = i, d, false
+			}
+			if d := manhattanDist(pos, s[len(s)-1]); d < bestDist {
+				best, bestDist, rev = i, d, true
+			}
+		}
+		s := left[best]
+		if rev {
+			s = reversed(s)
+		}
+		tour = append(tour, s)
+		pos = s[len(s)-1]
+		left[best] = left[len(left)-1]
+		left = left[:len(left)-1]
+	}
+	// 2-opt: reverse tour[i:j+1] if it shortens the tour. Bound
+	// the number of passes to keep large engravings fast.
+	const maxPasses = 10
+	for pass := 0; pass < maxPasses; pass++ {
+		improved := false
+		for i := 0; i < len(tour)-1; i++ {
+			prev := start
+			if i > 0 {
+				prev = tour[i-1][len(tour[i-1])-1]
+			}
+			first := tour[i][0]
+			for j := i + 1; j < len(tour); j++ {
+				last := tour[j][len(tour[j])-1]
+				before := manhattanDist(prev, first)
+				after := manhattanDist(prev, last)
+				var next *image.Point
+				if j+1 < len(tour) {
+					next = &tour[j+1][0]
+				} else {
+					next = end
+				}
+				if next != nil {
+					before += manhattanDist(last, *next)
+					after += manhattanDist(first, *next)
+				}
+				if after < before {
+					for a, b := i, j; a < b; a, b = a+1, b-1 {
+						tour[a], tour[b] = tour[b], tour[a]
+					}
+					for k := i; k <= j; k++ {
+						tour[k] = reversed(tour[k])
+					}
+					first = tour[i][0]
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return tour
+}
+
+func reversed(s []image.Point) []image.Point {
+	r := make([]image.Point, len(s))
+	for i, p := range s {
+		r[len(s)-1-i] = p
+	}
+	return r
+}
+
+// recording is a Command that replays recorded instructions,
+// including constant time blocks.
+type recording []recordedInstruction
+
+type recordedInstruction struct {
+	instruction
+	// begin and end mark constant time blocks.
+	begin, end bool
+}
+
+func (r *recording) Move(p image.Point) {
+	*r = append(*r, recordedInstruction{instruction: instruction{p: p}})
+}
+
+func (r *recording) Line(p image.Point) {
+	*r = append(*r, recordedInstruction{instruction: instruction{line: true, p: p}})
+}
+
+func (r *recording) BeginConstant() {
+	*r = append(*r, recordedInstruction{begin: true})
+}
+
+func (r *recording) EndConstant() {
+	*r = append(*r, recordedInstruction{end: true})
+}
+
+func (r recording) Engrave(p Program) {
+	c, constant := p.(ConstantProgram)
+	for _, inst := range r {
+		switch {
+		case inst.begin:
+			if constant {
+				c.BeginConstant()
+			}
+		case inst.end:
+			if constant {
+				c.EndConstant()
+			}
+		case inst.line:
+			p.Line(inst.p)
+		default:
+			p.Move(inst.p)
+		}
+	}
+}
+
+// Optimize the instructions of cmd with an Optimizer.
+func Optimize(cmd Command) (Command, Travel) {
+	rec := new(recording)
+	o := NewOptimizer(rec)
+	cmd.Engrave(o)
+	o.Flush()
+	return *rec, o.Travel()
+}
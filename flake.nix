@@ -137,6 +137,9 @@
                 ./scripts/config --enable VIDEO_DW9807_VCM
                 # Enable SPI.
                 ./scripts/config --enable SPI_BCM2835
+                # Enable the VideoCore mailbox device for reading the
+                # customer OTP.
+                ./scripts/config --enable BCM_VCIO
                 # Enable FTDI USB serial driver.
                 ./scripts/config --enable USB_SERIAL
                 ./scripts/config --enable USB_SERIAL_FTDI_SIO
@@ -314,17 +317,23 @@
                 }
 
                 # Create disk image.
-                dd if=/dev/zero of=disk.img bs=1M count=14
+                dd if=/dev/zero of=disk.img bs=1M count=15
                 ${pkgs.util-linux}/bin/sfdisk disk.img <<EOF
                   label: dos
                   label-id: 0xceedb0ad
 
-                  disk.img1 : type=c, bootable
+                  disk.img1 : size=13MiB, type=c, bootable
+                  disk.img2 : type=c
                 EOF
 
-                # Create boot partition.
+                # Create settings partition.
                 START=$(${pkgs.util-linux}/bin/fdisk -l -o Start disk.img|tail -n 1)
                 SECTORS=$(${pkgs.util-linux}/bin/fdisk -l -o Sectors disk.img|tail -n 1)
+                ${pkgs.dosfstools}/bin/mkfs.vfat --invariant -i 5e771265 -n shconfig disk.img --offset $START $(sectorsToBlocks $SECTORS)
+
+                # Create boot partition.
+                START=$(${pkgs.util-linux}/bin/fdisk -l -o Start disk.img|tail -n 2|head -n 1)
+                SECTORS=$(${pkgs.util-linux}/bin/fdisk -l -o Sectors disk.img|tail -n 2|head -n 1)
                 ${pkgs.dosfstools}/bin/mkfs.vfat --invariant -i deadbeef -n seedhammer disk.img --offset $START $(sectorsToBlocks $SECTORS)
                 OFFSET=$(sectorsToBytes $START)
 
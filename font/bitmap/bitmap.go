@@ -18,36 +18,52 @@ func NewFace(data []byte) *Face {
 	return &Face{data}
 }
 
+// Kern is the kerning of a pair of runes. Runes are limited
+// to the basic multilingual plane.
 type Kern struct {
-	R1, R2 uint8
+	R1, R2 uint16
 	Kern   fixed.Int26_6
 }
 
 type Glyph struct {
+	Rune     rune
 	Advance  fixed.Int26_6
-	ImageOff uint16
+	ImageOff uint32
 	Rect     alpha4.Rectangle
 }
 
 const (
-	indexLen      = unicode.MaxASCII
-	indexElemSize = 4 + 2 + 4
-	KernElemSize  = 2*1 + 4
+	// ASCIILen is the number of ASCII glyphs at the start of
+	// the index, in rune order. The remaining glyphs are
+	// sorted by rune.
+	ASCIILen      = unicode.MaxASCII
+	indexElemSize = 4 + 4 + 4 + 4
+	KernElemSize  = 2*2 + 4
 )
 
 const (
-	offAscent   = 0
-	offDescent  = offAscent + 4
-	offHeight   = offDescent + 4
-	offIndex    = offHeight + 4
-	offNumKerns = offIndex + indexLen*indexElemSize
-	OffKerns    = offNumKerns + 2
-
-	offIndexAdvance  = 0
+	offAscent    = 0
+	offDescent   = offAscent + 4
+	offHeight    = offDescent + 4
+	offNumGlyphs = offHeight + 4
+	offIndex     = offNumGlyphs + 2
+
+	offIndexRune     = 0
+	offIndexAdvance  = offIndexRune + 4
 	offIndexImageOff = offIndexAdvance + 4
-	offIndexBounds   = offIndexImageOff + 2
+	offIndexBounds   = offIndexImageOff + 4
 )
 
+// OffKerns returns the offset of the kerning table of a face
+// with nglyphs glyphs.
+func OffKerns(nglyphs int) int {
+	return offNumKerns(nglyphs) + 2
+}
+
+func offNumKerns(nglyphs int) int {
+	return offIndex + nglyphs*indexElemSize
+}
+
 var bo = binary.LittleEndian
 
 func (f *Face) Metrics() font.Metrics {
@@ -58,15 +74,35 @@ func (f *Face) Metrics() font.Metrics {
 	}
 }
 
+func (f *Face) numGlyphs() int {
+	return int(bo.Uint16(f.data[offNumGlyphs:]))
+}
+
 func (f *Face) glyphFor(r rune) (Glyph, bool) {
-	if r < 0 || int(r) >= indexLen {
+	if r < 0 {
 		return Glyph{}, false
 	}
-	index := f.data[offIndex:offNumKerns]
-	g := index[r*indexElemSize : (r+1)*indexElemSize]
+	n := f.numGlyphs()
+	index := f.data[offIndex:offNumKerns(n)]
+	elem := func(i int) []byte {
+		return index[i*indexElemSize : (i+1)*indexElemSize]
+	}
+	i := int(r)
+	if i >= ASCIILen {
+		var found bool
+		i, found = sort.Find(n-ASCIILen, func(i int) int {
+			return int(r - rune(bo.Uint32(elem(ASCIILen + i)[offIndexRune:])))
+		})
+		if !found {
+			return Glyph{}, false
+		}
+		i += ASCIILen
+	}
+	g := elem(i)
 	return Glyph{
+		Rune:     r,
 		Advance:  fixed.Int26_6(bo.Uint32(g[offIndexAdvance:])),
-		ImageOff: bo.Uint16(g[offIndexImageOff:]),
+		ImageOff: bo.Uint32(g[offIndexImageOff:]),
 		Rect: alpha4.Rectangle{
 			MinX: int8(g[offIndexBounds+0]),
 			MinY: int8(g[offIndexBounds+1]),
@@ -85,10 +121,12 @@ func (f *Face) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
 }
 
 func (f *Face) Kern(r1, r2 rune) fixed.Int26_6 {
-	nkerns := bo.Uint16(f.data[offNumKerns:])
-	kerns := f.data[OffKerns : OffKerns+nkerns*KernElemSize]
-	i, found := sort.Find(int(nkerns), func(i int) int {
-		kr1, kr2 := rune(kerns[i*KernElemSize+0]), rune(kerns[i*KernElemSize+1])
+	off := offNumKerns(f.numGlyphs())
+	nkerns := int(bo.Uint16(f.data[off:]))
+	kerns := f.data[off+2 : off+2+nkerns*KernElemSize]
+	i, found := sort.Find(nkerns, func(i int) int {
+		k := kerns[i*KernElemSize:]
+		kr1, kr2 := rune(bo.Uint16(k)), rune(bo.Uint16(k[2:]))
 		if d := int(r1 - kr1); d != 0 {
 			return d
 		}
@@ -97,7 +135,7 @@ func (f *Face) Kern(r1, r2 rune) fixed.Int26_6 {
 	if !found {
 		return 0
 	}
-	return fixed.Int26_6(bo.Uint32(kerns[i*KernElemSize+2:]))
+	return fixed.Int26_6(bo.Uint32(kerns[i*KernElemSize+4:]))
 }
 
 func (f *Face) Glyph(r rune) (*alpha4.Image, fixed.Int26_6, bool) {
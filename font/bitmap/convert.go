@@ -15,15 +15,18 @@ import (
 	"image/draw"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
 
 	"seedhammer.com/font/bitmap"
+	"seedhammer.com/gui/locale"
 	simage "seedhammer.com/image"
 	"seedhammer.com/image/alpha4"
 )
@@ -32,12 +35,15 @@ var (
 	packageName = flag.String("package", "main", "package name")
 	ppem        = flag.Int("ppem", 16, "pixels per em")
 	alphabet    = flag.String("alphabet", "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~", "alphabet to generate")
+	localeName  = flag.String("locale", "", "add the runes of a locale to the alphabet")
+	fallback    = flag.String("fallback", "", "font file for runes missing from the input font")
 )
 
 type Face struct {
 	Metrics font.Metrics
-	// Index maps an ASCII rune to its glyph.
-	Index  [unicode.MaxASCII]bitmap.Glyph
+	// Index contains the ASCII glyphs in rune order, followed
+	// by the remaining glyphs sorted by rune.
+	Index  []bitmap.Glyph
 	Kerns  []bitmap.Kern
 	Pixels []byte
 }
@@ -56,7 +62,20 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
-	conv, err := parse(ttf, *ppem)
+	var fallbackTTF []byte
+	if *fallback != "" {
+		fallbackTTF, err = os.ReadFile(*fallback)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	runes, err := alphabetRunes()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	conv, err := parse(ttf, fallbackTTF, *ppem, runes)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to parse %q: %v\n", infile, err)
 		os.Exit(1)
@@ -67,14 +86,47 @@ func main() {
 	}
 }
 
+// alphabetRunes returns the sorted runes of the alphabet, the
+// locale and the ASCII whitespace.
+func alphabetRunes() ([]rune, error) {
+	set := make(map[rune]bool)
+	for _, r := range *alphabet {
+		set[r] = true
+	}
+	if *localeName != "" {
+		c := locale.Lookup(*localeName)
+		if c == nil {
+			return nil, fmt.Errorf("unknown locale %q", *localeName)
+		}
+		for r := range c.Runes() {
+			set[r] = true
+		}
+	}
+	for i := rune(0); i < bitmap.ASCIILen; i++ {
+		if unicode.IsSpace(i) {
+			set[i] = true
+		}
+	}
+	var runes []rune
+	for r := range set {
+		if r > 0xffff {
+			return nil, fmt.Errorf("rune %q is outside the basic multilingual plane", r)
+		}
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes, nil
+}
+
 func generate(fname string, conv *Face) error {
 	outname := flag.Arg(1)
 	var output bytes.Buffer
 	ext := filepath.Ext(fname)
 	fname = fname[:len(fname)-len(ext)]
-	datafile := fmt.Sprintf("%s%d.bin", outname, *ppem)
+	basename := fmt.Sprintf("%s%d%s", outname, *ppem, *localeName)
+	datafile := basename + ".bin"
 	first, n := utf8.DecodeRuneInString(outname)
-	name := string(unicode.ToTitle(first)) + outname[n:]
+	name := fmt.Sprintf("%s%s%d%s", string(unicode.ToTitle(first)), outname[n:], *ppem, strings.ToUpper(*localeName))
 
 	fmt.Fprintf(&output, "// Code generated by font/bitmap/convert.go; DO NOT EDIT.\n\npackage %s\n\n", *packageName)
 	fmt.Fprintf(&output, "import (\n")
@@ -82,33 +134,40 @@ func generate(fname string, conv *Face) error {
 	fmt.Fprintf(&output, "    \"unsafe\"\n")
 	fmt.Fprintf(&output, "    \"seedhammer.com/font/bitmap\"\n")
 	fmt.Fprintf(&output, ")\n\n")
-	fmt.Fprintf(&output, "var %[1]s%[2]d = bitmap.NewFace(unsafe.Slice(unsafe.StringData(data%[3]s%[2]d), len(data%[3]s%[2]d)))\n", name, *ppem, outname)
+	fmt.Fprintf(&output, "var %[1]s = bitmap.NewFace(unsafe.Slice(unsafe.StringData(data%[2]s), len(data%[2]s)))\n", name, basename)
 	fmt.Fprintf(&output, "//go:embed %s\n", datafile)
-	fmt.Fprintf(&output, "var data%s%d string\n", outname, *ppem)
+	fmt.Fprintf(&output, "var data%s string\n", basename)
 
 	var data []byte
 	bo := binary.LittleEndian
 	data = bo.AppendUint32(data, uint32(conv.Metrics.Ascent))
 	data = bo.AppendUint32(data, uint32(conv.Metrics.Descent))
 	data = bo.AppendUint32(data, uint32(conv.Metrics.Height))
+	nglyphs := uint16(len(conv.Index))
+	if int(nglyphs) != len(conv.Index) {
+		return errors.New("glyph index overflows uint16")
+	}
 	nkerns := uint16(len(conv.Kerns))
 	if int(nkerns) != len(conv.Kerns) {
 		return errors.New("kern table overflows uint16")
 	}
-	pixelStart := bitmap.OffKerns + len(conv.Kerns)*bitmap.KernElemSize
+	pixelStart := bitmap.OffKerns(len(conv.Index)) + len(conv.Kerns)*bitmap.KernElemSize
+	data = bo.AppendUint16(data, nglyphs)
 	for _, g := range conv.Index {
+		data = bo.AppendUint32(data, uint32(g.Rune))
 		data = bo.AppendUint32(data, uint32(g.Advance))
-		imgOff := int(g.ImageOff) + pixelStart
-		off16 := uint16(imgOff)
-		if int(off16) != imgOff {
-			return errors.New("pixel offset overflows uint16")
+		imgOff := int64(g.ImageOff) + int64(pixelStart)
+		off32 := uint32(imgOff)
+		if int64(off32) != imgOff {
+			return errors.New("pixel offset overflows uint32")
 		}
-		data = bo.AppendUint16(data, off16)
+		data = bo.AppendUint32(data, off32)
 		data = append(data, uint8(g.Rect.MinX), uint8(g.Rect.MinY), uint8(g.Rect.MaxX), uint8(g.Rect.MaxY))
 	}
 	data = bo.AppendUint16(data, nkerns)
 	for _, k := range conv.Kerns {
-		data = append(data, k.R1, k.R2)
+		data = bo.AppendUint16(data, k.R1)
+		data = bo.AppendUint16(data, k.R2)
 		data = bo.AppendUint32(data, uint32(k.Kern))
 	}
 	if len(data) != pixelStart {
@@ -120,18 +179,40 @@ func generate(fname string, conv *Face) error {
 		fmt.Fprintf(os.Stderr, "failed to format output: %v\n", err)
 		os.Exit(2)
 	}
-	gofile := fmt.Sprintf("%s%d.go", outname, *ppem)
+	gofile := basename + ".go"
 	if err := os.WriteFile(gofile, formatted, 0o600); err != nil {
 		return err
 	}
 	return os.WriteFile(datafile, data, 0o600)
 }
 
-func face(ttf []byte, ppem int) (font.Face, error) {
+// source is a parsed font file.
+type source struct {
+	font *opentype.Font
+	face font.Face
+	buf  sfnt.Buffer
+}
+
+func load(ttf []byte, ppem int) (*source, error) {
 	f, err := opentype.Parse(ttf)
 	if err != nil {
 		return nil, err
 	}
+	face, err := face(f, ppem)
+	if err != nil {
+		return nil, err
+	}
+	return &source{font: f, face: face}, nil
+}
+
+// Has reports whether the font contains a glyph for r. Unlike
+// font.Face.Glyph, missing glyphs are not substituted.
+func (s *source) Has(r rune) bool {
+	x, err := s.font.GlyphIndex(&s.buf, r)
+	return err == nil && x != 0
+}
+
+func face(f *opentype.Font, ppem int) (font.Face, error) {
 	face, err := opentype.NewFace(f, &opentype.FaceOptions{
 		Size:    float64(ppem),
 		DPI:     72, // Size is in pixels.
@@ -143,12 +224,19 @@ func face(ttf []byte, ppem int) (font.Face, error) {
 	return face, nil
 }
 
-func parse(ttf []byte, ppem int) (*Face, error) {
-	f, err := face(ttf, ppem)
+func parse(ttf, fallbackTTF []byte, ppem int, runes []rune) (*Face, error) {
+	primary, err := load(ttf, ppem)
 	if err != nil {
 		return nil, err
 	}
-	m := f.Metrics()
+	var fallback *source
+	if fallbackTTF != nil {
+		fallback, err = load(fallbackTTF, ppem)
+		if err != nil {
+			return nil, fmt.Errorf("fallback: %w", err)
+		}
+	}
+	m := primary.face.Metrics()
 	face := &Face{
 		Metrics: font.Metrics{
 			Ascent:  m.Ascent,
@@ -156,50 +244,37 @@ func parse(ttf []byte, ppem int) (*Face, error) {
 			Height:  m.Height,
 		},
 	}
-	// Add whitespaces to alphabet.
-	var b strings.Builder
-	for _, r := range *alphabet {
-		i := uint8(r)
-		if rune(i) != r {
-			return nil, errors.New("alphabet overflows uint8")
-		}
-		b.WriteByte(i)
+	inAlphabet := make(map[rune]bool)
+	for _, r := range runes {
+		inAlphabet[r] = true
 	}
-	for i := uint8(0); i < unicode.MaxASCII; i++ {
-		if unicode.IsSpace(rune(i)) {
-			b.WriteByte(i)
-		}
-	}
-	alph := b.String()
-	for i := range face.Index {
-		r := rune(i)
-		off := uint16(len(face.Pixels))
+	var missing []rune
+	addGlyph := func(r rune) error {
+		off := uint32(len(face.Pixels))
 		if int(off) != len(face.Pixels) {
-			return nil, errors.New("pixel offset overflows uint16")
+			return errors.New("pixel offset overflows uint32")
 		}
-		face.Index[r].ImageOff = off
-		if strings.IndexRune(alph, r) == -1 {
-			continue
+		face.Index = append(face.Index, bitmap.Glyph{Rune: r, ImageOff: off})
+		if !inAlphabet[r] {
+			return nil
 		}
-		// Brute force n² pairs to construct kerning table. It works
-		// as long as alphabets are small.
-		for _, r2 := range alph {
-			if strings.IndexRune(alph, r2) == -1 {
-				continue
-			}
-			k := f.Kern(r, r2)
-			if k == 0 {
-				continue
+		src := primary
+		if !primary.Has(r) {
+			switch {
+			case fallback != nil && fallback.Has(r):
+				src = fallback
+			case r >= bitmap.ASCIILen:
+				missing = append(missing, r)
+				return nil
 			}
-			face.Kerns = append(face.Kerns, bitmap.Kern{R1: uint8(r), R2: uint8(r2), Kern: k})
 		}
-		dr, mask, maskp, adv, ok := f.Glyph(fixed.P(0, 0), r)
+		dr, mask, maskp, adv, ok := src.face.Glyph(fixed.P(0, 0), r)
 		if !ok {
-			continue
+			return nil
 		}
 		alpha, ok := mask.(*image.Alpha)
 		if !ok {
-			return nil, fmt.Errorf("bitmap image type %T is not supported", mask)
+			return fmt.Errorf("bitmap image type %T is not supported", mask)
 		}
 		alpha.Rect = dr
 		rcrop := simage.Crop(alpha)
@@ -210,12 +285,48 @@ func parse(ttf []byte, ppem int) (*Face, error) {
 			MaxY: int8(rcrop.Max.Y),
 		})
 		if crop.Bounds() != rcrop {
-			return nil, errors.New("glyph bounds overflows int8")
+			return errors.New("glyph bounds overflows int8")
 		}
 		draw.DrawMask(crop, crop.Bounds(), image.NewUniform(color.Black), image.Point{}, alpha, maskp.Add(crop.Bounds().Min), draw.Src)
-		face.Index[r].Advance = adv
-		face.Index[r].Rect = crop.Rect
+		g := &face.Index[len(face.Index)-1]
+		g.Advance = adv
+		g.Rect = crop.Rect
 		face.Pixels = append(face.Pixels, crop.Pix...)
+		return nil
+	}
+	// The ASCII glyphs are always present and indexed by rune.
+	for r := rune(0); r < bitmap.ASCIILen; r++ {
+		if err := addGlyph(r); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range runes {
+		if r < bitmap.ASCIILen {
+			continue
+		}
+		if err := addGlyph(r); err != nil {
+			return nil, err
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("runes missing from font: %q", string(missing))
+	}
+	// Brute force n² pairs to construct the sorted kerning table.
+	// Kerning is only defined between glyphs of the primary font.
+	var kerned []rune
+	for _, r := range runes {
+		if primary.Has(r) {
+			kerned = append(kerned, r)
+		}
+	}
+	for _, r1 := range kerned {
+		for _, r2 := range kerned {
+			k := primary.face.Kern(r1, r2)
+			if k == 0 {
+				continue
+			}
+			face.Kerns = append(face.Kerns, bitmap.Kern{R1: uint16(r1), R2: uint16(r2), Kern: k})
+		}
 	}
 	return face, nil
 }
@@ -8,3 +8,17 @@ package poppins
 //go:generate go run ../bitmap/convert.go -package poppins -ppem 23 Poppins-Bold.ttf bold
 // Size 45 is only for progress indicators
 //go:generate go run ../bitmap/convert.go -package poppins -ppem 45 -alphabet "0123456789%" Poppins-Bold.ttf boldprogress
+
+// Locale variants of the user interface faces. Japanese glyphs come from M+.
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale de Poppins-Regular.ttf regular
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale de Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 20 -locale de Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 23 -locale de Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale es Poppins-Regular.ttf regular
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale es Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 20 -locale es Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 23 -locale es Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale ja -fallback ../mplus/mplus-1p-regular.ttf Poppins-Regular.ttf regular
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 16 -locale ja -fallback ../mplus/mplus-1p-regular.ttf Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 20 -locale ja -fallback ../mplus/mplus-1p-regular.ttf Poppins-Bold.ttf bold
+//go:generate go run ../bitmap/convert.go -package poppins -ppem 23 -locale ja -fallback ../mplus/mplus-1p-regular.ttf Poppins-Bold.ttf bold
@@ -0,0 +1,14 @@
+// Code generated by font/bitmap/convert.go; DO NOT EDIT.
+
+package poppins
+
+import (
+	_ "embed"
+	"seedhammer.com/font/bitmap"
+	"unsafe"
+)
+
+var Regular16DE = bitmap.NewFace(unsafe.Slice(unsafe.StringData(dataregular16de), len(dataregular16de)))
+
+//go:embed regular16de.bin
+var dataregular16de string
@@ -0,0 +1,14 @@
+// Code generated by font/bitmap/convert.go; DO NOT EDIT.
+
+package poppins
+
+import (
+	_ "embed"
+	"seedhammer.com/font/bitmap"
+	"unsafe"
+)
+
+var Regular16ES = bitmap.NewFace(unsafe.Slice(unsafe.StringData(dataregular16es), len(dataregular16es)))
+
+//go:embed regular16es.bin
+var dataregular16es string
@@ -0,0 +1,14 @@
+// Code generated by font/bitmap/convert.go; DO NOT EDIT.
+
+package poppins
+
+import (
+	_ "embed"
+	"seedhammer.com/font/bitmap"
+	"unsafe"
+)
+
+var Regular16JA = bitmap.NewFace(unsafe.Slice(unsafe.StringData(dataregular16ja), len(dataregular16ja)))
+
+//go:embed regular16ja.bin
+var dataregular16ja string
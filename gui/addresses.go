@@ -0,0 +1,250 @@
+package gui
+
+import (
+	"image"
+	"strconv"
+	"strings"
+
+	"seedhammer.com/address"
+	"seedhammer.com/bc/urtypes"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/gui/widget"
+)
+
+// bech32Keys is the keyboard layout of the bech32 character set.
+var bech32Keys = [][]rune{
+	[]rune("023456789"),
+	[]rune("QWERTYUP"),
+	[]rune("ASDFGHJKL"),
+	[]rune("ZXCVNM⌫"),
+}
+
+// bech32Prefix returns the human readable part and separator of
+// the addresses of desc, or the empty string if they're not
+// encoded in bech32.
+func bech32Prefix(desc urtypes.OutputDescriptor) string {
+	switch desc.Script {
+	case urtypes.P2WPKH, urtypes.P2WSH, urtypes.P2TR:
+	default:
+		return ""
+	}
+	if len(desc.Keys) == 0 {
+		return ""
+	}
+	return desc.Keys[0].Network.Bech32HRPSegwit + "1"
+}
+
+// AddressKeyboardScreen inputs a bech32 address.
+type AddressKeyboardScreen struct {
+	// Prefix is the fixed start of the address, such
+	// as "bc1".
+	Prefix string
+
+	deriver *address.Deriver
+	kbd     *Keyboard
+}
+
+// address returns the entered address.
+func (s *AddressKeyboardScreen) address() string {
+	return s.Prefix + strings.ToLower(s.kbd.Word)
+}
+
+// valid reports whether the entered address is complete.
+func (s *AddressKeyboardScreen) valid() bool {
+	_, err := s.deriver.Normalize(s.address())
+	return err == nil
+}
+
+func (s *AddressKeyboardScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (string, Result) {
+	if s.kbd == nil {
+		s.kbd = newKeyboard(ctx, nil, bech32Keys)
+	}
+	for {
+		s.kbd.Update(ctx)
+		e, ok := ctx.Next(Button1, Button2)
+		if !ok {
+			break
+		}
+		if !e.Click {
+			continue
+		}
+		switch e.Button {
+		case Button1:
+			if s.kbd.Word == "" {
+				return "", ResultCancelled
+			}
+			s.kbd.rune('⌫')
+		case Button2:
+			if s.valid() {
+				return s.address(), ResultComplete
+			}
+		}
+	}
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.InputAddress))
+
+	screen := layout.Rectangle{Max: dims}
+	_, content := screen.CutTop(leadingSize)
+	content, _ = content.CutBottom(8)
+
+	kbdsz := s.kbd.Layout(ctx, ops.Begin(), th)
+	op.Position(ops, ops.End(), content.S(kbdsz))
+
+	// The address, truncated from the start to fit.
+	style := ctx.Styles.word
+	const pad = 8
+	width := dims.X - 2*assets.NavBtnPrimary.Bounds().Dx()
+	full := s.address()
+	addr := full
+	for i := 1; i < len(full) && widget.Label(op.Ctx{}, style, th.Background, addr).X > width-2*pad; i++ {
+		addr = ".." + full[i:]
+	}
+	sz := widget.Label(ops.Begin(), style, th.Background, addr)
+	word := ops.End()
+	r := image.Rectangle{Max: image.Pt(width, sz.Y)}
+	r.Min.Y -= 3
+	op.MaskOp(ops.Begin(), assets.ButtonFocused.For(r))
+	op.ColorOp(ops, th.Text)
+	op.Position(ops, word, image.Pt((width-sz.X)/2, 0))
+	top, _ := content.CutBottom(kbdsz.Y)
+	op.Position(ops, ops.End(), top.Center(r.Size()).Add(image.Pt(0, 3)))
+
+	icn := assets.IconBack
+	if s.kbd.Word != "" {
+		icn = assets.IconBackspace
+	}
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: icn},
+	)
+	if s.valid() {
+		layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button2, Style: StylePrimary, Icon: assets.IconCheckmark})
+	}
+	return "", ResultNone
+}
+
+// addressSearch searches the receive and change addresses of
+// a wallet in the background.
+type addressSearch struct {
+	gap      uint32
+	searched uint32
+	progress chan uint32
+	result   chan searchResult
+	cancel   chan struct{}
+}
+
+type searchResult struct {
+	Match address.Match
+	Found bool
+	Err   error
+}
+
+// searchBatch is the number of indices searched between
+// progress updates.
+const searchBatch = 50
+
+func startSearch(ctx *Context, d *address.Deriver, addr string, gap uint32) *addressSearch {
+	s := &addressSearch{
+		gap:      gap,
+		progress: make(chan uint32, 1),
+		result:   make(chan searchResult, 1),
+		cancel:   make(chan struct{}),
+	}
+	wakeup := ctx.Platform.Wakeup
+	go func() {
+		defer wakeup()
+		for start := uint32(0); start < gap; start += searchBatch {
+			end := start + searchBatch
+			if end > gap {
+				end = gap
+			}
+			m, found, err := d.Search(addr, start, end)
+			if found || err != nil {
+				s.result <- searchResult{Match: m, Found: found, Err: err}
+				return
+			}
+			select {
+			case <-s.cancel:
+				return
+			case <-s.progress:
+			default:
+			}
+			s.progress <- end
+			wakeup()
+		}
+		s.result <- searchResult{}
+	}()
+	return s
+}
+
+// Stop the search.
+func (s *addressSearch) Stop() {
+	close(s.cancel)
+}
+
+// Poll returns the result of the search, if complete.
+func (s *addressSearch) Poll() (searchResult, bool) {
+	select {
+	case p := <-s.progress:
+		s.searched = p
+	default:
+	}
+	select {
+	case res := <-s.result:
+		return res, true
+	default:
+		return searchResult{}, false
+	}
+}
+
+// verify starts the search for the scanned or entered address, or
+// returns a warning if it's not valid.
+func (s *AddressesScreen) verify(ctx *Context, addr string) *ErrorScreen {
+	if _, err := s.deriver.Normalize(addr); err != nil {
+		return &ErrorScreen{
+			Title: ctx.Text(locale.InvalidAddress),
+			Body:  ctx.Text(locale.InvalidAddressMsg),
+		}
+	}
+	s.search = startSearch(ctx, s.deriver, addr, ctx.Settings.Gap())
+	return nil
+}
+
+// searchResult converts the result of a search to a message.
+func (s *AddressesScreen) searchResult(ctx *Context, res searchResult) *ErrorScreen {
+	switch {
+	case res.Err != nil:
+		return NewErrorScreen(ctx, res.Err)
+	case !res.Found:
+		return &ErrorScreen{
+			Title: ctx.Text(locale.AddressNotFound),
+			Body:  ctx.Text(locale.AddressNotFoundMsg, "Gap", strconv.FormatUint(uint64(s.search.gap), 10)),
+		}
+	}
+	msg := locale.ReceiveAddressMsg
+	if res.Match.Change {
+		msg = locale.ChangeAddressMsg
+	}
+	return &ErrorScreen{
+		Title: ctx.Text(locale.AddressFound),
+		Body:  ctx.Text(msg, "Index", strconv.FormatUint(uint64(res.Match.Index), 10)),
+	}
+}
+
+// layoutSearch draws the progress of the search.
+func (s *AddressesScreen) layoutSearch(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) {
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.VerifyAddress))
+	r := layout.Rectangle{Max: dims}
+	txt := ctx.Text(locale.SearchingAddresses,
+		"N", strconv.FormatUint(uint64(s.search.searched), 10),
+		"Total", strconv.FormatUint(uint64(s.search.gap), 10),
+	)
+	sz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*16, th.Text, txt)
+	op.Position(ops, ops.End(), r.Center(sz))
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+	)
+}
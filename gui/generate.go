@@ -0,0 +1,420 @@
+package gui
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"image"
+	"strconv"
+	"strings"
+
+	"seedhammer.com/bip39"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/gui/widget"
+)
+
+// GenerateScreen generates a new seed from dice rolls or coin
+// flips, optionally mixed with camera sensor noise.
+type GenerateScreen struct {
+	seedlen *ChoiceScreen
+	method  *ChoiceScreen
+	mixin   *ChoiceScreen
+	input   *EntropyScreen
+	noise   *NoiseScreen
+	warning *ErrorScreen
+	nwords  int
+	mode    entropyMode
+	// words are the words rolled so far in diceWords
+	// mode.
+	words   bip39.Mnemonic
+	entropy []byte
+}
+
+type entropyMode int
+
+const (
+	// diceWords maps 5 die rolls to every word with
+	// bip39.DiceToWord.
+	diceWords entropyMode = iota
+	// diceHash hashes die rolls into the entropy, like
+	// the Coldcard.
+	diceHash
+	// coinFlips enters the entropy bits directly.
+	coinFlips
+)
+
+// noiseFrames is the number of camera frames hashed into
+// the camera noise.
+const noiseFrames = 8
+
+const (
+	diceSymbols = "123456"
+	coinSymbols = "01"
+)
+
+func NewGenerateScreen(ctx *Context) *GenerateScreen {
+	return &GenerateScreen{
+		seedlen: &ChoiceScreen{
+			Title:   ctx.Text(locale.GenerateSeed),
+			Lead:    ctx.Text(locale.NumWords),
+			Choices: []string{ctx.Text(locale.Choice12Words), ctx.Text(locale.Choice24Words)},
+		},
+	}
+}
+
+func (s *GenerateScreen) startInput(ctx *Context) {
+	switch s.mode {
+	case diceWords:
+		s.input = &EntropyScreen{
+			Title:   ctx.Text(locale.WordOf, "N", strconv.Itoa(len(s.words)+1), "Total", strconv.Itoa(s.nwords)),
+			Lead:    locale.RollOf,
+			Symbols: diceSymbols,
+			N:       len(bip39.Roll{}),
+		}
+	case diceHash:
+		s.input = &EntropyScreen{
+			Title:   ctx.Text(locale.Dice),
+			Lead:    locale.RollOf,
+			Symbols: diceSymbols,
+			N:       bip39.DiceRolls(s.nwords),
+		}
+	case coinFlips:
+		s.input = &EntropyScreen{
+			Title:   ctx.Text(locale.CoinFlips),
+			Lead:    locale.FlipOf,
+			Symbols: coinSymbols,
+			N:       bip39.EntropySize(s.nwords) * 8,
+		}
+	}
+}
+
+// addInput adds the values of a completed input screen and
+// reports whether the entropy is complete.
+func (s *GenerateScreen) addInput(ctx *Context, vals []int) bool {
+	switch s.mode {
+	case diceWords:
+		var roll bip39.Roll
+		for i, v := range vals {
+			roll[i] = v + 1
+		}
+		w, ok := bip39.DiceToWord(roll)
+		if !ok {
+			s.warning = &ErrorScreen{
+				Title: ctx.Text(locale.InvalidRoll),
+				Body:  ctx.Text(locale.InvalidRollMsg),
+			}
+			return false
+		}
+		s.words = append(s.words, w)
+		if len(s.words) < s.nwords {
+			return false
+		}
+		// The rolls of the final word are only partly used,
+		// because the word includes the checksum.
+		s.entropy = s.words.FixChecksum().Entropy()
+	case diceHash:
+		rolls := make([]int, len(vals))
+		for i, v := range vals {
+			rolls[i] = v + 1
+		}
+		s.entropy = bip39.DiceEntropy(rolls, s.nwords)
+	case coinFlips:
+		s.entropy = make([]byte, len(vals)/8)
+		for i, v := range vals {
+			s.entropy[i/8] |= byte(v) << (7 - i%8)
+		}
+	}
+	return true
+}
+
+// mixNoise mixes noise into entropy. The result is at least as
+// random as entropy, even if noise is predictable.
+func mixNoise(entropy, noise []byte) []byte {
+	mixed := make([]byte, len(entropy))
+	for i := range mixed {
+		mixed[i] = entropy[i] ^ noise[i]
+	}
+	return mixed
+}
+
+// seed returns the generated mnemonic. Its final word is the
+// checksum word of the entropy.
+func (s *GenerateScreen) seed() bip39.Mnemonic {
+	return bip39.New(s.entropy)
+}
+
+func (s *GenerateScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (bip39.Mnemonic, Result) {
+	for {
+		switch {
+		case s.warning != nil:
+			if s.warning.Update(ctx) {
+				s.warning = nil
+				s.startInput(ctx)
+				continue
+			}
+			s.input.Layout(ctx, ops, th, dims)
+			s.warning.Layout(ctx, ops.Begin(), th, dims)
+			ops.End().Add(ops)
+			return nil, ResultNone
+		case s.noise != nil:
+			noise, status := s.noise.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return nil, ResultNone
+			}
+			s.noise = nil
+			if status == ResultCancelled {
+				continue
+			}
+			s.entropy = mixNoise(s.entropy, noise)
+			return s.seed(), ResultComplete
+		case s.mixin != nil:
+			choice, status := s.mixin.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return nil, ResultNone
+			case ResultCancelled:
+				// Discard the entropy.
+				s.mixin = nil
+				s.entropy = nil
+				s.words = nil
+				continue
+			}
+			if choice == 0 {
+				return s.seed(), ResultComplete
+			}
+			s.noise = new(NoiseScreen)
+			continue
+		case s.input != nil:
+			vals, status := s.input.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return nil, ResultNone
+			case ResultCancelled:
+				s.input = nil
+				s.words = nil
+				continue
+			}
+			if !s.addInput(ctx, vals) {
+				if s.warning == nil {
+					s.startInput(ctx)
+				}
+				continue
+			}
+			s.input = nil
+			s.mixin = &ChoiceScreen{
+				Title:   ctx.Text(locale.CameraNoise),
+				Lead:    ctx.Text(locale.MixCameraNoise),
+				Choices: []string{ctx.Text(locale.ChoiceSkip), ctx.Text(locale.ChoiceCamera)},
+			}
+			continue
+		case s.method != nil:
+			choice, status := s.method.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return nil, ResultNone
+			case ResultCancelled:
+				s.method = nil
+				continue
+			}
+			s.mode = entropyMode(choice)
+			s.words = nil
+			s.startInput(ctx)
+			continue
+		default:
+			choice, status := s.seedlen.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return nil, ResultNone
+			case ResultCancelled:
+				return nil, ResultCancelled
+			}
+			s.nwords = []int{12, 24}[choice]
+			s.method = &ChoiceScreen{
+				Title: ctx.Text(locale.GenerateSeed),
+				Lead:  ctx.Text(locale.EntropySource),
+				Choices: []string{
+					ctx.Text(locale.ChoiceDiceWords),
+					ctx.Text(locale.ChoiceDiceHash),
+					ctx.Text(locale.ChoiceCoinFlips),
+				},
+			}
+			continue
+		}
+	}
+}
+
+// EntropyScreen inputs a sequence of die rolls or coin flips.
+type EntropyScreen struct {
+	Title string
+	// Lead describes a single input, such as [locale.RollOf].
+	Lead locale.ID
+	// Symbols are the possible values, in order.
+	Symbols string
+	// N is the number of values to input.
+	N        int
+	values   []int
+	selected int
+}
+
+func (s *EntropyScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) ([]int, Result) {
+	for {
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down, CCW, CW, Rune)
+		if !ok {
+			break
+		}
+		n := len(s.Symbols)
+		switch e.Button {
+		case Button1:
+			if !e.Click {
+				break
+			}
+			if len(s.values) == 0 {
+				return nil, ResultCancelled
+			}
+			s.values = s.values[:len(s.values)-1]
+		case Button3, Center:
+			if e.Click {
+				s.values = append(s.values, s.selected)
+			}
+		case Up, CW:
+			if e.Pressed {
+				s.selected = (s.selected + 1) % n
+			}
+		case Down, CCW:
+			if e.Pressed {
+				s.selected = (s.selected + n - 1) % n
+			}
+		case Rune:
+			if i := strings.IndexRune(s.Symbols, e.Rune); i != -1 {
+				s.values = append(s.values, i)
+			}
+		}
+		if len(s.values) == s.N {
+			vals := s.values
+			s.values = nil
+			return vals, ResultComplete
+		}
+	}
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, s.Title)
+
+	r := layout.Rectangle{Max: dims}
+	_, content := r.CutTop(leadingSize)
+	content, lead := content.CutBottom(leadingSize)
+
+	selsz := widget.Label(ops.Begin(), ctx.Styles.progress, th.Text, s.Symbols[s.selected:s.selected+1])
+	selpos := content.Center(selsz)
+	op.Position(ops, ops.End(), selpos)
+
+	// Show the most recent values above the selection.
+	const recent = 10
+	var hist strings.Builder
+	start := len(s.values) - recent
+	if start < 0 {
+		start = 0
+	}
+	for i, v := range s.values[start:] {
+		if i > 0 {
+			hist.WriteByte(' ')
+		}
+		hist.WriteByte(s.Symbols[v])
+	}
+	histsz := widget.Label(ops.Begin(), ctx.Styles.word, th.Text, hist.String())
+	op.Position(ops, ops.End(), image.Pt((dims.X-histsz.X)/2, selpos.Y-histsz.Y-8))
+
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text, ctx.Text(s.Lead, "N", strconv.Itoa(len(s.values)+1), "Total", strconv.Itoa(s.N)))
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+
+	icnBack := assets.IconBack
+	if len(s.values) > 0 {
+		icnBack = assets.IconBackspace
+	}
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: icnBack},
+		NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconCheckmark},
+	)
+	return nil, ResultNone
+}
+
+// NoiseScreen hashes camera frames into noise.
+type NoiseScreen struct {
+	frames int
+	hash   hash.Hash
+	err    error
+}
+
+func (s *NoiseScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) ([]byte, Result) {
+	for {
+		e, ok := ctx.Next(Button1)
+		if !ok {
+			break
+		}
+		if e.Click {
+			return nil, ResultCancelled
+		}
+	}
+	if s.hash == nil {
+		s.hash = sha256.New()
+	}
+	ctx.Platform.CameraFrame(dims)
+	for {
+		f, ok := ctx.Frame()
+		if !ok {
+			break
+		}
+		s.err = f.Error()
+		if s.err != nil {
+			continue
+		}
+		switch img := f.Image().(type) {
+		case *image.YCbCr:
+			s.hash.Write(img.Y)
+			s.hash.Write(img.Cb)
+			s.hash.Write(img.Cr)
+		default:
+			b := img.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					r, g, b, _ := img.At(x, y).RGBA()
+					s.hash.Write([]byte{byte(r), byte(g), byte(b)})
+				}
+			}
+		}
+		s.frames++
+		if s.frames == noiseFrames {
+			return s.hash.Sum(nil), ResultComplete
+		}
+	}
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.CameraNoise))
+	r := layout.Rectangle{Max: dims}
+	_, content := r.CutTop(leadingSize)
+	content, lead := content.CutBottom(leadingSize)
+	progress := fmt.Sprintf("%d%%", s.frames*100/noiseFrames)
+	sz := widget.Label(ops.Begin(), ctx.Styles.progress, th.Text, progress)
+	op.Position(ops, ops.End(), content.Center(sz))
+	msg := ctx.Text(locale.CollectingNoise)
+	if s.err != nil {
+		msg = ctx.Text(locale.CameraError, "Err", s.err.Error())
+	}
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text, msg)
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+	layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack})
+	return nil, ResultNone
+}
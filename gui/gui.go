@@ -7,9 +7,10 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"io"
 	"log"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -17,38 +18,49 @@ import (
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"seedhammer.com/address"
+	"seedhammer.com/auditlog"
 	"seedhammer.com/backup"
+	"seedhammer.com/bc/bytewords"
+	"seedhammer.com/bc/fountain"
 	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
+	"seedhammer.com/driver"
 	"seedhammer.com/driver/mjolnir"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font/constant"
 	"seedhammer.com/gui/assets"
 	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
 	"seedhammer.com/gui/op"
 	"seedhammer.com/gui/saver"
 	"seedhammer.com/gui/text"
 	"seedhammer.com/gui/widget"
 	"seedhammer.com/nonstandard"
 	"seedhammer.com/seedqr"
+	"seedhammer.com/settings"
 )
 
 const nbuttons = 8
 
 type Context struct {
-	Buttons      [nbuttons]bool
-	Repeats      [nbuttons]time.Time
-	Platform     Platform
-	Styles       Styles
-	Version      string
-	Calibrated   bool
-	NoSDCard     bool
-	RotateCamera bool
-
-	events []Event
-	wakeup struct {
+	Buttons  [nbuttons]bool
+	Repeats  [nbuttons]time.Time
+	Platform Platform
+	Styles   Styles
+	Version  string
+	NoSDCard bool
+	// Settings are the persistent device settings.
+	Settings settings.Settings
+
+	// audit are the audit entries not yet stored.
+	audit []auditlog.Entry
+	// unstored is set if the settings changed since they were
+	// last stored.
+	unstored bool
+	events   []Event
+	wakeup   struct {
 		timeouts chan time.Time
 		quit     chan struct{}
 	}
@@ -57,8 +69,8 @@ type Context struct {
 func NewContext(pl Platform) *Context {
 	c := &Context{
 		Platform: pl,
-		Styles:   NewStyles(),
 	}
+	c.SetSettings(settings.Default())
 	c.wakeup.timeouts = make(chan time.Time)
 	c.wakeup.quit = make(chan struct{}, 1)
 	// Wakeup goroutine is not running.
@@ -68,6 +80,61 @@ func NewContext(pl Platform) *Context {
 	return c
 }
 
+// SetSettings replaces the device settings and updates the styles
+// to match the locale.
+func (c *Context) SetSettings(s settings.Settings) {
+	c.Settings = s
+	c.Styles = NewStyles(s.Locale)
+}
+
+// Text returns the message for id in the locale of the device, with
+// the placeholders substituted by args. See [locale.Text].
+func (c *Context) Text(id locale.ID, args ...string) string {
+	return locale.Text(c.Settings.Locale, id, args...)
+}
+
+// StoreSettings persists the device settings. The settings remain in
+// effect until restart, so failures are logged and the settings are
+// stored again by FlushSettings.
+func (c *Context) StoreSettings() {
+	c.unstored = true
+	c.FlushSettings()
+}
+
+// FlushSettings stores the device settings if they changed since they
+// were last stored. Like FlushAudit, it does nothing while the SD card
+// is removed.
+func (c *Context) FlushSettings() {
+	if !c.unstored || c.NoSDCard {
+		return
+	}
+	if err := c.Platform.StoreSettings(c.Settings); err != nil {
+		log.Printf("settings: %v", err)
+		return
+	}
+	c.unstored = false
+}
+
+// Audit records an entry in the audit log.
+func (c *Context) Audit(e auditlog.Entry) {
+	c.audit = append(c.audit, e)
+	c.FlushAudit()
+}
+
+// FlushAudit stores the pending audit entries. The entries are kept
+// for a later attempt if the log is unavailable, typically because
+// the SD card is removed.
+func (c *Context) FlushAudit() {
+	if len(c.audit) == 0 || c.NoSDCard {
+		return
+	}
+	if err := c.Platform.AppendAudit(c.audit); err != nil {
+		log.Printf("audit: %v", err)
+		return
+	}
+	c.audit = nil
+}
+
 func (c *Context) WakeupAt(t time.Time) {
 	select {
 	case <-c.wakeup.quit:
@@ -177,12 +244,27 @@ type program int
 
 const (
 	backupWallet program = iota
+	recoverWallet
+	generateSeed
+	multisigSession
+	engravingHistory
+	deviceSettings
+
+	numPrograms = int(deviceSettings) + 1
 )
 
 type AddressesScreen struct {
 	addresses [2][]string
 	page      int
 	scroll    int
+
+	desc     urtypes.OutputDescriptor
+	deriver  *address.Deriver
+	method   *ChoiceScreen
+	scanner  *ScanScreen
+	keyboard *AddressKeyboardScreen
+	search   *addressSearch
+	result   *ErrorScreen
 }
 
 type linePos struct {
@@ -215,16 +297,22 @@ func (r *richText) Add(ops op.Ctx, style text.Style, width int, col color.NRGBA,
 }
 
 func NewAddressesScreen(desc urtypes.OutputDescriptor) *AddressesScreen {
-	s := new(AddressesScreen)
+	s := &AddressesScreen{desc: desc}
+	d, err := address.NewDeriver(desc)
+	if err != nil {
+		// Very unlikely.
+		return s
+	}
+	s.deriver = d
 	for i := 0; i < 20; i++ {
-		addr, err := address.Receive(desc, uint32(i))
+		addr, err := d.Address(uint32(i), false)
 		if err != nil {
 			// Very unlikely.
 			continue
 		}
 		const addrLen = 12
 		s.addresses[0] = append(s.addresses[0], shortenAddress(addrLen, addr))
-		change, err := address.Change(desc, uint32(i))
+		change, err := d.Address(uint32(i), true)
 		if err != nil {
 			continue
 		}
@@ -245,8 +333,85 @@ func (s *AddressesScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 	const linesPerScroll = linesPerPage - 3
 
 	const maxPage = len(s.addresses)
+	th := &descriptorTheme
 	for {
-		e, ok := ctx.Next(Button1, Left, Right, Up, Down)
+		switch {
+		case s.scanner != nil:
+			res, status := s.scanner.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return false
+			}
+			s.scanner = nil
+			if status == ResultComplete {
+				addr, _ := res.([]byte)
+				s.result = s.verify(ctx, string(addr))
+			}
+			continue
+		case s.keyboard != nil:
+			addr, status := s.keyboard.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return false
+			}
+			s.keyboard = nil
+			if status == ResultComplete {
+				s.result = s.verify(ctx, addr)
+			}
+			continue
+		case s.method != nil:
+			choice, status := s.method.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return false
+			case ResultCancelled:
+				s.method = nil
+				continue
+			}
+			s.method = nil
+			switch choice {
+			case 0:
+				s.scanAddress(ctx)
+			case 1:
+				s.keyboard = &AddressKeyboardScreen{
+					Prefix:  bech32Prefix(s.desc),
+					deriver: s.deriver,
+				}
+			}
+			continue
+		case s.search != nil:
+			if res, done := s.search.Poll(); done {
+				s.result = s.searchResult(ctx, res)
+				s.search = nil
+				continue
+			}
+			for {
+				e, ok := ctx.Next(Button1)
+				if !ok {
+					break
+				}
+				if e.Click {
+					s.search.Stop()
+					s.search = nil
+					break
+				}
+			}
+			if s.search != nil {
+				s.layoutSearch(ctx, ops, th, dims)
+				return false
+			}
+			continue
+		case s.result != nil:
+			if s.result.Update(ctx) {
+				s.result = nil
+				continue
+			}
+		}
+		e, ok := ctx.Next(Button1, Button3, Left, Right, Up, Down)
 		if !ok {
 			break
 		}
@@ -255,6 +420,20 @@ func (s *AddressesScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 			if e.Click {
 				return true
 			}
+		case Button3:
+			if !e.Click || s.deriver == nil {
+				break
+			}
+			if bech32Prefix(s.desc) == "" {
+				// Base58 addresses can't be entered.
+				s.scanAddress(ctx)
+				break
+			}
+			s.method = &ChoiceScreen{
+				Title:   ctx.Text(locale.VerifyAddress),
+				Lead:    ctx.Text(locale.InputMethod),
+				Choices: []string{ctx.Text(locale.ChoiceScan), ctx.Text(locale.ChoiceKeyboard)},
+			}
 		case Left:
 			if e.Pressed {
 				s.page = (s.page - 1 + maxPage) % maxPage
@@ -275,14 +454,13 @@ func (s *AddressesScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 			}
 		}
 	}
-	th := &descriptorTheme
 	op.ColorOp(ops, th.Background)
 
 	// Title.
 	r := layout.Rectangle{Max: dims}
-	title := "Receive"
+	title := ctx.Text(locale.Receive)
 	if s.page == 1 {
-		title = "Change"
+		title = ctx.Text(locale.Change)
 	}
 	layoutTitle(ctx, ops, dims.X, th.Text, title)
 
@@ -324,15 +502,33 @@ func (s *AddressesScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) boo
 	}
 	fadeClip(ops, ops.End(), image.Rectangle(body))
 
-	layoutNavigation(ctx, ops, th, dims,
-		NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
-	)
+	if s.result != nil {
+		s.result.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+		return false
+	}
+	btns := []NavButton{{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack}}
+	if s.deriver != nil {
+		btns = append(btns, NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconCheckmark})
+	}
+	layoutNavigation(ctx, ops, th, dims, btns...)
 	return false
 }
 
+// scanAddress starts the scan of an address to verify.
+func (s *AddressesScreen) scanAddress(ctx *Context) {
+	s.scanner = &ScanScreen{
+		Title: ctx.Text(locale.VerifyAddress),
+		Lead:  ctx.Text(locale.BitcoinAddress),
+		Raw:   true,
+	}
+}
+
 type DescriptorScreen struct {
 	Descriptor urtypes.OutputDescriptor
 	Mnemonic   bip39.Mnemonic
+	details    *ChoiceScreen
+	keys       *KeysScreen
 	addresses  *AddressesScreen
 	confirm    *ConfirmWarningScreen
 	warning    *ErrorScreen
@@ -388,6 +584,36 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 			}
 			s.addresses = nil
 			continue
+		case s.keys != nil:
+			done := s.keys.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return 0, ResultNone
+			}
+			s.keys = nil
+			continue
+		case s.details != nil:
+			choice, status := s.details.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return 0, ResultNone
+			case ResultComplete:
+				switch choice {
+				case 0:
+					keyIdx, ok := descriptorKeyIdx(s.Descriptor, s.Mnemonic, "")
+					if !ok {
+						keyIdx = -1
+					}
+					s.keys = NewKeysScreen(s.Descriptor, keyIdx)
+				case 1:
+					s.addresses = NewAddressesScreen(s.Descriptor)
+				}
+			}
+			s.details = nil
+			continue
 		case s.confirm != nil:
 			result := s.confirm.Update(ctx)
 			switch result {
@@ -419,13 +645,17 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 			if !e.Click {
 				break
 			}
-			s.addresses = NewAddressesScreen(s.Descriptor)
+			s.details = &ChoiceScreen{
+				Title:   ctx.Text(locale.WalletDetails),
+				Lead:    ctx.Text(locale.DetailsLead),
+				Choices: []string{ctx.Text(locale.ChoiceKeys), ctx.Text(locale.ChoiceAddresses)},
+			}
 		case Button3:
 			if !e.Click {
 				break
 			}
 			if err := validateDescriptor(s.Descriptor); err != nil {
-				s.warning = NewErrorScreen(err)
+				s.warning = NewErrorScreen(ctx, err)
 				continue
 			}
 			keyIdx, ok := descriptorKeyIdx(s.Descriptor, s.Mnemonic, "")
@@ -436,14 +666,14 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 				// to.
 				if len(s.Descriptor.Keys) == 1 {
 					s.confirm = &ConfirmWarningScreen{
-						Title: "Unknown Wallet",
-						Body:  "The wallet does not match the seed.\n\nIf it is passphrase protected, long press to confirm.",
+						Title: ctx.Text(locale.UnknownWallet),
+						Body:  ctx.Text(locale.UnknownWalletConfirm),
 						Icon:  assets.IconCheckmark,
 					}
 				} else {
 					s.warning = &ErrorScreen{
-						Title: "Unknown Wallet",
-						Body:  "The wallet does not match the seed or is passphrase protected.",
+						Title: ctx.Text(locale.UnknownWallet),
+						Body:  ctx.Text(locale.UnknownWalletMsg),
 					}
 				}
 				continue
@@ -457,7 +687,7 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 
 	// Title.
 	r := layout.Rectangle{Max: dims}
-	layoutTitle(ctx, ops, dims.X, th.Text, "Confirm Wallet")
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.ConfirmWallet))
 
 	btnw := assets.NavBtnPrimary.Bounds().Dx()
 	body := r.Shrink(leadingSize, btnw, 0, btnw)
@@ -471,24 +701,26 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 	bodyst := ctx.Styles.body
 	subst := ctx.Styles.subtitle
 	if desc.Title != "" {
-		bodytxt.Add(ops, subst, body.Dx(), th.Text, "Title")
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.WalletTitle))
 		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, desc.Title)
 		bodytxt.Y += infoSpacing
 	}
-	bodytxt.Add(ops, subst, body.Dx(), th.Text, "Type")
+	bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.WalletType))
 	var typetxt string
 	switch desc.Type {
 	case urtypes.Singlesig:
-		typetxt = "Singlesig"
+		typetxt = ctx.Text(locale.Singlesig)
 	default:
-		typetxt = fmt.Sprintf("%d-of-%d multisig", desc.Threshold, len(desc.Keys))
+		typetxt = ctx.Text(locale.Multisig,
+			"Threshold", strconv.Itoa(desc.Threshold),
+			"Keys", strconv.Itoa(len(desc.Keys)))
 	}
 	if len(desc.Keys) > 0 && desc.Keys[0].Network != &chaincfg.MainNetParams {
-		typetxt += " (testnet)"
+		typetxt = ctx.Text(locale.Testnet, "Type", typetxt)
 	}
 	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, typetxt)
 	bodytxt.Y += infoSpacing
-	bodytxt.Add(ops, subst, body.Dx(), th.Text, "Script")
+	bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.WalletScript))
 	bodytxt.Add(ops, bodyst, body.Dx(), th.Text, desc.Script.String())
 
 	ops.Begin()
@@ -515,12 +747,27 @@ func (s *DescriptorScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (i
 }
 
 type ScanScreen struct {
-	Title     string
-	Lead      string
+	Title string
+	Lead  string
+	// Decoder, if set, accumulates the parts of a multi-part UR
+	// across scans. The screen completes with every part that is
+	// successfully added, and leaves the result to the caller.
+	Decoder *ur.Decoder
+	// Raw, if set, completes the screen with the content of
+	// the first QR code scanned, without decoding it.
+	Raw bool
+	// Plates, if not zero, replaces the fragment status with the
+	// status of the plates of a multi-plate backup, where Scanned
+	// are the indices of the plates scanned.
+	Plates    int
+	Scanned   []int
 	decoder   ur.Decoder
 	nsdecoder nonstandard.Decoder
 	feed      *image.Gray
 	err       error
+	// decodeErr is the most recent error from decoding
+	// an animated QR code, if any.
+	decodeErr error
 }
 
 func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Result) {
@@ -537,7 +784,8 @@ func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Re
 		case Button1:
 			return nil, ResultCancelled
 		case Button2:
-			ctx.RotateCamera = !ctx.RotateCamera
+			ctx.Settings.RotateCamera = !ctx.Settings.RotateCamera
+			ctx.StoreSettings()
 		}
 	}
 
@@ -555,7 +803,7 @@ func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Re
 			ycbcr := f.Image().(*image.YCbCr)
 			gray := &image.Gray{Pix: ycbcr.Y, Stride: ycbcr.YStride, Rect: ycbcr.Bounds()}
 
-			scaleRot(s.feed, gray, ctx.RotateCamera)
+			scaleRot(s.feed, gray, ctx.Settings.RotateCamera)
 			// Re-create image (but not backing store) to ensure redraw.
 			copy := *s.feed
 			s.feed = &copy
@@ -590,9 +838,17 @@ func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Re
 	title.Max.Y -= 4
 	background(ops, ops.End(), title, image.Point{})
 
-	// Camera error, if any.
-	if err := s.err; err != nil {
-		sz := widget.LabelW(ops.Begin(), ctx.Styles.body, dims.X-2*16, th.Text, err.Error())
+	// Camera or decoding error, if any.
+	var errMsg string
+	switch {
+	case s.err != nil:
+		errMsg = s.err.Error()
+	case s.decodeErr != nil:
+		scr := NewErrorScreen(ctx, s.decodeErr)
+		errMsg = scr.Body
+	}
+	if errMsg != "" {
+		sz := widget.LabelW(ops.Begin(), ctx.Styles.body, dims.X-2*16, th.Text, errMsg)
 		op.Position(ops, ops.End(), r.Center(sz))
 	}
 
@@ -604,11 +860,28 @@ func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Re
 	background(ops, ops.End(), image.Rectangle{Min: pos, Max: pos.Add(sz)}, pos)
 
 	// Progress
-	progress := int(100 * s.decoder.Progress())
+	dec := s.urDecoder()
+	progress := int(100 * dec.Progress())
 	if progress == 0 {
 		progress = int(100 * s.nsdecoder.Progress())
 	}
 	if progress > 0 {
+		// Fragment or plate status, if they are few enough to
+		// be distinguishable.
+		n, done := dec.SeqLen(), dec.Recovered()
+		if s.Plates > 0 {
+			n, done = s.Plates, s.Scanned
+		}
+		if n > 1 {
+			sz := layoutSegments(ops.Begin(), th, width, n, done)
+			call := ops.End()
+			if sz != (image.Point{}) {
+				var frags layout.Rectangle
+				top, frags = top.CutBottom(sz.Y + 2*4)
+				pos := frags.Center(sz)
+				background(ops, call, image.Rectangle{Min: pos, Max: pos.Add(sz)}.Inset(-4), pos)
+			}
+		}
 		sz = widget.LabelW(ops.Begin(), ctx.Styles.lead, width, th.Text, fmt.Sprintf("%d%%", progress))
 		_, percent := top.CutBottom(sz.Y)
 		pos := percent.Center(sz)
@@ -627,6 +900,38 @@ func (s *ScanScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (any, Re
 	return nil, ResultNone
 }
 
+// layoutSegments draws a bar of n segments, one for each fragment or plate,
+// where the segments in done are filled and the missing segments are dimmed.
+// It returns the zero size if the segments don't fit the width.
+func layoutSegments(ops op.Ctx, th *Colors, width, n int, done []int) image.Point {
+	const (
+		height = 8
+		gap    = 2
+		minSeg = 3
+	)
+	seg := (width + gap) / n
+	if seg-gap < minSeg {
+		return image.Point{}
+	}
+	seg -= gap
+	filled := make([]bool, n)
+	for _, idx := range done {
+		if idx >= 0 && idx < n {
+			filled[idx] = true
+		}
+	}
+	for i, d := range filled {
+		x := i * (seg + gap)
+		op.ClipOp(image.Rect(x, 0, x+seg, height)).Add(ops)
+		col := th.Text
+		if !d {
+			col.A = theme.inactiveMask
+		}
+		op.ColorOp(ops, col)
+	}
+	return image.Pt(n*(seg+gap)-gap, height)
+}
+
 // scaleRot is a specialized function for fast scaling and rotation of
 // the camera frames for display.
 func scaleRot(dst, src *image.Gray, rot180 bool) {
@@ -666,17 +971,60 @@ func (s *ScanScreen) parseNonStandard(qr []byte) (any, Result) {
 	return enc, ResultComplete
 }
 
+// malformedQR reports whether err is from a QR code that
+// exceeds the decoder limits or is invalid.
+func malformedQR(err error) bool {
+	var lerr *fountain.LimitError
+	return errors.As(err, &lerr) || errors.Is(err, fountain.ErrInvalidPart)
+}
+
+func (s *ScanScreen) urDecoder() *ur.Decoder {
+	if s.Decoder != nil {
+		return s.Decoder
+	}
+	return &s.decoder
+}
+
+// addPart adds a part to the external decoder, and completes
+// with the part if it was accepted.
+func (s *ScanScreen) addPart(uqr string) (any, Result) {
+	if !strings.HasPrefix(uqr, "UR:") {
+		return nil, ResultNone
+	}
+	if err := s.Decoder.Add(uqr); err != nil {
+		s.decodeErr = err
+		return nil, ResultNone
+	}
+	s.decodeErr = nil
+	return uqr, ResultComplete
+}
+
 func (s *ScanScreen) parseQR(qr []byte) (any, Result) {
+	if s.Raw {
+		return qr, ResultComplete
+	}
 	uqr := strings.ToUpper(string(qr))
+	if s.Decoder != nil {
+		return s.addPart(uqr)
+	}
 	if !strings.HasPrefix(uqr, "UR:") {
 		s.decoder = ur.Decoder{}
 		return s.parseNonStandard(qr)
 	}
 	s.nsdecoder = nonstandard.Decoder{}
-	if err := s.decoder.Add(uqr); err != nil {
+	err := s.decoder.Add(uqr)
+	if err != nil && !malformedQR(err) {
 		// Incompatible fragment. Reset decoder and try again.
 		s.decoder = ur.Decoder{}
-		s.decoder.Add(uqr)
+		err = s.decoder.Add(uqr)
+	}
+	if malformedQR(err) {
+		// Drop the part, but keep the parts decoded so far.
+		s.decodeErr = err
+		return nil, ResultNone
+	}
+	if err == nil {
+		s.decodeErr = nil
 	}
 	typ, enc, err := s.decoder.Result()
 	if err != nil {
@@ -694,6 +1042,112 @@ func (s *ScanScreen) parseQR(qr []byte) (any, Result) {
 	return v, ResultComplete
 }
 
+// RecoverScreen recovers a wallet output descriptor by scanning
+// the descriptor QR codes of multiple plates.
+type RecoverScreen struct {
+	decoder ur.Decoder
+	// parts are the distinct QR codes scanned.
+	parts []string
+	// plates and scanned are the result of backup.ScannedPlates
+	// for parts.
+	plates  int
+	scanned []int
+	scanner ScanScreen
+}
+
+type recovery struct {
+	Descriptor urtypes.OutputDescriptor
+	// Shares are the indices of the shares scanned.
+	Shares []int
+}
+
+var errRecoveryFailed = errors.New("The scanned QR codes don't combine into a supported wallet.")
+
+func (s *RecoverScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) (recovery, Result) {
+	s.scanner.Title = ctx.Text(locale.Recover)
+	s.scanner.Decoder = &s.decoder
+	for {
+		s.scanner.Plates, s.scanner.Scanned = s.plates, s.scanned
+		switch {
+		case len(s.scanned) > 0:
+			s.scanner.Lead = sharesLead(ctx, s.scanned, s.plates)
+		case len(s.parts) > 0:
+			s.scanner.Lead = ctx.Text(locale.QRsScanned, "N", strconv.Itoa(len(s.parts)))
+		default:
+			s.scanner.Lead = ctx.Text(locale.ScanPlateQRs)
+		}
+		res, status := s.scanner.Layout(ctx, ops.Begin(), dims)
+		dialog := ops.End()
+		switch status {
+		case ResultNone:
+			dialog.Add(ops)
+			return recovery{}, ResultNone
+		case ResultCancelled:
+			return recovery{}, ResultCancelled
+		}
+		part := res.(string)
+		dup := false
+		for _, p := range s.parts {
+			if p == part {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			s.parts = append(s.parts, part)
+			s.plates, s.scanned = backup.ScannedPlates(s.parts)
+		}
+		desc, err := s.result()
+		if err != nil {
+			s.decoder = ur.Decoder{}
+			s.parts = nil
+			s.plates, s.scanned = 0, nil
+			s.scanner.decodeErr = err
+			continue
+		}
+		if desc == nil {
+			continue
+		}
+		return recovery{
+			Descriptor: *desc,
+			Shares:     backup.Shares(*desc, s.parts),
+		}, ResultComplete
+	}
+}
+
+// result returns the recovered descriptor, or nil if more
+// QR codes are needed.
+func (s *RecoverScreen) result() (*urtypes.OutputDescriptor, error) {
+	typ, enc, err := s.decoder.Result()
+	if err != nil {
+		return nil, errRecoveryFailed
+	}
+	if enc == nil {
+		return nil, nil
+	}
+	v, err := urtypes.Parse(typ, enc)
+	if err != nil {
+		return nil, errRecoveryFailed
+	}
+	desc, ok := v.(urtypes.OutputDescriptor)
+	if !ok || !address.Supported(desc) {
+		return nil, errRecoveryFailed
+	}
+	return &desc, nil
+}
+
+// sharesLead describes the scanned shares of an n-share backup.
+func sharesLead(ctx *Context, shares []int, n int) string {
+	if len(shares) == 0 {
+		return ctx.Text(locale.WalletRecovered)
+	}
+	var idx []string
+	for _, sh := range shares {
+		idx = append(idx, strconv.Itoa(sh+1))
+	}
+	return ctx.Text(locale.SharesOf, "Shares", strings.Join(idx, ", "), "N", strconv.Itoa(n))
+}
+
 type ErrorScreen struct {
 	Title string
 	Body  string
@@ -868,6 +1322,9 @@ func (p ProgressImage) RGBA64At(x, y int) color.RGBA64 {
 type EngraveScreen struct {
 	instructions []Instruction
 	plate        Plate
+	// durations are the estimated durations of the
+	// plate sides.
+	durations []time.Duration
 
 	cancel *ConfirmWarningScreen
 	step   int
@@ -877,6 +1334,22 @@ type EngraveScreen struct {
 	}
 	engrave engraveState
 	confirm ConfirmDelay
+	// resume, if not nil, describes an interrupted engraving
+	// that may be resumed.
+	resume       *resumeState
+	resumeChoice *ChoiceScreen
+	preview      *PreviewScreen
+	verify       *VerifyScreen
+	// verified is set if the engraved plate passed
+	// verification.
+	verified bool
+}
+
+type resumeState struct {
+	side int
+	// completed is the number of instructions completed
+	// before the interruption.
+	completed int
 }
 
 type errDuplicateKey struct {
@@ -892,22 +1365,42 @@ func (e *errDuplicateKey) Is(target error) bool {
 	return ok
 }
 
-func NewErrorScreen(err error) *ErrorScreen {
+func NewErrorScreen(ctx *Context, err error) *ErrorScreen {
 	var errDup *errDuplicateKey
 	switch {
 	case errors.As(err, &errDup):
 		return &ErrorScreen{
-			Title: "Duplicated Share",
-			Body:  fmt.Sprintf("The share %.8x is listed more than once in the wallet.", errDup.Fingerprint),
+			Title: ctx.Text(locale.DuplicatedShare),
+			Body:  ctx.Text(locale.DuplicatedShareMsg, "Fingerprint", fmt.Sprintf("%.8x", errDup.Fingerprint)),
 		}
 	case errors.Is(err, backup.ErrDescriptorTooLarge):
 		return &ErrorScreen{
-			Title: "Too Large",
-			Body:  "The descriptor cannot fit any plate size.",
+			Title: ctx.Text(locale.TooLarge),
+			Body:  ctx.Text(locale.DescriptorTooLarge),
+		}
+	case errors.As(err, new(*fountain.LimitError)):
+		return &ErrorScreen{
+			Title: ctx.Text(locale.TooLarge),
+			Body:  ctx.Text(locale.QRTooLarge),
+		}
+	case errors.Is(err, fountain.ErrInvalidPart):
+		return &ErrorScreen{
+			Title: ctx.Text(locale.InvalidQR),
+			Body:  ctx.Text(locale.InvalidQRMsg),
+		}
+	case errors.Is(err, fountain.ErrIncompatiblePart):
+		return &ErrorScreen{
+			Title: ctx.Text(locale.InvalidQR),
+			Body:  ctx.Text(locale.IncompatibleQR),
+		}
+	case errors.Is(err, errRecoveryFailed):
+		return &ErrorScreen{
+			Title: ctx.Text(locale.Error),
+			Body:  ctx.Text(locale.RecoveryFailed),
 		}
 	default:
 		return &ErrorScreen{
-			Title: "Error",
+			Title: ctx.Text(locale.Error),
 			Body:  err.Error(),
 		}
 	}
@@ -948,16 +1441,32 @@ func validateDescriptor(desc urtypes.OutputDescriptor) error {
 type Plate struct {
 	Size              backup.PlateSize
 	MasterFingerprint uint32
-	Sides             []engrave.Command
+	// KeyIdx is the index of the share on the plate, out of
+	// Keys.
+	KeyIdx int
+	Keys   int
+	// Title and DescriptorChecksum identify the wallet of
+	// the plate, if any.
+	Title              string
+	DescriptorChecksum uint32
+	Sides              []engrave.Command
+	// QRs are the contents of the QR codes engraved on the
+	// plate, for verifying the engraving.
+	QRs [][]byte
 }
 
-func engraveSeed(m bip39.Mnemonic) (Plate, error) {
+// engraveSeed lays out m on the smallest plate that fits, but
+// no smaller than minSize.
+func engraveSeed(m bip39.Mnemonic, minSize backup.PlateSize) (Plate, error) {
 	mfp, err := masterFingerprintFor(m, &chaincfg.MainNetParams)
 	if err != nil {
 		return Plate{}, err
 	}
 	var lastErr error
 	for _, sz := range []backup.PlateSize{backup.SmallPlate, backup.SquarePlate, backup.LargePlate} {
+		if sz < minSize {
+			continue
+		}
 		seedDesc := backup.Seed{
 			KeyIdx:            0,
 			Mnemonic:          m,
@@ -975,6 +1484,7 @@ func engraveSeed(m bip39.Mnemonic) (Plate, error) {
 			Sides:             []engrave.Command{seedSide},
 			Size:              sz,
 			MasterFingerprint: mfp,
+			Keys:              1,
 		}, nil
 	}
 	return Plate{}, lastErr
@@ -992,13 +1502,18 @@ func masterFingerprintFor(m bip39.Mnemonic, network *chaincfg.Params) (uint32, e
 	return mfp, nil
 }
 
-func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic) (Plate, error) {
+// engravePlate lays out the share of keyIdx on the smallest plate
+// that fits, but no smaller than minSize.
+func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic, minSize backup.PlateSize) (Plate, error) {
 	mfp, err := masterFingerprintFor(m, desc.Keys[keyIdx].Network)
 	if err != nil {
 		return Plate{}, err
 	}
 	var lastErr error
 	for _, sz := range []backup.PlateSize{backup.SmallPlate, backup.SquarePlate, backup.LargePlate} {
+		if sz < minSize {
+			continue
+		}
 		descPlate := backup.Descriptor{
 			Descriptor: desc,
 			KeyIdx:     keyIdx,
@@ -1024,10 +1539,19 @@ func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic) (
 			lastErr = err
 			continue
 		}
+		var qrs [][]byte
+		for _, u := range backup.URs(desc, keyIdx) {
+			qrs = append(qrs, []byte(u))
+		}
 		return Plate{
-			Size:              sz,
-			MasterFingerprint: mfp,
-			Sides:             []engrave.Command{descSide, seedSide},
+			Size:               sz,
+			MasterFingerprint:  mfp,
+			KeyIdx:             keyIdx,
+			Keys:               len(desc.Keys),
+			Title:              desc.Title,
+			DescriptorChecksum: fountain.Checksum(desc.Encode()),
+			Sides:              []engrave.Command{descSide, seedSide},
+			QRs:                qrs,
 		}, nil
 	}
 	return Plate{}, lastErr
@@ -1035,7 +1559,7 @@ func engravePlate(desc urtypes.OutputDescriptor, keyIdx int, m bip39.Mnemonic) (
 
 func NewEngraveScreen(ctx *Context, plate Plate) *EngraveScreen {
 	var ins []Instruction
-	if !ctx.Calibrated {
+	if !ctx.Settings.Calibrated {
 		ins = append(ins, EngraveFirstSideA...)
 	} else {
 		ins = append(ins, EngraveSideA...)
@@ -1048,11 +1572,38 @@ func NewEngraveScreen(ctx *Context, plate Plate) *EngraveScreen {
 		plate:        plate,
 		instructions: ins,
 	}
+	s.dryRun.enabled = ctx.Settings.DryRun
+	var total time.Duration
+	for _, side := range plate.Sides {
+		e := &mjolnir.Estimator{
+			MoveSpeed:  ctx.Settings.MoveSpeed,
+			PrintSpeed: ctx.Settings.PrintSpeed,
+		}
+		side.Engrave(e)
+		d := e.Duration()
+		s.durations = append(s.durations, d)
+		total += d
+	}
 	for i, ins := range s.instructions {
-		repl := strings.NewReplacer(
-			"{{.Name}}", plateName(plate.Size),
-		)
-		s.instructions[i].resolvedBody = repl.Replace(ins.Body)
+		if ins.Body != "" {
+			s.instructions[i].resolvedBody = ctx.Text(ins.Body, "Name", plateName(plate.Size))
+		}
+		if ins.Type == ConnectInstruction && i+1 < len(s.instructions) {
+			side := s.instructions[i+1].Side
+			eta := ctx.Text(locale.About, "Duration", formatDuration(ctx, s.durations[side]))
+			if len(s.durations) > 1 {
+				eta = ctx.Text(locale.AboutTotal, "Duration", formatDuration(ctx, s.durations[side]), "Total", formatDuration(ctx, total))
+			}
+			s.instructions[i].resolvedBody += "\n\n" + eta
+		}
+		if ins.Type == VerifyInstruction {
+			if len(plate.QRs) == 0 {
+				// Nothing to verify.
+				s.instructions[i].Type = PrepareInstruction
+			} else {
+				s.instructions[i].resolvedBody += "\n\n" + ctx.Text(locale.VerifyHint)
+			}
+		}
 		// As a special case, the Sh01 image is a placeholder for the plate-specific image.
 		if ins.Image == assets.Sh01 {
 			s.instructions[i].Image = plateImage(plate.Size)
@@ -1062,12 +1613,40 @@ func NewEngraveScreen(ctx *Context, plate Plate) *EngraveScreen {
 }
 
 type engraveState struct {
-	dev          io.ReadWriteCloser
+	dev          driver.Device
 	cancel       chan struct{}
 	progress     chan float32
 	errs         chan error
 	lastProgress float32
-	warning      *ErrorScreen
+	// offset is the progress of a resumed engraving
+	// before it was interrupted.
+	offset float32
+	// audit describes the running engraving for the
+	// audit log.
+	audit   auditlog.Entry
+	warning *ErrorScreen
+}
+
+// instructionCounter is a Program that counts its instructions.
+type instructionCounter int
+
+func (c *instructionCounter) Move(p image.Point) {
+	*c++
+}
+
+func (c *instructionCounter) Line(p image.Point) {
+	*c++
+}
+
+// nextSide returns the side engraved by the current or next
+// engrave instruction.
+func (s *EngraveScreen) nextSide() int {
+	for _, ins := range s.instructions[s.step:] {
+		if ins.Type == EngraveInstruction {
+			return ins.Side
+		}
+	}
+	return len(s.plate.Sides) - 1
 }
 
 func (s *EngraveScreen) close() {
@@ -1088,8 +1667,8 @@ func (s *EngraveScreen) moveStep(ctx *Context) bool {
 		if err != nil {
 			log.Printf("gui: failed to connect to engraver: %v", err)
 			s.engrave.warning = &ErrorScreen{
-				Title: "Connection Error",
-				Body:  fmt.Sprintf("Ensure the engraver is turned on and verify that it is connected to the middle port of this device.\n\nError details: %v", err),
+				Title: ctx.Text(locale.ConnectionError),
+				Body:  ctx.Text(locale.ConnectionErrorMsg, "Err", err.Error()),
 			}
 			return false
 		}
@@ -1102,11 +1681,35 @@ func (s *EngraveScreen) moveStep(ctx *Context) bool {
 	}
 	ins = s.instructions[s.step]
 	if ins.Type == EngraveInstruction {
-		prog := &mjolnir.Program{
-			DryRun: s.dryRun.enabled,
+		job := driver.Job{
+			Command:    s.plate.Sides[ins.Side],
+			DryRun:     s.dryRun.enabled,
+			MoveSpeed:  ctx.Settings.MoveSpeed,
+			PrintSpeed: ctx.Settings.PrintSpeed,
+		}
+		if r := s.resume; r != nil && r.side == ins.Side {
+			s.resume = nil
+			job.Resume = r.completed
+			total := new(instructionCounter)
+			job.Command.Engrave(total)
+			s.engrave.offset = float32(r.completed) / float32(*total)
+			s.engrave.lastProgress = s.engrave.offset
+		}
+		s.engrave.audit = auditlog.Entry{
+			Time:              ctx.Platform.Now().UTC(),
+			Version:           ctx.Version,
+			Plate:             plateName(s.plate.Size),
+			Side:              ins.Side,
+			Share:             s.plate.KeyIdx,
+			Shares:            s.plate.Keys,
+			MasterFingerprint: fmt.Sprintf("%.8x", s.plate.MasterFingerprint),
+			Title:             s.plate.Title,
+			DryRun:            job.DryRun,
+			Estimated:         s.durations[ins.Side],
+		}
+		if s.plate.DescriptorChecksum != 0 {
+			s.engrave.audit.DescriptorChecksum = fmt.Sprintf("%.8x", s.plate.DescriptorChecksum)
 		}
-		s.plate.Sides[ins.Side].Engrave(prog)
-		prog.Prepare()
 		cancel := make(chan struct{})
 		errs := make(chan error, 1)
 		progress := make(chan float32, 1)
@@ -1134,13 +1737,29 @@ func (s *EngraveScreen) moveStep(ctx *Context) bool {
 		}()
 		go func() {
 			defer dev.Close()
-			errs <- mjolnir.Engrave(dev, prog, progress, cancel)
+			err := dev.Run(job, progress, cancel)
+			if err == nil {
+				// Sanity check that the needle returned
+				// to rest.
+				err = driver.CheckParked(dev)
+			}
+			errs <- err
 		}()
-		go s.plate.Sides[ins.Side].Engrave(prog)
 	}
 	return false
 }
 
+// audit records the outcome of the running engraving in the audit
+// log.
+func (s *EngraveScreen) audit(ctx *Context, err error) {
+	e := s.engrave.audit
+	e.Actual = ctx.Platform.Now().Sub(e.Time)
+	if err != nil {
+		e.Error = err.Error()
+	}
+	ctx.Audit(e)
+}
+
 type Result int
 
 const (
@@ -1154,19 +1773,53 @@ loop:
 	for {
 		select {
 		case p := <-s.engrave.progress:
-			s.engrave.lastProgress = p
+			off := s.engrave.offset
+			s.engrave.lastProgress = off + p*(1-off)
 		case err := <-s.engrave.errs:
+			// A needle away from its rest position after a
+			// completed engraving is worth a warning, but the
+			// plate is done.
+			var perr *driver.PositionError
+			parked := !errors.As(err, &perr)
+			if !parked {
+				log.Printf("gui: engraving completed: %v", err)
+				err = nil
+			}
+			s.audit(ctx, err)
 			s.engrave = engraveState{}
+			if !parked {
+				s.engrave.warning = &ErrorScreen{
+					Title: ctx.Text(locale.NeedleNotParked),
+					Body:  ctx.Text(locale.NeedleNotParkedMsg, "Err", perr.Error()),
+				}
+			}
 			if err != nil {
-				log.Printf("gui: connection lost to engraver: %v", err)
+				log.Printf("gui: engraving failed: %v", err)
+				var ierr *driver.InterruptedError
+				if errors.As(err, &ierr) && ierr.Completed > 0 {
+					s.resume = &resumeState{
+						side:      s.instructions[s.step].Side,
+						completed: ierr.Completed,
+					}
+				}
 				s.step--
+				if errors.Is(err, driver.ErrCancelled) {
+					s.engrave.warning = &ErrorScreen{
+						Title: ctx.Text(locale.EngravingCancelled),
+						Body:  ctx.Text(locale.EngravingCancelMsg),
+					}
+					break
+				}
 				s.engrave.warning = &ErrorScreen{
-					Title: "Connection Error",
-					Body:  fmt.Sprintf("Turn off the engraver and disconnect this device from it. Wait 10 seconds, then turn on the engraver and reconnect.\n\nError details: %v", err),
+					Title: ctx.Text(locale.ConnectionError),
+					Body:  ctx.Text(locale.ConnectionLostMsg, "Err", err.Error()),
 				}
 				break
 			}
-			ctx.Calibrated = true
+			if !ctx.Settings.Calibrated {
+				ctx.Settings.Calibrated = true
+				ctx.StoreSettings()
+			}
 			s.step++
 			if s.step == len(s.instructions) {
 				return ResultComplete
@@ -1203,6 +1856,9 @@ loop:
 			result := s.cancel.Update(ctx)
 			switch result {
 			case ConfirmYes:
+				if s.engrave.errs != nil {
+					s.audit(ctx, driver.ErrCancelled)
+				}
 				s.close()
 				return ResultCancelled
 			case ConfirmNo:
@@ -1213,8 +1869,54 @@ loop:
 			dismissed := s.engrave.warning.Update(ctx)
 			if dismissed {
 				s.engrave.warning = nil
+				if s.resume != nil {
+					s.resumeChoice = &ChoiceScreen{
+						Title:   ctx.Text(locale.ResumeEngraving),
+						Lead:    ctx.Text(locale.ResumeEngravingLead),
+						Choices: []string{ctx.Text(locale.ChoiceResume), ctx.Text(locale.ChoiceRestart)},
+					}
+				}
 				continue
 			}
+		case s.resumeChoice != nil:
+			choice, status := s.resumeChoice.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return ResultNone
+			case ResultComplete:
+				if choice == 1 {
+					s.resume = nil
+				}
+			}
+			s.resumeChoice = nil
+			continue
+		case s.preview != nil:
+			status := s.preview.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return ResultNone
+			}
+			s.preview = nil
+			continue
+		case s.verify != nil:
+			status := s.verify.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return ResultNone
+			case ResultComplete:
+				s.verify = nil
+				s.verified = true
+				if s.moveStep(ctx) {
+					return ResultComplete
+				}
+			}
+			s.verify = nil
+			continue
 		}
 		e, ok := ctx.Next(Button1, Button2, Button3)
 		if !ok {
@@ -1229,8 +1931,8 @@ loop:
 				s.step--
 			} else {
 				s.cancel = &ConfirmWarningScreen{
-					Title: "Cancel?",
-					Body:  "This will cancel the engraving process.\n\nHold button to confirm.",
+					Title: ctx.Text(locale.CancelEngraving),
+					Body:  ctx.Text(locale.CancelEngravingMsg),
 					Icon:  assets.IconDiscard,
 				}
 			}
@@ -1241,6 +1943,13 @@ loop:
 				ctx.WakeupAt(t)
 			} else {
 				s.dryRun.timeout = time.Time{}
+				switch {
+				case !e.Click:
+				case ins.Type == PrepareInstruction:
+					s.preview = NewPreviewScreen(s.plate, s.nextSide())
+				case ins.Type == VerifyInstruction:
+					s.verify = &VerifyScreen{Plate: s.plate}
+				}
 			}
 		case Button3:
 			if ins.Type == ConnectInstruction {
@@ -1261,7 +1970,7 @@ loop:
 	}
 
 	op.ColorOp(ops, th.Background)
-	layoutTitle(ctx, ops, dims.X, th.Text, fmt.Sprintf("Engrave Plate"))
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.EngravePlate))
 
 	r := layout.Rectangle{Max: dims}
 	_, subt := r.CutTop(leadingSize)
@@ -1296,8 +2005,13 @@ loop:
 		bodysz.Y += sz.Y
 	}
 	op.Position(ops, ops.End(), content.Center(bodysz))
-	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*margin, th.Text, ins.Lead)
-	op.Position(ops, ops.End(), lead.Center(leadsz))
+	leadTxt := ins.Lead
+	if ins.Type == EngraveInstruction {
+		left := time.Duration(float32(s.durations[ins.Side]) * (1 - s.engrave.lastProgress))
+		leadTxt = ctx.Text(locale.EngravingLeft, "Left", formatDuration(ctx, left))
+	}
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*margin, th.Text, leadTxt)
+	op.Position(ops, ops.End(), lead.Center(leadsz))
 
 	progressw := dims.X * (s.step + 1) / len(s.instructions)
 	op.ClipOp(image.Rectangle{Max: image.Pt(progressw, 2)}).Add(ops)
@@ -1317,6 +2031,11 @@ loop:
 		layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button1, Style: StyleSecondary, Icon: icnBack})
 		switch ins.Type {
 		case EngraveInstruction:
+		case VerifyInstruction:
+			layoutNavigation(ctx, ops, th, dims,
+				NavButton{Button: Button2, Style: StyleSecondary, Icon: assets.IconCheckmark},
+				NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconRight},
+			)
 		case ConnectInstruction:
 			icn := image.RGBA64Image(assets.IconHammer)
 			if s.confirm.Running() {
@@ -1327,7 +2046,10 @@ loop:
 			}
 			layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button3, Style: StylePrimary, Icon: icn})
 		default:
-			layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconRight})
+			layoutNavigation(ctx, ops, th, dims,
+				NavButton{Button: Button2, Style: StyleSecondary, Icon: assets.IconInfo},
+				NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconRight},
+			)
 		}
 	case s.cancel != nil:
 		s.cancel.Layout(ctx, ops.Begin(), th, dims)
@@ -1341,6 +2063,16 @@ loop:
 	return ResultNone
 }
 
+// formatDuration formats an estimated duration in whole minutes,
+// rounded up.
+func formatDuration(ctx *Context, d time.Duration) string {
+	mins := int((d + time.Minute - 1) / time.Minute)
+	if mins < 60 {
+		return ctx.Text(locale.Minutes, "M", strconv.Itoa(mins))
+	}
+	return ctx.Text(locale.HoursMinutes, "H", strconv.Itoa(mins/60), "M", strconv.Itoa(mins%60))
+}
+
 func plateImage(p backup.PlateSize) image.RGBA64Image {
 	switch p {
 	case backup.SmallPlate:
@@ -1373,10 +2105,13 @@ const (
 	PrepareInstruction InstructionType = iota
 	ConnectInstruction
 	EngraveInstruction
+	// VerifyInstruction offers to verify the engraved plate
+	// by scanning its QR codes.
+	VerifyInstruction
 )
 
 type Instruction struct {
-	Body  string
+	Body  locale.ID
 	Lead  string
 	Type  InstructionType
 	Side  int
@@ -1388,45 +2123,44 @@ type Instruction struct {
 var (
 	EngraveFirstSideA = []Instruction{
 		{
-			Body: "Make sure the fingerprint above represents the intended share.",
+			Body: locale.CheckFingerprint,
 			Lead: "seedhammer.com/tip#1",
 		},
 		{
-			Body: "Turn off the engraver and disconnect it from this device.",
+			Body: locale.DisconnectEngraver,
 			Lead: "seedhammer.com/tip#2",
 		},
 		{
-			Body: "Manually move the hammerhead to the far upper left position.",
+			Body: locale.MoveHammerhead,
 			Lead: "seedhammer.com/tip#3",
 		},
 		{
-			Body:  "Place a {{.Name}} on top\nof a protective plate.",
+			Body:  locale.PlacePlate,
 			Image: assets.Sh01,
 			Lead:  "seedhammer.com/tip#4",
 		},
 		{
-			Body: "Tighten the nuts firmly.",
+			Body: locale.TightenNuts,
 			Lead: "seedhammer.com/tip#4",
 		},
 		{
-			Body: "Loosen the hammerhead finger screw. Adjust needle distance to ~1.5 mm above the plate.",
+			Body: locale.AdjustNeedle,
 			Lead: "seedhammer.com/tip#5",
 		},
 		{
-			Body: "Tighten the hammerhead finger screw and make sure the depth selector is set to \"Strong\".",
+			Body: locale.TightenScrew,
 			Lead: "seedhammer.com/tip#6",
 		},
 		{
-			Body: "Turn on the engraving machine and connect this device via the middle port.",
+			Body: locale.ConnectEngraver,
 			Lead: "seedhammer.com/tip#7",
 		},
 		{
-			Body: "Hold button to start the engraving process. The process is loud, use hearing protection.",
+			Body: locale.StartEngraving,
 			Type: ConnectInstruction,
 			Lead: "seedhammer.com/tip#8",
 		},
 		{
-			Lead: "Engraving plate",
 			Type: EngraveInstruction,
 			Side: 0,
 		},
@@ -1434,25 +2168,24 @@ var (
 
 	EngraveSideA = []Instruction{
 		{
-			Body: "Make sure the fingerprint above represents the intended share.",
+			Body: locale.CheckFingerprint,
 			Lead: "seedhammer.com/tip#1",
 		},
 		{
-			Body:  "Place a {{.Name}} on top\nof a protective plate.",
+			Body:  locale.PlacePlate,
 			Image: assets.Sh01,
 			Lead:  "seedhammer.com/tip#4",
 		},
 		{
-			Body: "Tighten the nuts firmly.",
+			Body: locale.TightenNuts,
 			Lead: "seedhammer.com/tip#4",
 		},
 		{
-			Body: "Hold button to start the engraving process. The process is loud, use hearing protection.",
+			Body: locale.StartEngraving,
 			Type: ConnectInstruction,
 			Lead: "seedhammer.com/tip#8",
 		},
 		{
-			Lead: "Engraving plate",
 			Type: EngraveInstruction,
 			Side: 0,
 		},
@@ -1460,17 +2193,16 @@ var (
 
 	EngraveSideB = []Instruction{
 		{
-			Body: "Unscrew the 4 nuts and flip the top metal plate horizontally.",
+			Body: locale.FlipPlate,
 		},
 		{
-			Body: "Tighten the nuts firmly.",
+			Body: locale.TightenNuts,
 		},
 		{
-			Body: "Hold button to start the engraving process. The process is loud, use hearing protection.",
+			Body: locale.StartEngraving,
 			Type: ConnectInstruction,
 		},
 		{
-			Lead: "Engraving plate",
 			Type: EngraveInstruction,
 			Side: 1,
 		},
@@ -1478,17 +2210,22 @@ var (
 
 	EngraveSuccess = []Instruction{
 		{
-			Body: "Engraving completed successfully.",
+			Body: locale.EngravingComplete,
+			Type: VerifyInstruction,
 		},
 	}
 )
 
-func NewEmptySeedScreen(title string) *SeedScreen {
+func NewEmptySeedScreen(ctx *Context, title string) *SeedScreen {
 	s := &SeedScreen{
 		method: &ChoiceScreen{
-			Title:   title,
-			Lead:    "Choose input method",
-			Choices: []string{"KEYBOARD", "CAMERA"},
+			Title: title,
+			Lead:  ctx.Text(locale.InputMethod),
+			Choices: []string{
+				ctx.Text(locale.ChoiceKeyboard),
+				ctx.Text(locale.ChoiceCamera),
+				ctx.Text(locale.ChoiceLastWord),
+			},
 		},
 	}
 	return s
@@ -1504,6 +2241,10 @@ type SeedScreen struct {
 	scanner  *ScanScreen
 	cancel   *ConfirmWarningScreen
 	warning  *ErrorScreen
+	// lastWord is set when the final word is chosen from
+	// the words that complete the checksum.
+	lastWord   bool
+	candidates *LastWordScreen
 }
 
 func (s *SeedScreen) empty() bool {
@@ -1554,8 +2295,8 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 					res = sqr
 				} else if nonstandard.ElectrumSeed(string(b)) {
 					s.warning = &ErrorScreen{
-						Title: "Invalid Seed",
-						Body:  "Electrum seeds are not supported.",
+						Title: ctx.Text(locale.InvalidSeed),
+						Body:  ctx.Text(locale.ElectrumSeed),
 					}
 					continue
 				}
@@ -1563,8 +2304,8 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 			seed, ok := res.(bip39.Mnemonic)
 			if !ok {
 				s.warning = &ErrorScreen{
-					Title: "Invalid Seed",
-					Body:  "The scanned data does not represent a seed.",
+					Title: ctx.Text(locale.InvalidSeed),
+					Body:  ctx.Text(locale.NotSeed),
 				}
 				continue
 			}
@@ -1584,8 +2325,12 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 			}
 			nwords := []int{12, 24}[choice]
 			s.Mnemonic = emptyMnemonic(nwords)
+			input := s.Mnemonic
+			if s.lastWord {
+				input = input[:nwords-1]
+			}
 			s.input = &WordKeyboardScreen{
-				Mnemonic: s.Mnemonic,
+				Mnemonic: input,
 			}
 			continue
 		case s.method != nil && s.input == nil && s.warning == nil:
@@ -1598,17 +2343,24 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 			case ResultCancelled:
 				return nil, ResultCancelled
 			}
+			s.lastWord = choice == 2
 			switch choice {
 			case 0:
 				s.seedlen = &ChoiceScreen{
-					Title:   "Input Seed",
-					Lead:    "Choose number of words",
-					Choices: []string{"12 WORDS", "24 WORDS"},
+					Title:   ctx.Text(locale.InputSeed),
+					Lead:    ctx.Text(locale.NumWords),
+					Choices: []string{ctx.Text(locale.Choice12Words), ctx.Text(locale.Choice24Words)},
 				}
 			case 1:
 				s.scanner = &ScanScreen{
-					Title: "Scan",
-					Lead:  "SeedQR or Mnemonic",
+					Title: ctx.Text(locale.Scan),
+					Lead:  ctx.Text(locale.SeedQR),
+				}
+			case 2:
+				s.seedlen = &ChoiceScreen{
+					Title:   ctx.Text(locale.LastWord),
+					Lead:    ctx.Text(locale.NumWords),
+					Choices: []string{ctx.Text(locale.Choice12Words), ctx.Text(locale.Choice24Words)},
 				}
 			}
 			continue
@@ -1624,11 +2376,28 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 					s.input = nil
 					continue
 				}
+			case ResultComplete:
+				if s.lastWord && s.Mnemonic[len(s.Mnemonic)-1] == -1 {
+					s.candidates = NewLastWordScreen(s.Mnemonic)
+				}
 			}
 			s.seedlen = nil
 			s.input = nil
 			s.method = nil
 			continue
+		case s.candidates != nil:
+			w, status := s.candidates.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return nil, ResultNone
+			}
+			s.candidates = nil
+			if status == ResultComplete {
+				s.Mnemonic[len(s.Mnemonic)-1] = w
+				s.selected = len(s.Mnemonic) - 1
+			}
+			continue
 		case s.cancel != nil:
 			result := s.cancel.Update(ctx)
 			switch result {
@@ -1658,14 +2427,18 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 				return nil, ResultCancelled
 			}
 			s.cancel = &ConfirmWarningScreen{
-				Title: "Discard Seed?",
-				Body:  "Going back will discard the seed.\n\nHold button to confirm.",
+				Title: ctx.Text(locale.DiscardSeed),
+				Body:  ctx.Text(locale.DiscardSeedMsg),
 				Icon:  assets.IconDiscard,
 			}
 		case Button2, Center:
 			if !e.Click {
 				break
 			}
+			if s.lastWord && s.selected == len(s.Mnemonic)-1 {
+				s.candidates = NewLastWordScreen(s.Mnemonic)
+				continue
+			}
 			s.input = &WordKeyboardScreen{
 				Mnemonic: s.Mnemonic,
 				selected: s.selected,
@@ -1677,16 +2450,16 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 			}
 			if !s.Mnemonic.Valid() {
 				s.warning = &ErrorScreen{
-					Title: "Invalid Seed",
+					Title: ctx.Text(locale.InvalidSeed),
 				}
 				var words []string
 				for _, w := range s.Mnemonic {
 					words = append(words, bip39.LabelFor(w))
 				}
 				if nonstandard.ElectrumSeed(strings.Join(words, " ")) {
-					s.warning.Body = "Electrum seeds are not supported."
+					s.warning.Body = ctx.Text(locale.ElectrumSeed)
 				} else {
-					s.warning.Body = "The seed phrase is invalid.\n\nCheck the words and try again."
+					s.warning.Body = ctx.Text(locale.InvalidMnemonic)
 				}
 				break
 			}
@@ -1703,7 +2476,7 @@ func (s *SeedScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Poi
 	}
 
 	op.ColorOp(ops, th.Background)
-	layoutTitle(ctx, ops, dims.X, th.Text, "Confirm Seed")
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.ConfirmSeed))
 
 	style := ctx.Styles.word
 	_, longestPrefix := style.Layout(math.MaxInt, "24: ")
@@ -1894,7 +2667,7 @@ func (s *WordKeyboardScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims i
 	}
 	completedWord, complete := s.kbd.Complete()
 	op.ColorOp(ops, th.Background)
-	layoutTitle(ctx, ops, dims.X, th.Text, "Input Words")
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.InputWords))
 
 	screen := layout.Rectangle{Max: dims}
 	_, content := screen.CutTop(leadingSize)
@@ -1933,7 +2706,292 @@ func (s *WordKeyboardScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims i
 	return ResultNone
 }
 
-var kbdKeys = [...][]rune{
+// LastWordScreen lists the final words that complete a
+// mnemonic with a valid checksum, for seeds where all but
+// the final word were generated by hand.
+type LastWordScreen struct {
+	candidates []bip39.Word
+	selected   int
+}
+
+func NewLastWordScreen(m bip39.Mnemonic) *LastWordScreen {
+	return &LastWordScreen{
+		candidates: m.LastWords(),
+	}
+}
+
+func (s *LastWordScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (bip39.Word, Result) {
+	for {
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down, CCW, CW)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if e.Click {
+				return -1, ResultCancelled
+			}
+		case Button3, Center:
+			if e.Click {
+				return s.candidates[s.selected], ResultComplete
+			}
+		case Up, CCW:
+			if e.Pressed && s.selected > 0 {
+				s.selected--
+			}
+		case Down, CW:
+			if e.Pressed && s.selected < len(s.candidates)-1 {
+				s.selected++
+			}
+		}
+	}
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.LastWord))
+
+	r := layout.Rectangle{Max: dims}
+	_, list := r.CutTop(leadingSize)
+	list, lead := list.CutBottom(leadingSize)
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text,
+		ctx.Text(locale.Candidate, "N", strconv.Itoa(s.selected+1), "Total", strconv.Itoa(len(s.candidates))))
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+
+	style := ctx.Styles.word
+	longest := widget.Label(op.Ctx{}, style, th.Text, longestWord)
+	content := list.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+	lineHeight := longest.Y + 2
+	linesPerPage := content.Dy() / lineHeight
+	scroll := s.selected - linesPerPage/2
+	if maxScroll := len(s.candidates) - linesPerPage; scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	{
+		ops := ops.Begin()
+		x := content.Min.X + (content.Dx()-longest.X)/2
+		// Only lay out the visible candidates and their neighbours.
+		end := scroll + linesPerPage + 1
+		if end > len(s.candidates) {
+			end = len(s.candidates)
+		}
+		for i := scroll; i < end; i++ {
+			ops.Begin()
+			col := th.Text
+			if i == s.selected {
+				col = th.Background
+				r := image.Rectangle{Max: longest}
+				r.Min.Y -= 3
+				op.MaskOp(ops, assets.ButtonFocused.For(r))
+				op.ColorOp(ops, th.Text)
+			}
+			word := strings.ToUpper(bip39.LabelFor(s.candidates[i]))
+			sz := widget.Label(ops.Begin(), style, col, word)
+			op.Position(ops, ops.End(), image.Pt((longest.X-sz.X)/2, 0))
+			op.Position(ops, ops.End(), image.Pt(x, content.Min.Y+(i-scroll)*lineHeight))
+		}
+	}
+	fadeClip(ops, ops.End(), image.Rectangle(list))
+
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+		NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconCheckmark},
+	)
+	return -1, ResultNone
+}
+
+// FragmentKeyboardScreen is for typing UR fragments in the minimal
+// bytewords style, for recovering data from plates with unreadable
+// QR codes.
+type FragmentKeyboardScreen struct {
+	// Type is the UR type of the fragments.
+	Type string
+
+	decoder ur.Decoder
+	words   []string
+	kbd     *Keyboard
+	cancel  *ConfirmWarningScreen
+	warning *ErrorScreen
+}
+
+func (s *FragmentKeyboardScreen) empty() bool {
+	return len(s.words) == 0 && s.decoder.Progress() == 0
+}
+
+// addWord adds a word to the current fragment and adds the fragment to
+// the decoder if it's complete. It returns the decoded value, if any.
+func (s *FragmentKeyboardScreen) addWord(ctx *Context, w string) (any, Result) {
+	s.words = append(s.words, w)
+	frag := strings.Join(s.words, "")
+	data, err := bytewords.Decode(frag)
+	if err != nil {
+		// The fragment is incomplete until its checksum matches.
+		return nil, ResultNone
+	}
+	s.words = nil
+	var u string
+	if seqNum, seqLen, _, err := fountain.ParseHeader(data); err == nil {
+		u = fmt.Sprintf("ur:%s/%d-%d/%s", s.Type, seqNum, seqLen, frag)
+	} else {
+		u = fmt.Sprintf("ur:%s/%s", s.Type, frag)
+	}
+	if err := s.decoder.Add(u); err != nil {
+		if errors.Is(err, fountain.ErrIncompatiblePart) {
+			s.warning = &ErrorScreen{
+				Title: ctx.Text(locale.InvalidFragment),
+				Body:  ctx.Text(locale.InvalidFragMsg),
+			}
+		} else {
+			s.warning = NewErrorScreen(ctx, err)
+		}
+		return nil, ResultNone
+	}
+	typ, enc, err := s.decoder.Result()
+	if err != nil {
+		s.decoder = ur.Decoder{}
+		s.warning = &ErrorScreen{
+			Title: ctx.Text(locale.InvalidFrags),
+			Body:  ctx.Text(locale.InvalidFragsMsg),
+		}
+		return nil, ResultNone
+	}
+	if enc == nil {
+		return nil, ResultNone
+	}
+	s.decoder = ur.Decoder{}
+	v, err := urtypes.Parse(typ, enc)
+	if err != nil {
+		s.warning = &ErrorScreen{
+			Title: ctx.Text(locale.InvalidData),
+			Body:  ctx.Text(locale.InvalidDataMsg, "Err", err.Error()),
+		}
+		return nil, ResultNone
+	}
+	return v, ResultComplete
+}
+
+func (s *FragmentKeyboardScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) (any, Result) {
+	if s.kbd == nil {
+		s.kbd = newKeyboard(ctx, bytewordsList, kbdKeys)
+	}
+	for {
+		switch {
+		case s.cancel != nil:
+			switch s.cancel.Update(ctx) {
+			case ConfirmYes:
+				return nil, ResultCancelled
+			case ConfirmNo:
+				s.cancel = nil
+				continue
+			}
+		case s.warning != nil:
+			if s.warning.Update(ctx) {
+				s.warning = nil
+				continue
+			}
+		default:
+			s.kbd.Update(ctx)
+		}
+		e, ok := ctx.Next(Button1, Button2)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if !e.Click {
+				break
+			}
+			if n := len(s.words); n > 0 {
+				s.words = s.words[:n-1]
+				break
+			}
+			if s.empty() {
+				return nil, ResultCancelled
+			}
+			s.cancel = &ConfirmWarningScreen{
+				Title: ctx.Text(locale.DiscardFrags),
+				Body:  ctx.Text(locale.DiscardFragsMsg),
+				Icon:  assets.IconDiscard,
+			}
+		case Button2:
+			if !e.Click {
+				break
+			}
+			w, complete := s.kbd.complete()
+			if !complete {
+				break
+			}
+			s.kbd.Clear()
+			if v, res := s.addWord(ctx, bytewordsList[w]); res != ResultNone {
+				return v, res
+			}
+		}
+	}
+	_, complete := s.kbd.complete()
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.InputFragment))
+
+	screen := layout.Rectangle{Max: dims}
+	_, content := screen.CutTop(leadingSize)
+	content, _ = content.CutBottom(8)
+
+	kbdsz := s.kbd.Layout(ctx, ops.Begin(), th)
+	op.Position(ops, ops.End(), content.S(kbdsz))
+
+	style := ctx.Styles.word
+	layoutWord := func(ops op.Ctx, n int, word string) image.Point {
+		txt := fmt.Sprintf("%3d: %s", n, word)
+		return widget.Label(ops, style, th.Background, txt)
+	}
+	longest := layoutWord(op.Ctx{}, 100, "WW")
+	layoutWord(ops.Begin(), len(s.words)+1, s.kbd.Word)
+	word := ops.End()
+	r := image.Rectangle{Max: longest}
+	r.Min.Y -= 3
+	op.MaskOp(ops.Begin(), assets.ButtonFocused.For(r))
+	op.ColorOp(ops, th.Text)
+	word.Add(ops)
+	top, _ := content.CutBottom(kbdsz.Y)
+	op.Position(ops, ops.End(), top.Center(longest))
+
+	// Status line with the most recent words and the decoding progress.
+	const recentWords = 4
+	recent := s.words
+	if len(recent) > recentWords {
+		recent = recent[len(recent)-recentWords:]
+	}
+	status := strings.ToUpper(strings.Join(recent, " "))
+	if p := s.decoder.Progress(); p > 0 {
+		status = fmt.Sprintf("%d%% %s", int(p*100), status)
+	}
+	sz := widget.LabelW(ops.Begin(), ctx.Styles.body, dims.X-2*16, th.Text, status)
+	_, subt := screen.CutTop(leadingSize)
+	op.Position(ops, ops.End(), subt.N(sz).Sub(image.Pt(0, 4)))
+
+	switch {
+	default:
+		icn := assets.IconBack
+		if len(s.words) > 0 {
+			icn = assets.IconBackspace
+		}
+		layoutNavigation(ctx, ops, th, dims,
+			NavButton{Button: Button1, Style: StyleSecondary, Icon: icn},
+		)
+		if complete {
+			layoutNavigation(ctx, ops, th, dims, NavButton{Button: Button2, Style: StylePrimary, Icon: assets.IconCheckmark})
+		}
+	case s.cancel != nil:
+		s.cancel.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	case s.warning != nil:
+		s.warning.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	}
+	return nil, ResultNone
+}
+
+var kbdKeys = [][]rune{
 	[]rune("QWERTYUIOP"),
 	[]rune("ASDFGHJKL"),
 	[]rune("ZXCVBNM⌫"),
@@ -1942,8 +3000,11 @@ var kbdKeys = [...][]rune{
 type Keyboard struct {
 	Word string
 
+	// words constrains the input, if not nil.
+	words     wordList
+	keys      [][]rune
 	nvalid    int
-	positions [len(kbdKeys)][]image.Point
+	positions [][]image.Point
 	bginact   image.Image
 	bgact     image.Image
 	bsinact   image.Image
@@ -1956,8 +3017,68 @@ type Keyboard struct {
 	row, col int
 }
 
+// wordList is an alphabetically sorted list of lower case words.
+type wordList interface {
+	Len() int
+	Word(i int) string
+}
+
+type bip39Words struct{}
+
+func (bip39Words) Len() int {
+	return int(bip39.NumWords)
+}
+
+func (bip39Words) Word(i int) string {
+	return bip39.LabelFor(bip39.Word(i))
+}
+
+// minimalBytewords lists the minimal, two letter forms of
+// the bytewords.
+type minimalBytewords []string
+
+var bytewordsList minimalBytewords
+
+func init() {
+	for i := 0; i < 256; i++ {
+		w := bytewords.Word(byte(i))
+		bytewordsList = append(bytewordsList, w[:1]+w[len(w)-1:])
+	}
+	sort.Strings(bytewordsList)
+}
+
+func (m minimalBytewords) Len() int {
+	return len(m)
+}
+
+func (m minimalBytewords) Word(i int) string {
+	return m[i]
+}
+
+// closestWord is like [bip39.ClosestWord] for any word list.
+func closestWord(l wordList, word string) (int, bool) {
+	n := l.Len()
+	i := sort.Search(n, func(i int) bool {
+		return l.Word(i) >= word
+	})
+	if i == n {
+		return -1, false
+	}
+	return i, strings.HasPrefix(l.Word(i), word)
+}
+
 func NewKeyboard(ctx *Context) *Keyboard {
-	k := new(Keyboard)
+	return newKeyboard(ctx, bip39Words{}, kbdKeys)
+}
+
+// newKeyboard creates a keyboard with a layout of at most 32 keys
+// besides backspace.
+func newKeyboard(ctx *Context, words wordList, keys [][]rune) *Keyboard {
+	k := &Keyboard{
+		words:     words,
+		keys:      keys,
+		positions: make([][]image.Point, len(keys)),
+	}
 	_, k.widest = ctx.Styles.keyboard.Layout(math.MaxInt, "W")
 	bsb := assets.KeyBackspace.Bounds()
 	bsWidth := bsb.Min.X*2 + bsb.Dx()
@@ -1970,15 +3091,15 @@ func NewKeyboard(ctx *Context) *Keyboard {
 	const margin = 2
 	bgsz := bgbnds.Size().Add(image.Pt(margin, margin))
 	longest := 0
-	for _, row := range kbdKeys {
+	for _, row := range k.keys {
 		if n := len(row); n > longest {
 			longest = n
 		}
 	}
 	maxw := longest*bgsz.X - margin
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		n := len(row)
-		if i == len(kbdKeys)-1 {
+		if i == len(k.keys)-1 {
 			// Center row without the backspace key.
 			n--
 		}
@@ -1993,45 +3114,56 @@ func NewKeyboard(ctx *Context) *Keyboard {
 	}
 	k.size = image.Point{
 		X: maxw,
-		Y: len(kbdKeys)*bgsz.Y - margin,
+		Y: len(k.keys)*bgsz.Y - margin,
 	}
 	k.Clear()
 	return k
 }
 
 func (k *Keyboard) Complete() (bip39.Word, bool) {
+	w, complete := k.complete()
+	return bip39.Word(w), complete
+}
+
+// complete is like Complete but returns the index into the
+// keyboard word list.
+func (k *Keyboard) complete() (int, bool) {
 	word := strings.ToLower(k.Word)
-	w, ok := bip39.ClosestWord(word)
+	w, ok := closestWord(k.words, word)
 	if !ok {
 		return -1, false
 	}
 	// The word is complete if it's in the word list or is the only option.
-	return w, k.nvalid == 1 || word == bip39.LabelFor(w)
+	return w, k.nvalid == 1 || word == k.words.Word(w)
 }
 
 func (k *Keyboard) Clear() {
 	k.Word = ""
 	k.updateMask()
-	k.row = len(kbdKeys) / 2
-	k.col = len(kbdKeys[k.row]) / 2
+	k.row = len(k.keys) / 2
+	k.col = len(k.keys[k.row]) / 2
 	k.adjust(false)
 }
 
 func (k *Keyboard) updateMask() {
+	if k.words == nil {
+		k.mask = 0
+		return
+	}
 	k.mask = ^uint32(0)
 	word := strings.ToLower(k.Word)
-	w, valid := bip39.ClosestWord(word)
+	w, valid := closestWord(k.words, word)
 	if !valid {
 		return
 	}
 	k.nvalid = 0
-	for ; w < bip39.NumWords; w++ {
-		bip39w := bip39.LabelFor(w)
-		if !strings.HasPrefix(bip39w, word) {
+	for ; w < k.words.Len(); w++ {
+		kw := k.words.Word(w)
+		if !strings.HasPrefix(kw, word) {
 			break
 		}
 		k.nvalid++
-		suffix := bip39w[len(word):]
+		suffix := kw[len(word):]
 		if len(suffix) > 0 {
 			r := rune(strings.ToUpper(suffix)[0])
 			idx, valid := k.idxForRune(r)
@@ -2047,11 +3179,19 @@ func (k *Keyboard) updateMask() {
 }
 
 func (k *Keyboard) idxForRune(r rune) (int, bool) {
-	idx := int(r - 'A')
-	if idx < 0 || idx >= 32 {
-		return 0, false
+	idx := 0
+	for _, row := range k.keys {
+		for _, key := range row {
+			if key == '⌫' {
+				continue
+			}
+			if key == r {
+				return idx, true
+			}
+			idx++
+		}
 	}
-	return idx, true
+	return 0, false
 }
 
 func (k *Keyboard) Valid(r rune) bool {
@@ -2078,12 +3218,12 @@ func (k *Keyboard) Update(ctx *Context) {
 				next--
 				if next == -1 {
 					if e.Button == CCW {
-						nrows := len(kbdKeys)
+						nrows := len(k.keys)
 						k.row = (k.row - 1 + nrows) % nrows
 					}
-					next = len(kbdKeys[k.row]) - 1
+					next = len(k.keys[k.row]) - 1
 				}
-				if !k.Valid(kbdKeys[k.row][next]) {
+				if !k.Valid(k.keys[k.row][next]) {
 					continue
 				}
 				k.col = next
@@ -2094,14 +3234,14 @@ func (k *Keyboard) Update(ctx *Context) {
 			next := k.col
 			for {
 				next++
-				if next == len(kbdKeys[k.row]) {
+				if next == len(k.keys[k.row]) {
 					if e.Button == CW {
-						nrows := len(kbdKeys)
+						nrows := len(k.keys)
 						k.row = (k.row + 1 + nrows) % nrows
 					}
 					next = 0
 				}
-				if !k.Valid(kbdKeys[k.row][next]) {
+				if !k.Valid(k.keys[k.row][next]) {
 					continue
 				}
 				k.col = next
@@ -2109,7 +3249,7 @@ func (k *Keyboard) Update(ctx *Context) {
 				break
 			}
 		case Up:
-			n := len(kbdKeys)
+			n := len(k.keys)
 			next := k.row
 			for {
 				next = (next - 1 + n) % n
@@ -2119,7 +3259,7 @@ func (k *Keyboard) Update(ctx *Context) {
 				}
 			}
 		case Down:
-			n := len(kbdKeys)
+			n := len(k.keys)
 			next := k.row
 			for {
 				next = (next + 1) % n
@@ -2131,7 +3271,7 @@ func (k *Keyboard) Update(ctx *Context) {
 		case Rune:
 			k.rune(e.Rune)
 		case Center, Button3:
-			r := kbdKeys[k.row][k.col]
+			r := k.keys[k.row][k.col]
 			k.rune(r)
 		}
 	}
@@ -2156,7 +3296,7 @@ func (k *Keyboard) adjust(allowBackspace bool) {
 	dist := int(1e6)
 	current := k.positions[k.row][k.col]
 	found := false
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		j := 0
 		for _, key := range row {
 			if !k.Valid(key) || key == '⌫' && !allowBackspace {
@@ -2186,7 +3326,7 @@ func (k *Keyboard) adjustCol(row int) bool {
 	dist := int(1e6)
 	found := false
 	x := k.positions[k.row][k.col].X
-	for i, r := range kbdKeys[row] {
+	for i, r := range k.keys[row] {
 		if !k.Valid(r) {
 			continue
 		}
@@ -2206,7 +3346,7 @@ func (k *Keyboard) adjustCol(row int) bool {
 }
 
 func (k *Keyboard) Layout(ctx *Context, ops op.Ctx, th *Colors) image.Point {
-	for i, row := range kbdKeys {
+	for i, row := range k.keys {
 		for j, key := range row {
 			valid := k.Valid(key)
 			bg := k.bginact
@@ -2350,10 +3490,18 @@ type MainScreen struct {
 	mnemonic   bip39.Mnemonic
 	page       program
 	scanner    *ScanScreen
+	recovery   *RecoverScreen
+	recovered  *ChoiceScreen
+	addresses  *AddressesScreen
+	fragments  *FragmentKeyboardScreen
 	desc       *DescriptorScreen
 	descriptor *urtypes.OutputDescriptor
 	method     *ChoiceScreen
 	seed       *SeedScreen
+	generate   *GenerateScreen
+	session    *SessionScreen
+	settings   *SettingsScreen
+	history    *HistoryScreen
 	engrave    *EngraveScreen
 	warning    *ErrorScreen
 	error      Warning
@@ -2366,7 +3514,60 @@ type MainScreen struct {
 func (s *MainScreen) Select(ctx *Context) {
 	switch s.page {
 	case backupWallet:
-		s.seed = NewEmptySeedScreen("Input Seed")
+		s.seed = NewEmptySeedScreen(ctx, ctx.Text(locale.InputSeed))
+	case recoverWallet:
+		s.recovery = new(RecoverScreen)
+	case generateSeed:
+		s.generate = NewGenerateScreen(ctx)
+	case multisigSession:
+		s.session = NewSessionScreen(ctx)
+	case engravingHistory:
+		s.history = NewHistoryScreen(ctx)
+	case deviceSettings:
+		s.settings = NewSettingsScreen(ctx.Settings)
+	}
+}
+
+// parseDescriptor interprets the result of a scan or fragment input
+// as a supported output descriptor.
+func parseDescriptor(ctx *Context, res any) (urtypes.OutputDescriptor, *ErrorScreen) {
+	desc, ok := res.(urtypes.OutputDescriptor)
+	if !ok {
+		if b, isbytes := res.([]byte); isbytes {
+			d, err := nonstandard.OutputDescriptor(b)
+			desc, ok = d, err == nil
+		}
+	}
+	if !ok {
+		return desc, &ErrorScreen{
+			Title: ctx.Text(locale.Error),
+			Body:  ctx.Text(locale.NotDescriptor),
+		}
+	}
+
+	if !address.Supported(desc) {
+		return desc, &ErrorScreen{
+			Title: ctx.Text(locale.Error),
+			Body:  ctx.Text(locale.UnsupportedDescriptor),
+		}
+	}
+	desc.Title = backup.TitleString(constant.Font, desc.Title)
+	return desc, nil
+}
+
+// loadDescriptor interprets the result of a scan or fragment input
+// as an output descriptor.
+func (s *MainScreen) loadDescriptor(ctx *Context, res any) {
+	desc, warning := parseDescriptor(ctx, res)
+	if warning != nil {
+		s.warning = warning
+		return
+	}
+	s.method = nil
+	s.descriptor = &desc
+	s.desc = &DescriptorScreen{
+		Descriptor: desc,
+		Mnemonic:   s.mnemonic,
 	}
 }
 
@@ -2380,10 +3581,71 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 	for {
 		switch s.page {
 		case backupWallet:
-			title = "Backup Wallet"
+			title = ctx.Text(locale.BackupWallet)
 			th = &descriptorTheme
+		case recoverWallet:
+			title = ctx.Text(locale.RecoverWallet)
+			th = &singleTheme
+		case generateSeed:
+			title = ctx.Text(locale.GenerateSeed)
+			th = &descriptorTheme
+		case multisigSession:
+			title = ctx.Text(locale.MultisigSession)
+			th = &descriptorTheme
+		case engravingHistory:
+			title = ctx.Text(locale.History)
+			th = &singleTheme
+		case deviceSettings:
+			title = ctx.Text(locale.Settings)
+			th = &singleTheme
 		}
 		switch {
+		case s.history != nil:
+			status := s.history.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return
+			}
+			s.history = nil
+			continue
+		case s.settings != nil:
+			status := s.settings.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return
+			}
+			if status == ResultComplete && s.settings.Settings != ctx.Settings {
+				ctx.SetSettings(s.settings.Settings)
+				ctx.StoreSettings()
+			}
+			s.settings = nil
+			continue
+		case s.session != nil:
+			status := s.session.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return
+			}
+			s.session = nil
+			continue
+		case s.generate != nil:
+			m, status := s.generate.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return
+			}
+			s.generate = nil
+			if status == ResultCancelled {
+				continue
+			}
+			// Confirm the generated words before engraving.
+			s.seed = &SeedScreen{Mnemonic: m}
+			continue
 		case s.seed != nil && s.method == nil && s.desc == nil && s.engrave == nil:
 			m, status := s.seed.Layout(ctx, ops.Begin(), th, dims)
 			dialog := ops.End()
@@ -2397,15 +3659,27 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 				s.seed = nil
 				continue
 			}
+			if s.page == recoverWallet && s.descriptor != nil {
+				// Re-engrave a share of the recovered wallet.
+				s.desc = &DescriptorScreen{
+					Descriptor: *s.descriptor,
+					Mnemonic:   s.mnemonic,
+				}
+				continue
+			}
 			s.method = &ChoiceScreen{
-				Title:   "Descriptor",
-				Lead:    "Choose input method",
-				Choices: []string{"SCAN", "SKIP"},
+				Title: ctx.Text(locale.Descriptor),
+				Lead:  ctx.Text(locale.InputMethod),
+				Choices: []string{
+					ctx.Text(locale.ChoiceScan),
+					ctx.Text(locale.ChoiceSkip),
+					ctx.Text(locale.ChoiceKeyboard),
+				},
 			}
 			if s.descriptor != nil {
 				_, match := descriptorKeyIdx(*s.descriptor, s.mnemonic, "")
 				if match {
-					s.method.Choices = append(s.method.Choices, "RE-USE")
+					s.method.Choices = append(s.method.Choices, ctx.Text(locale.ChoiceReuse))
 				}
 			}
 			continue
@@ -2422,35 +3696,71 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			case ResultCancelled:
 				continue
 			}
-			desc, ok := res.(urtypes.OutputDescriptor)
-			if !ok {
-				if b, isbytes := res.([]byte); isbytes {
-					d, err := nonstandard.OutputDescriptor(b)
-					desc, ok = d, err == nil
-				}
-			}
-			if !ok {
-				s.warning = &ErrorScreen{
-					Title: "Error",
-					Body:  "The scanned data does not represent a wallet output descriptor or XPUB key.",
-				}
-				continue
+			s.loadDescriptor(ctx, res)
+			continue
+		case s.recovery != nil:
+			res, status := s.recovery.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return
 			}
-
-			if !address.Supported(desc) {
-				s.warning = &ErrorScreen{
-					Title: "Error",
-					Body:  "The descriptor is not supported.",
-				}
+			s.recovery = nil
+			switch status {
+			case ResultCancelled:
 				continue
 			}
-			s.method = nil
+			desc := res.Descriptor
 			desc.Title = backup.TitleString(constant.Font, desc.Title)
 			s.descriptor = &desc
-			s.desc = &DescriptorScreen{
-				Descriptor: desc,
-				Mnemonic:   s.mnemonic,
+			s.recovered = &ChoiceScreen{
+				Title:   ctx.Text(locale.Recovered),
+				Lead:    sharesLead(ctx, res.Shares, len(desc.Keys)),
+				Choices: []string{ctx.Text(locale.ChoiceAddresses), ctx.Text(locale.ChoiceEngrave)},
+			}
+			continue
+		case s.addresses != nil:
+			done := s.addresses.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if !done {
+				dialog.Add(ops)
+				return
 			}
+			s.addresses = nil
+			continue
+		case s.recovered != nil && s.seed == nil:
+			choice, status := s.recovered.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return
+			case ResultCancelled:
+				s.recovered = nil
+				continue
+			}
+			switch choice {
+			case 0: // Addresses.
+				s.addresses = NewAddressesScreen(*s.descriptor)
+			case 1: // Engrave a lost share.
+				s.seed = NewEmptySeedScreen(ctx, ctx.Text(locale.InputSeed))
+			}
+			continue
+		case s.fragments != nil:
+			res, status := s.fragments.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return
+			}
+			s.fragments = nil
+			switch status {
+			case ResultCancelled:
+				continue
+			}
+			s.loadDescriptor(ctx, res)
 			continue
 		case s.method != nil && s.engrave == nil && s.warning == nil:
 			choice, status := s.method.Layout(ctx, ops.Begin(), th, dims, s.warning == nil)
@@ -2466,18 +3776,22 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			switch choice {
 			case 0: // Scan.
 				s.scanner = &ScanScreen{
-					Title: "Scan",
-					Lead:  "Wallet Output Descriptor",
+					Title: ctx.Text(locale.Scan),
+					Lead:  ctx.Text(locale.OutputDescriptor),
 				}
 			case 1: // Skip descriptor.
 				s.method = nil
-				plate, err := engraveSeed(s.mnemonic)
+				plate, err := engraveSeed(s.mnemonic, ctx.Settings.PlateSize)
 				if err != nil {
-					s.warning = NewErrorScreen(err)
+					s.warning = NewErrorScreen(ctx, err)
 					break
 				}
 				s.engrave = NewEngraveScreen(ctx, plate)
-			case 2: // Re-use.
+			case 2: // Keyboard.
+				s.fragments = &FragmentKeyboardScreen{
+					Type: "crypto-output",
+				}
+			case 3: // Re-use.
 				s.method = nil
 				s.desc = &DescriptorScreen{
 					Descriptor: *s.descriptor,
@@ -2499,6 +3813,7 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			s.desc = nil
 			s.engrave = nil
 			s.seed = nil
+			s.recovered = nil
 			continue
 		case s.desc != nil:
 			keyIdx, status := s.desc.Layout(ctx, ops.Begin(), dims)
@@ -2512,9 +3827,9 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 				continue
 			}
 			desc := *s.descriptor
-			plate, err := engravePlate(desc, keyIdx, s.mnemonic)
+			plate, err := engravePlate(desc, keyIdx, s.mnemonic, ctx.Settings.PlateSize)
 			if err != nil {
-				s.warning = NewErrorScreen(err)
+				s.warning = NewErrorScreen(ctx, err)
 				break
 			}
 			s.engrave = NewEngraveScreen(ctx, plate)
@@ -2548,12 +3863,14 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			if !e.Click {
 				break
 			}
-			if ctx.NoSDCard || s.sdcard.shown {
+			// The settings and the audit log contain no
+			// secrets and are stored on the SD card.
+			if ctx.NoSDCard || s.sdcard.shown || s.page == engravingHistory || s.page == deviceSettings {
 				s.Select(ctx)
 			} else {
 				s.sdcard.warning = &ConfirmWarningScreen{
-					Title: "Remove SD card",
-					Body:  "Remove SD card to continue.\n\nHold button to ignore this warning.",
+					Title: ctx.Text(locale.RemoveSDCard),
+					Body:  ctx.Text(locale.RemoveSDCardMsg),
 					Icon:  assets.IconRight,
 				}
 			}
@@ -2566,14 +3883,14 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 			}
 			s.page--
 			if s.page < 0 {
-				s.page = backupWallet
+				s.page = program(numPrograms - 1)
 			}
 		case Right:
 			if !e.Pressed {
 				break
 			}
 			s.page++
-			if s.page > backupWallet {
+			if int(s.page) >= numPrograms {
 				s.page = 0
 			}
 		}
@@ -2602,7 +3919,7 @@ func (s *MainScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point, err erro
 		ops.End().Add(ops)
 	case err != nil:
 		s.error.Layout(ctx, ops, th, dims,
-			"Error",
+			ctx.Text(locale.Error),
 			err.Error(),
 		)
 	case s.sdcard.warning != nil:
@@ -2704,8 +4021,7 @@ func (s *MainScreen) layoutPage(ops op.Ctx, th *Colors, width int) image.Point {
 	const margin = 16
 
 	op.Position(ops, content, image.Pt((width-contentsz.X)/2, 8+h.Y(contentsz)))
-	const npage = int(backupWallet) + 1
-	if npage > 1 {
+	if numPrograms > 1 {
 		op.Position(ops, left, image.Pt(margin, h.Y(leftsz)))
 		op.Position(ops, right, image.Pt(width-margin-rightsz.X, h.Y(rightsz)))
 	}
@@ -2715,7 +4031,7 @@ func (s *MainScreen) layoutPage(ops op.Ctx, th *Colors, width int) image.Point {
 
 func (s *MainScreen) layoutMainPlates(ops op.Ctx) image.Point {
 	switch s.page {
-	case backupWallet:
+	case backupWallet, recoverWallet, generateSeed, multisigSession, engravingHistory, deviceSettings:
 		img := assets.Hammer
 		op.ImageOp(ops, img)
 		return img.Bounds().Size()
@@ -2724,7 +4040,7 @@ func (s *MainScreen) layoutMainPlates(ops op.Ctx) image.Point {
 }
 
 func (s *MainScreen) layoutPager(ops op.Ctx, th *Colors) image.Point {
-	const npages = int(backupWallet) + 1
+	const npages = numPrograms
 	const space = 4
 	if npages <= 1 {
 		return image.Point{}
@@ -2745,7 +4061,7 @@ func (s *MainScreen) layoutPager(ops op.Ctx, th *Colors) image.Point {
 type Platform interface {
 	Events() []Event
 	Wakeup()
-	Engraver() (io.ReadWriteCloser, error)
+	Engraver() (driver.Device, error)
 	CameraFrame(size image.Point)
 	Now() time.Time
 	DisplaySize() image.Point
@@ -2756,6 +4072,24 @@ type Platform interface {
 	NextChunk() (draw.RGBA64Image, bool)
 	ScanQR(qr *image.Gray) ([][]byte, error)
 	Debug() bool
+	// LoadSettings reads the persistent device settings.
+	LoadSettings() (settings.Settings, error)
+	// StoreSettings persists the device settings.
+	StoreSettings(s settings.Settings) error
+	// AppendAudit signs and appends entries to the audit log.
+	AppendAudit(entries []auditlog.Entry) error
+	// LoadAudit reads the audit log.
+	LoadAudit() (auditlog.Log, error)
+	// ExportAudit exports the audit log for reading on
+	// another computer.
+	ExportAudit() error
+	// CheckAuditKey reports whether the key that signs the
+	// audit log is available. It returns auditlog.ErrNoKey if
+	// the key is not yet set up.
+	CheckAuditKey() error
+	// SetupAuditKey sets up the audit log key. The key is bound
+	// to the device, and setting it up may be irreversible.
+	SetupAuditKey() error
 }
 
 type FrameEvent interface {
@@ -2812,6 +4146,14 @@ type App struct {
 func NewApp(pl Platform, version string) (*App, error) {
 	ctx := NewContext(pl)
 	ctx.Version = version
+	set, err := pl.LoadSettings()
+	if err != nil {
+		// Don't fail on damaged settings; they are
+		// replaced when changed.
+		log.Printf("settings: %v", err)
+		set = settings.Default()
+	}
+	ctx.SetSettings(set)
 	a := &App{
 		ctx: ctx,
 	}
@@ -2829,6 +4171,8 @@ func (a *App) Frame() {
 		switch e := e.(type) {
 		case SDCardEvent:
 			a.ctx.NoSDCard = !e.Inserted
+			a.ctx.FlushAudit()
+			a.ctx.FlushSettings()
 		case Event:
 			a.ctx.Events(e)
 		}
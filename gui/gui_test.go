@@ -1,12 +1,13 @@
 package gui
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"image"
 	"image/draw"
-	"io"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -14,15 +15,23 @@ import (
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/kortschak/qr"
+	"seedhammer.com/address"
+	"seedhammer.com/auditlog"
 	"seedhammer.com/backup"
+	"seedhammer.com/bc/fountain"
+	"seedhammer.com/bc/ur"
 	"seedhammer.com/bc/urtypes"
 	"seedhammer.com/bip32"
 	"seedhammer.com/bip39"
+	"seedhammer.com/driver"
 	"seedhammer.com/driver/mjolnir"
 	"seedhammer.com/engrave"
 	"seedhammer.com/font/constant"
 	"seedhammer.com/gui/op"
+	"seedhammer.com/image/rgb565"
 	"seedhammer.com/nonstandard"
+	"seedhammer.com/seedqr"
+	"seedhammer.com/settings"
 )
 
 func TestDescriptorScreenError(t *testing.T) {
@@ -131,6 +140,79 @@ func TestMainScreen(t *testing.T) {
 	}
 }
 
+func TestSettings(t *testing.T) {
+	p := newPlatform()
+	p.settings = &settings.Settings{Calibrated: true, Locale: "en"}
+	a, err := NewApp(p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := a.ctx
+	if !ctx.Settings.Calibrated {
+		t.Fatal("stored settings not loaded")
+	}
+	scr := new(MainScreen)
+	frame := func() {
+		scr.Layout(ctx, op.Ctx{}, image.Pt(240, 240), nil)
+	}
+	// Navigate to the settings page.
+	ctxButton(ctx, Left, Button3)
+	frame()
+	if scr.settings == nil {
+		t.Fatal("settings page not opened without SD card warning")
+	}
+	// Select a larger plate and a faster move speed,
+	// then enable the first time guide.
+	ctxButton(ctx, Right, Down, Right, Right, Down, Down, Down, Down, Center)
+	frame()
+	if p.settings.PlateSize != backup.SmallPlate {
+		t.Fatal("settings stored before confirmation")
+	}
+	ctxButton(ctx, Button3)
+	frame()
+	want := settings.Settings{
+		PlateSize: backup.SquarePlate,
+		MoveSpeed: 2. / speedSteps,
+		Locale:    "en",
+	}
+	if *p.settings != want || ctx.Settings != want {
+		t.Errorf("stored settings %+v, want %+v", *p.settings, want)
+	}
+	// Cancelling discards changes.
+	ctxButton(ctx, Button3, Center, Button1)
+	frame()
+	if *p.settings != want {
+		t.Errorf("cancelled settings stored")
+	}
+
+	plate, err := engravePlate(twoOfThree.Descriptor, 0, twoOfThree.Mnemonic, ctx.Settings.PlateSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plate.Size != backup.SquarePlate {
+		t.Errorf("engraved %s plate, want %s", plateName(plate.Size), plateName(backup.SquarePlate))
+	}
+	eng := NewEngraveScreen(ctx, plate)
+	if eng.instructions[0].Body != EngraveFirstSideA[0].Body {
+		t.Error("first time guide not shown")
+	}
+
+	// Settings changed without the SD card are stored when it is
+	// inserted.
+	p.events = append(p.events, SDCardEvent{Inserted: false})
+	a.Frame()
+	ctx.Settings.Calibrated = true
+	ctx.StoreSettings()
+	if p.settings.Calibrated {
+		t.Fatal("settings stored without SD card")
+	}
+	p.events = append(p.events, SDCardEvent{Inserted: true})
+	a.Frame()
+	if !p.settings.Calibrated {
+		t.Error("settings not stored after SD card insertion")
+	}
+}
+
 func TestNonParticipatingSeed(t *testing.T) {
 	// Enter seed not part of the descriptor.
 	mnemonic := make(bip39.Mnemonic, 12)
@@ -156,7 +238,7 @@ func TestNonParticipatingSeed(t *testing.T) {
 func newTestEngraveScreen(t *testing.T, ctx *Context) *EngraveScreen {
 	desc := twoOfThree.Descriptor
 	const keyIdx = 0
-	plate, err := engravePlate(desc, keyIdx, twoOfThree.Mnemonic)
+	plate, err := engravePlate(desc, keyIdx, twoOfThree.Mnemonic, backup.SmallPlate)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -186,6 +268,325 @@ func TestEngraveScreenCancel(t *testing.T) {
 	}
 }
 
+func TestEngraveScreenEstimate(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	scr := newTestEngraveScreen(t, ctx)
+	if len(scr.durations) != len(scr.plate.Sides) {
+		t.Fatalf("%d estimates for %d sides", len(scr.durations), len(scr.plate.Sides))
+	}
+	for i, d := range scr.durations {
+		if d <= 0 {
+			t.Errorf("side %d: non-positive estimate %v", i, d)
+		}
+	}
+	connects := 0
+	for _, ins := range scr.instructions {
+		if ins.Type != ConnectInstruction {
+			continue
+		}
+		connects++
+		if !strings.Contains(ins.resolvedBody, " total)") {
+			t.Errorf("confirmation %q lacks the total estimate", ins.resolvedBody)
+		}
+	}
+	if connects != len(scr.plate.Sides) {
+		t.Errorf("%d confirmations for %d sides", connects, len(scr.plate.Sides))
+	}
+	tests := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{"en", 30 * time.Second, "1 min"},
+		{"en", 5 * time.Minute, "5 min"},
+		{"en", 90*time.Minute + time.Second, "1 h 31 min"},
+		{"de", 90 * time.Minute, "1 Std. 30 Min."},
+		{"ja", 5 * time.Minute, "5分"},
+	}
+	for _, test := range tests {
+		ctx.Settings.Locale = test.locale
+		if got := formatDuration(ctx, test.d); got != test.want {
+			t.Errorf("formatDuration(%v) in %q = %q, want %q", test.d, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestEngraveScreenPreview(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	scr := newTestEngraveScreen(t, ctx)
+	dims := image.Pt(240, 240)
+	ctxButton(ctx, Button2)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	prev := scr.preview
+	if prev == nil {
+		t.Fatal("no preview")
+	}
+	img := prev.cache.img
+	if img == nil {
+		t.Fatal("preview not rendered")
+	}
+	if b := img.Bounds(); b.Dx() > dims.X || b.Dy() > dims.Y {
+		t.Errorf("unzoomed preview %v exceeds the screen", b)
+	}
+	countColors := func(img *rgb565.Image) int {
+		colors := make(map[rgb565.Color]bool)
+		for _, c := range img.Pix {
+			colors[c] = true
+		}
+		return len(colors)
+	}
+	plain := countColors(img)
+	if plain < 2 {
+		t.Fatal("preview is blank")
+	}
+	// Zoom in and pan.
+	ctxButton(ctx, Button3, Right, Down)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if prev.cache.img == img {
+		t.Error("zoomed preview re-used the unzoomed image")
+	}
+	if got, want := prev.cache.img.Bounds().Dx(), img.Bounds().Dx()*2; got < want-1 || got > want+1 {
+		t.Errorf("zoomed preview is %d pixels wide, want %d", got, want)
+	}
+	center := NewPreviewScreen(prev.Plate, prev.Side).center
+	if prev.center.X <= center.X || prev.center.Y <= center.Y {
+		t.Errorf("panned preview center %v, started at %v", prev.center, center)
+	}
+	zoomed := prev.cache.img
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if prev.cache.img != zoomed {
+		t.Error("preview wasn't cached")
+	}
+	// Engraving order.
+	ctxButton(ctx, Center)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if n := countColors(prev.cache.img); n <= plain {
+		t.Errorf("engraving order preview has %d colors, plain preview %d", n, plain)
+	}
+	// Back.
+	ctxButton(ctx, Button1)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if scr.preview != nil {
+		t.Error("preview didn't close")
+	}
+	if scr.step != 0 {
+		t.Errorf("preview moved to step %d", scr.step)
+	}
+}
+
+func TestEngraveScreenVerify(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	scr := newTestEngraveScreen(t, ctx)
+	scr.step = len(scr.instructions) - 1
+	if typ := scr.instructions[scr.step].Type; typ != VerifyInstruction {
+		t.Fatalf("final instruction is %v, want VerifyInstruction", typ)
+	}
+	dims := image.Pt(240, 240)
+	// A QR code from another plate.
+	ctxButton(ctx, Button2)
+	ctxQR(t, ctx, p, string(twoOfThree.Descriptor.Encode()))
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if scr.verify == nil || scr.verify.result == nil || scr.verify.passed {
+		t.Fatal("foreign QR code passed verification")
+	}
+	// Dismiss, and time out.
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	p.timeOffset += verifyTimeout
+	scr.Layout(ctx, op.Ctx{}, dims)
+	if r := scr.verify.result; r == nil || r.Title != "Unreadable QR Code" {
+		t.Fatalf("got result %+v for an unreadable plate", r)
+	}
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, dims)
+	for _, qr := range scr.plate.QRs {
+		ctxQR(t, ctx, p, strings.ToLower(string(qr)))
+		scr.Layout(ctx, op.Ctx{}, dims)
+	}
+	if !scr.verify.passed {
+		t.Fatal("plate QR codes failed verification")
+	}
+	ctxButton(ctx, Button3)
+	if res := scr.Layout(ctx, op.Ctx{}, dims); res != ResultComplete {
+		t.Errorf("verified screen returned %v, want ResultComplete", res)
+	}
+}
+
+func TestVerifySeedQR(t *testing.T) {
+	m := twoOfThree.Mnemonic
+	other := make(bip39.Mnemonic, len(m))
+	copy(other, m)
+	other[0] = (other[0] + 1) % bip39.NumWords
+	other = other.FixChecksum()
+	scr := &VerifyScreen{Plate: Plate{QRs: [][]byte{seedqr.QR(m)}}}
+	if scr.match(seedqr.CompactQR(m)) != 0 {
+		t.Error("compact SeedQR didn't match standard SeedQR")
+	}
+	if scr.match(seedqr.QR(other)) != -1 {
+		t.Error("SeedQR of another seed matched")
+	}
+}
+
+func TestSessionScreen(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	scr := NewSessionScreen(ctx)
+	if w := scr.load(ctx, twoOfThree.Descriptor); w != nil {
+		t.Fatalf("failed to load descriptor: %s", w.Body)
+	}
+	dims := image.Pt(240, 240)
+	layout := func() Result {
+		return scr.Layout(ctx, op.Ctx{}, &descriptorTheme, dims)
+	}
+	if w := scr.addSeed(ctx, twoOfThree.Mnemonic); w != nil {
+		t.Fatalf("failed to add seed: %s", w.Body)
+	}
+	if scr.engrave == nil || scr.keyIdx != 0 {
+		t.Fatalf("engraving share %d, want 0", scr.keyIdx)
+	}
+	// Complete the engraving without verification.
+	scr.engrave.step = len(scr.engrave.instructions) - 1
+	ctxButton(ctx, Button3)
+	layout()
+	sh := scr.shares[0]
+	if sh == nil || sh.Verified {
+		t.Fatalf("got share %+v after engraving, want unverified share", sh)
+	}
+	if n := scr.remaining(); n != 2 {
+		t.Errorf("%d shares remaining, want 2", n)
+	}
+	// The same seed again.
+	if w := scr.addSeed(ctx, twoOfThree.Mnemonic); w == nil || w.Title != "Share Already Engraved" {
+		t.Errorf("engraved share accepted again")
+	}
+	// A seed not in the wallet.
+	other := make(bip39.Mnemonic, 12)
+	for i := range other {
+		other[i] = bip39.RandomWord()
+	}
+	if w := scr.addSeed(ctx, other.FixChecksum()); w == nil || w.Title != "Unknown Wallet" {
+		t.Errorf("foreign seed accepted")
+	}
+	// Ending the session early requires confirmation.
+	ctxButton(ctx, Button1)
+	if res := layout(); res != ResultNone || scr.cancel == nil {
+		t.Fatalf("incomplete session ended without confirmation")
+	}
+	ctxButton(ctx, Button3)
+	layout()
+	if scr.seed != nil {
+		t.Fatal("seed input started behind the confirmation")
+	}
+}
+
+func TestAddressesScreenVerify(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	ctx.Settings.AddressGap = 10
+	desc := twoOfThree.Descriptor
+	scr := NewAddressesScreen(desc)
+	dims := image.Pt(240, 240)
+	layout := func() {
+		scr.Layout(ctx, op.Ctx{}, dims)
+	}
+	// result waits for the search and dismisses its result.
+	result := func() string {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for scr.result == nil {
+			if time.Now().After(deadline) {
+				t.Fatal("address search timed out")
+			}
+			layout()
+		}
+		title := scr.result.Title
+		ctxButton(ctx, Button3)
+		layout()
+		return title
+	}
+	change, err := address.Change(desc, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Enter the change address with the keyboard.
+	ctxButton(ctx, Button3)
+	layout()
+	ctxButton(ctx, Down, Button3)
+	layout()
+	if scr.keyboard == nil {
+		t.Fatal("address keyboard not opened")
+	}
+	ctxString(ctx, strings.ToUpper(strings.TrimPrefix(change, "bc1")))
+	ctxButton(ctx, Button2)
+	layout()
+	if got := result(); got != "Address Found" {
+		t.Errorf("entered change address resulted in %q", got)
+	}
+
+	// Scan a receive address beyond the gap.
+	beyond, err := address.Receive(desc, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctxButton(ctx, Button3)
+	layout()
+	ctxButton(ctx, Button3)
+	layout()
+	ctxQR(t, ctx, p, "bitcoin:"+strings.ToUpper(beyond))
+	layout()
+	if got := result(); got != "Not in Wallet" {
+		t.Errorf("address beyond the gap resulted in %q", got)
+	}
+
+	// Scan something else.
+	ctxButton(ctx, Button3)
+	layout()
+	ctxButton(ctx, Button3)
+	layout()
+	ctxQR(t, ctx, p, "not an address")
+	layout()
+	if got := result(); got != "Invalid Address" {
+		t.Errorf("invalid address resulted in %q", got)
+	}
+}
+
+func TestKeysScreen(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	desc := twoOfThree.Descriptor
+	scr := &DescriptorScreen{
+		Descriptor: desc,
+		Mnemonic:   twoOfThree.Mnemonic,
+	}
+	ctxButton(ctx, Button2)
+	scr.Layout(ctx, op.Ctx{}, image.Pt(240, 240))
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Pt(240, 240))
+	if scr.keys == nil || scr.keys.keyIdx != 0 {
+		t.Fatal("keys screen not opened with the matching key")
+	}
+	for i, k := range desc.Keys {
+		if unusualPath(desc.Script, k) {
+			t.Errorf("key %d: standard path %s flagged", i, k.DerivationPath)
+		}
+	}
+	if mixedNetworks(desc) {
+		t.Error("mainnet keys flagged as mixing networks")
+	}
+	odd := desc
+	odd.Keys = append([]urtypes.KeyDescriptor(nil), desc.Keys...)
+	odd.Keys[1].DerivationPath = urtypes.Path{hdkeychain.HardenedKeyStart + 48}
+	odd.Keys[2].Network = &chaincfg.TestNet3Params
+	if !unusualPath(odd.Script, odd.Keys[1]) {
+		t.Errorf("unusual path %s not flagged", odd.Keys[1].DerivationPath)
+	}
+	if !unusualPath(odd.Script, odd.Keys[2]) {
+		t.Error("mainnet path of testnet key not flagged")
+	}
+	if !mixedNetworks(odd) {
+		t.Error("mixed networks not flagged")
+	}
+}
+
 func TestEngraveError(t *testing.T) {
 	nonstdPath := []uint32{
 		hdkeychain.HardenedKeyStart + 86,
@@ -211,7 +612,7 @@ func TestEngraveError(t *testing.T) {
 				Keys:      make([]urtypes.KeyDescriptor, test.keys),
 			}
 			mnemonic := fillDescriptor(t, desc, test.path, 12, 0)
-			_, err := engravePlate(desc, 0, mnemonic)
+			_, err := engravePlate(desc, 0, mnemonic, backup.SmallPlate)
 			if err == nil {
 				t.Fatal("invalid descriptor succeeded")
 			}
@@ -271,6 +672,430 @@ func TestEngraveScreenConnectionError(t *testing.T) {
 	<-p.engrave.closed
 }
 
+func TestEngraveScreenResume(t *testing.T) {
+	p := newPlatform()
+	p.engrave.closed = make(chan []mjolnir.Cmd, 1)
+	p.engrave.ioErr = errors.New("error during engraving")
+	p.engrave.ioErrAfter = 200
+	ctx := NewContext(p)
+	scr := newTestEngraveScreen(t, ctx)
+	connect := func() {
+		for scr.instructions[scr.step].Type != ConnectInstruction {
+			ctxButton(ctx, Button3)
+			scr.Layout(ctx, op.Ctx{}, image.Point{})
+		}
+		ctxPress(ctx, Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+		p.timeOffset += confirmDelay
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	connect()
+	side := scr.instructions[scr.step].Side
+	for scr.engrave.warning == nil {
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	<-p.engrave.closed
+	if scr.resume == nil || scr.resume.side != side || scr.resume.completed == 0 {
+		t.Fatalf("interrupted engraving not resumable: %+v", scr.resume)
+	}
+	completed := scr.resume.completed
+	// Dismiss error and choose resume.
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.resumeChoice == nil {
+		t.Fatal("no resume choice after interrupted engraving")
+	}
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.resumeChoice != nil || scr.resume == nil {
+		t.Fatal("resume choice not accepted")
+	}
+	p.engrave.ioErr = nil
+	connect()
+	if scr.resume != nil {
+		t.Error("resume state not cleared")
+	}
+	if ins := scr.instructions[scr.step]; ins.Type != EngraveInstruction || ins.Side != side {
+		t.Fatalf("resumed at instruction %+v", ins)
+	}
+	total := new(instructionCounter)
+	scr.plate.Sides[side].Engrave(total)
+	if got, want := scr.engrave.offset, float32(completed)/float32(*total); got != want {
+		t.Errorf("resumed at progress %v, want %v", got, want)
+	}
+	for scr.instructions[scr.step].Type == EngraveInstruction {
+		if p := scr.engrave.lastProgress; p < scr.engrave.offset {
+			t.Fatalf("progress %v before the resume offset", p)
+		}
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	if scr.engrave.warning != nil {
+		t.Fatal("resumed engraving failed")
+	}
+	<-p.engrave.closed
+}
+
+func TestEngraveScreenAudit(t *testing.T) {
+	p := newPlatform()
+	p.engrave.closed = make(chan []mjolnir.Cmd, 1)
+	p.engrave.ioErr = errors.New("error during engraving")
+	p.engrave.ioErrAfter = 200
+	ctx := NewContext(p)
+	ctx.Version = "v1.2.3"
+	ctx.NoSDCard = true
+	scr := newTestEngraveScreen(t, ctx)
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	ctxPress(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	side := scr.instructions[scr.step].Side
+	for scr.engrave.warning == nil {
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	<-p.engrave.closed
+	if len(p.audit) != 0 || len(ctx.audit) != 1 {
+		t.Fatalf("audit entry stored without SD card")
+	}
+	ctx.NoSDCard = false
+	ctx.FlushAudit()
+	if len(p.audit) != 1 || len(ctx.audit) != 0 {
+		t.Fatalf("audit entry not stored after SD card insertion")
+	}
+	desc := twoOfThree.Descriptor
+	e := p.audit[0]
+	want := auditlog.Entry{
+		Time:               e.Time,
+		Version:            ctx.Version,
+		Plate:              plateName(scr.plate.Size),
+		Side:               side,
+		Share:              0,
+		Shares:             len(desc.Keys),
+		MasterFingerprint:  fmt.Sprintf("%.8x", desc.Keys[0].MasterFingerprint),
+		DescriptorChecksum: fmt.Sprintf("%.8x", fountain.Checksum(desc.Encode())),
+		Title:              desc.Title,
+		Estimated:          scr.durations[side],
+		Actual:             e.Actual,
+		Error:              e.Error,
+	}
+	if e != want {
+		t.Errorf("got audit entry\n%+v\nwant\n%+v", e, want)
+	}
+	if e.Error == "" || e.Time.IsZero() || e.Actual <= 0 {
+		t.Errorf("audit entry %+v lacks the outcome of the engraving", e)
+	}
+}
+
+func TestHistoryScreen(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	ctx.Audit(auditlog.Entry{Plate: "SH01", Shares: 1, MasterFingerprint: "dc567276", Error: "cancelled"})
+	ctx.NoSDCard = true
+	ctx.Audit(auditlog.Entry{Plate: "SH02", Side: 1, Shares: 3, DryRun: true})
+	dims := image.Pt(240, 240)
+	scr := NewHistoryScreen(ctx)
+	if !scr.unavailable || len(scr.pending) != 1 {
+		t.Fatalf("history without SD card: unavailable %v, %d pending", scr.unavailable, len(scr.pending))
+	}
+	// Export requires the SD card.
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, &singleTheme, dims)
+	if scr.result != nil {
+		t.Fatal("exported without SD card")
+	}
+	ctx.NoSDCard = false
+	scr = NewHistoryScreen(ctx)
+	if scr.unavailable || len(scr.pending) != 0 || len(scr.log.Records) != 2 {
+		t.Fatalf("history with SD card: unavailable %v, %d pending, %d stored", scr.unavailable, len(scr.pending), len(scr.log.Records))
+	}
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, &singleTheme, dims)
+	if r := scr.result; r == nil || r.Title != "Log Exported" {
+		t.Fatalf("got result %+v after export", r)
+	}
+	ctxButton(ctx, Button3, Button1)
+	if res := scr.Layout(ctx, op.Ctx{}, &singleTheme, dims); res != ResultCancelled {
+		t.Errorf("history screen returned %v, want ResultCancelled", res)
+	}
+}
+
+func TestAuditKeySetup(t *testing.T) {
+	p := newPlatform()
+	p.noAuditKey = true
+	ctx := NewContext(p)
+	ctx.Audit(auditlog.Entry{Plate: "SH01", Shares: 1})
+	if len(p.audit) != 0 || len(ctx.audit) != 1 {
+		t.Fatal("audit entry stored without audit key")
+	}
+	dims := image.Pt(240, 240)
+	hist := NewHistoryScreen(ctx)
+	if !hist.unsigned || len(hist.pending) != 1 {
+		t.Fatalf("history without audit key: unsigned %v, %d pending", hist.unsigned, len(hist.pending))
+	}
+	scr := new(SettingsScreen)
+	frame := func() {
+		scr.Layout(ctx, op.Ctx{}, &singleTheme, dims)
+	}
+	for i := 0; i < int(fieldAudit); i++ {
+		ctxButton(ctx, Down)
+	}
+	frame()
+	if scr.field != fieldAudit {
+		t.Fatalf("selected field %v, want the audit key", scr.field)
+	}
+	// Cancelling leaves the key alone.
+	ctxButton(ctx, Center, Button1)
+	frame()
+	if scr.confirm != nil || !p.noAuditKey {
+		t.Fatal("audit key set up without confirmation")
+	}
+	ctxButton(ctx, Center)
+	frame()
+	ctxPress(ctx, Button3)
+	frame()
+	if !p.noAuditKey {
+		t.Fatal("audit key set up before the confirmation delay")
+	}
+	p.timeOffset += confirmDelay
+	frame()
+	if p.noAuditKey {
+		t.Fatal("audit key not set up after confirmation")
+	}
+	if r := scr.result; r == nil || r.Title != "Audit Key Set Up" {
+		t.Fatalf("got result %+v after setting up the audit key", r)
+	}
+	if len(p.audit) != 1 || len(ctx.audit) != 0 {
+		t.Fatal("pending audit entry not stored after setting up the audit key")
+	}
+	// An existing key cannot be set up again.
+	ctxButton(ctx, Button3, Center)
+	frame()
+	if scr.result != nil || scr.confirm != nil {
+		t.Fatal("audit key setup offered for a device with a key")
+	}
+	hist = NewHistoryScreen(ctx)
+	if hist.unsigned || hist.unavailable || len(hist.log.Records) != 1 {
+		t.Fatalf("history with audit key: unsigned %v, unavailable %v, %d stored", hist.unsigned, hist.unavailable, len(hist.log.Records))
+	}
+}
+
+func TestEngraveScreenFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   mjolnir.Fault
+		warning string
+	}{
+		{"drop", mjolnir.Fault{Type: mjolnir.FaultDrop, Batch: 5}, ""},
+		{"delay", mjolnir.Fault{Type: mjolnir.FaultDelay, Batch: 5, Delay: 10 * time.Millisecond}, ""},
+		{"cancel", mjolnir.Fault{Type: mjolnir.FaultCancel, Batch: 5}, "Engraving Cancelled"},
+		{"disconnect", mjolnir.Fault{Type: mjolnir.FaultDisconnect, Batch: 5}, "Connection Error"},
+		{"garbage", mjolnir.Fault{Type: mjolnir.FaultGarbage, Batch: 5}, "Connection Error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			p := newPlatform()
+			p.engrave.closed = make(chan []mjolnir.Cmd, 1)
+			p.engrave.fault = test.fault
+			ctx := NewContext(p)
+			scr := newTestEngraveScreen(t, ctx)
+			for scr.instructions[scr.step].Type != ConnectInstruction {
+				ctxButton(ctx, Button3)
+				scr.Layout(ctx, op.Ctx{}, image.Point{})
+			}
+			ctxPress(ctx, Button3)
+			scr.Layout(ctx, op.Ctx{}, image.Point{})
+			p.timeOffset += confirmDelay
+			scr.Layout(ctx, op.Ctx{}, image.Point{})
+			side := scr.instructions[scr.step].Side
+			for scr.engrave.warning == nil && scr.instructions[scr.step].Type == EngraveInstruction {
+				scr.Layout(ctx, op.Ctx{}, image.Point{})
+			}
+			<-p.engrave.closed
+			switch w := scr.engrave.warning; {
+			case test.warning == "" && w != nil:
+				t.Errorf("engraving failed: %s: %s", w.Title, w.Body)
+			case test.warning != "" && w == nil:
+				t.Errorf("engraving succeeded, expected %q", test.warning)
+			case w != nil && w.Title != test.warning:
+				t.Errorf("got warning %q, want %q", w.Title, test.warning)
+			case w != nil && (scr.resume == nil || scr.resume.side != side):
+				t.Error("interrupted engraving not resumable")
+			}
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > before {
+				if time.Now().After(deadline) {
+					t.Fatalf("%d goroutines leaked", runtime.NumGoroutine()-before)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestEngraveScreenNotParked(t *testing.T) {
+	p := newPlatform()
+	p.engrave.closed = make(chan []mjolnir.Cmd, 1)
+	p.engrave.misparked = true
+	ctx := NewContext(p)
+	scr := newTestEngraveScreen(t, ctx)
+	for scr.instructions[scr.step].Type != ConnectInstruction {
+		ctxButton(ctx, Button3)
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	ctxPress(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	p.timeOffset += confirmDelay
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	step := scr.step
+	for scr.engrave.warning == nil {
+		scr.Layout(ctx, op.Ctx{}, image.Point{})
+	}
+	<-p.engrave.closed
+	if w := scr.engrave.warning; w.Title != "Needle Not Parked" {
+		t.Errorf("got warning %q, want %q", w.Title, "Needle Not Parked")
+	}
+	if scr.step != step+1 || scr.resume != nil {
+		t.Errorf("completed engraving at step %d not advanced", step)
+	}
+	if n := len(p.audit); n != 1 || p.audit[0].Error != "" {
+		t.Errorf("completed engraving audited as %+v", p.audit)
+	}
+}
+
+// misparkedEngraver expects the needle to park away from where
+// the engraver leaves it.
+type misparkedEngraver struct {
+	*mjolnir.Engraver
+}
+
+func (m misparkedEngraver) ParkPosition() image.Point {
+	return m.Engraver.ParkPosition().Add(image.Pt(1, 1))
+}
+
+func TestGenerateScreen(t *testing.T) {
+	layout := func(ctx *Context, scr *GenerateScreen) (bip39.Mnemonic, Result) {
+		return scr.Layout(ctx, op.Ctx{}, &descriptorTheme, image.Point{})
+	}
+	t.Run("dice hash", func(t *testing.T) {
+		ctx := NewContext(newPlatform())
+		scr := NewGenerateScreen(ctx)
+		// 12 words, dice hash.
+		ctxButton(ctx, Button3, Down, Button3)
+		var rolls []int
+		for i := 0; i < bip39.DiceRolls(12); i++ {
+			r := i%6 + 1
+			rolls = append(rolls, r)
+			ctxString(ctx, fmt.Sprint(r))
+		}
+		// Skip camera noise.
+		ctxButton(ctx, Button3)
+		m, res := layout(ctx, scr)
+		if res != ResultComplete {
+			t.Fatalf("generation didn't complete: %v", res)
+		}
+		if want := bip39.New(bip39.DiceEntropy(rolls, 12)); !reflect.DeepEqual(m, want) {
+			t.Errorf("generated %v, want %v", m, want)
+		}
+	})
+	t.Run("coin flips with camera", func(t *testing.T) {
+		p := newPlatform()
+		ctx := NewContext(p)
+		scr := NewGenerateScreen(ctx)
+		// 24 words, coin flips.
+		ctxButton(ctx, Down, Button3, Down, Down, Button3)
+		ent := make([]byte, bip39.EntropySize(24))
+		for i := 0; i < len(ent)*8; i++ {
+			bit := i % 3 / 2
+			ent[i/8] |= byte(bit) << (7 - i%8)
+			ctxString(ctx, fmt.Sprint(bit))
+		}
+		// Mix in camera noise.
+		ctxButton(ctx, Down, Button3)
+		if _, res := layout(ctx, scr); res != ResultNone {
+			t.Fatalf("generation completed without camera noise")
+		}
+		h := sha256.New()
+		var m bip39.Mnemonic
+		for i := 0; i < noiseFrames; i++ {
+			img := image.NewYCbCr(image.Rect(0, 0, 4, 4), image.YCbCrSubsampleRatio420)
+			for j := range img.Y {
+				img.Y[j] = byte(i * j)
+			}
+			h.Write(img.Y)
+			h.Write(img.Cb)
+			h.Write(img.Cr)
+			ctx.Events(testFrame{Img: img})
+			var res Result
+			m, res = layout(ctx, scr)
+			if complete := res == ResultComplete; complete != (i == noiseFrames-1) {
+				t.Fatalf("frame %d: result %v", i, res)
+			}
+		}
+		want := bip39.New(mixNoise(ent, h.Sum(nil)))
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("generated %v, want %v", m, want)
+		}
+	})
+	t.Run("dice words", func(t *testing.T) {
+		ctx := NewContext(newPlatform())
+		scr := NewGenerateScreen(ctx)
+		// 12 words, dice words.
+		ctxButton(ctx, Button3, Button3)
+		// A roll without a word.
+		ctxString(ctx, "16666")
+		layout(ctx, scr)
+		if scr.warning == nil {
+			t.Fatal("invalid roll accepted")
+		}
+		ctxButton(ctx, Button3)
+		var words bip39.Mnemonic
+		for i := 0; i < 12; i++ {
+			roll := bip39.Roll{i%5 + 1, 2, 3, 4, 5}
+			w, ok := bip39.DiceToWord(roll)
+			if !ok {
+				t.Fatalf("invalid test roll %v", roll)
+			}
+			words = append(words, w)
+			for _, r := range roll {
+				ctxString(ctx, fmt.Sprint(r))
+			}
+		}
+		ctxButton(ctx, Button3)
+		m, res := layout(ctx, scr)
+		if res != ResultComplete {
+			t.Fatalf("generation didn't complete: %v", res)
+		}
+		if !m.Valid() {
+			t.Errorf("generated invalid mnemonic %v", m)
+		}
+		if !reflect.DeepEqual(m[:11], words[:11]) {
+			t.Errorf("generated %v, want rolled words %v", m, words)
+		}
+	})
+	t.Run("dice words after discarded entropy", func(t *testing.T) {
+		ctx := NewContext(newPlatform())
+		scr := NewGenerateScreen(ctx)
+		// 12 words, dice words.
+		ctxButton(ctx, Button3, Button3)
+		for i := 0; i < 12; i++ {
+			ctxString(ctx, "11111")
+		}
+		// Discard the entropy and choose dice words again.
+		ctxButton(ctx, Button1, Button3)
+		ctxString(ctx, "11111")
+		if _, res := layout(ctx, scr); res != ResultNone {
+			t.Fatalf("generation ended after a single word: %v", res)
+		}
+		if n := len(scr.words); n != 1 {
+			t.Fatalf("%d words rolled after restarting, want 1", n)
+		}
+	})
+}
+
 func TestScanScreenError(t *testing.T) {
 	p := newPlatform()
 	// Fail on connect.
@@ -291,6 +1116,41 @@ func TestScanScreenError(t *testing.T) {
 	}
 }
 
+func TestScanScreenMalformedPart(t *testing.T) {
+	desc := twoOfThree.Descriptor
+	data := desc.Encode()
+	const seqLen = 3
+	scr := new(ScanScreen)
+	if _, res := scr.parseQR([]byte(ur.Encode("crypto-output", data, 1, seqLen))); res != ResultNone {
+		t.Fatalf("decoded from a single part (result %v)", res)
+	}
+	// A part exceeding the decoder limits.
+	big := ur.Encode("crypto-output", make([]byte, 2000), 1, 2000)
+	scr.parseQR([]byte(big))
+	if !malformedQR(scr.decodeErr) {
+		t.Fatalf("got error %v for a malformed part", scr.decodeErr)
+	}
+	if scr.decoder.Progress() == 0 {
+		t.Fatal("malformed part discarded the previous parts")
+	}
+	var v any
+	for seqNum := 2; seqNum <= seqLen; seqNum++ {
+		var res Result
+		v, res = scr.parseQR([]byte(ur.Encode("crypto-output", data, seqNum, seqLen)))
+		if (res == ResultComplete) != (seqNum == seqLen) {
+			t.Fatalf("part %d: result %v", seqNum, res)
+		}
+	}
+	got, ok := v.(urtypes.OutputDescriptor)
+	if !ok {
+		t.Fatalf("parts decoded to %T, expected a descriptor", v)
+	}
+	got.Title = desc.Title
+	if !reflect.DeepEqual(got, desc) {
+		t.Errorf("parts decoded to %v, expected %v", got, desc)
+	}
+}
+
 func TestWordKeyboardScreen(t *testing.T) {
 	ctx := NewContext(newPlatform())
 	for i := bip39.Word(0); i < bip39.NumWords; i++ {
@@ -310,6 +1170,88 @@ func TestWordKeyboardScreen(t *testing.T) {
 	}
 }
 
+func TestFragmentKeyboardScreen(t *testing.T) {
+	ctx := NewContext(newPlatform())
+	desc := twoOfThree.Descriptor
+	data := desc.Encode()
+	const seqLen = 2
+	scr := &FragmentKeyboardScreen{Type: "crypto-output"}
+	var v any
+	res := ResultNone
+	for seqNum := 1; seqNum <= seqLen; seqNum++ {
+		frag := ur.Encode("crypto-output", data, seqNum, seqLen)
+		frag = frag[strings.LastIndexByte(frag, '/')+1:]
+		for i := 0; i < len(frag); i += 2 {
+			if res != ResultNone {
+				t.Fatal("fragments decoded before the final word")
+			}
+			ctxString(ctx, strings.ToUpper(frag[i:i+2]))
+			ctxButton(ctx, Button2)
+			v, res = scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+		}
+		if seqNum < seqLen && scr.decoder.Progress() == 0 {
+			t.Errorf("fragment %d not accepted", seqNum)
+		}
+	}
+	if res != ResultComplete {
+		t.Fatalf("fragments not decoded (warning: %v)", scr.warning)
+	}
+	got, ok := v.(urtypes.OutputDescriptor)
+	if !ok {
+		t.Fatalf("fragments decoded to %T, expected a descriptor", v)
+	}
+	got.Title = desc.Title
+	if !reflect.DeepEqual(got, desc) {
+		t.Errorf("fragments decoded to %v, expected %v", got, desc)
+	}
+}
+
+func TestRecoverScreen(t *testing.T) {
+	p := newPlatform()
+	ctx := NewContext(p)
+	desc := twoOfThree.Descriptor
+	data := desc.Encode()
+	check := fountain.Checksum(data)
+	// The descriptor QR codes of the first and last share of
+	// a 2-of-3 backup.
+	first := ur.Encode("crypto-output", data, fountain.SeqNumFor(2, check, []int{0}), 2)
+	last := ur.Encode("crypto-output", data, fountain.SeqNumFor(2, check, []int{0, 1}), 2)
+	scr := new(RecoverScreen)
+	ctxQR(t, ctx, p, first, first)
+	if _, res := scr.Layout(ctx, op.Ctx{}, image.Point{}); res != ResultNone {
+		t.Fatalf("recovered from a single share (result %v)", res)
+	}
+	if n := len(scr.parts); n != 1 {
+		t.Errorf("scanned %d QR codes, expected 1", n)
+	}
+	// The first plate alone doesn't tell a 2-of-3 from a 2-of-2
+	// backup.
+	if scr.scanner.Plates < 2 || !reflect.DeepEqual(scr.scanner.Scanned, []int{0}) {
+		t.Errorf("plate status %v of %d, expected the first plate", scr.scanner.Scanned, scr.scanner.Plates)
+	}
+	if lead := scr.scanner.Lead; !strings.HasPrefix(lead, "Shares 1 of ") {
+		t.Errorf("scanning lead %q doesn't list the first share", lead)
+	}
+	ctxQR(t, ctx, p, ur.Encode("crypto-seed", []byte{0x01}, 1, 1))
+	scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if scr.scanner.decodeErr == nil {
+		t.Error("mismatched QR code accepted")
+	}
+	ctxQR(t, ctx, p, last)
+	r, res := scr.Layout(ctx, op.Ctx{}, image.Point{})
+	if res != ResultComplete {
+		t.Fatal("failed to recover descriptor")
+	}
+	got := r.Descriptor
+	got.Title = desc.Title
+	if !reflect.DeepEqual(got, desc) {
+		t.Errorf("recovered %v, expected %v", got, desc)
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(r.Shares, want) {
+		t.Errorf("recovered from shares %v, expected %v", r.Shares, want)
+	}
+}
+
 func ctxQR(t *testing.T, ctx *Context, p *testPlatform, qrs ...string) {
 	t.Helper()
 	for _, qr := range qrs {
@@ -320,7 +1262,7 @@ func ctxQR(t *testing.T, ctx *Context, p *testPlatform, qrs ...string) {
 func TestSeedScreenScan(t *testing.T) {
 	p := newPlatform()
 	ctx := NewContext(p)
-	scr := NewEmptySeedScreen("")
+	scr := NewEmptySeedScreen(ctx, "")
 	// Select camera.
 	ctxButton(ctx, Down, Button3)
 	ctxQR(t, ctx, p, "011513251154012711900771041507421289190620080870026613431420201617920614089619290300152408010643")
@@ -338,7 +1280,7 @@ func TestSeedScreenScan(t *testing.T) {
 func TestSeedScreenScanInvalid(t *testing.T) {
 	p := newPlatform()
 	ctx := NewContext(p)
-	scr := NewEmptySeedScreen("")
+	scr := NewEmptySeedScreen(ctx, "")
 	// Select camera.
 	ctxButton(ctx, Down, Button3)
 	ctxQR(t, ctx, p, "UR:CRYPTO-SEED/OYADGDIYWLAMAEJSZSWDWYTLTIFEENFTLNMNWKBDHNSSRO")
@@ -348,6 +1290,51 @@ func TestSeedScreenScanInvalid(t *testing.T) {
 	}
 }
 
+func TestSeedScreenLastWord(t *testing.T) {
+	want, err := bip39.ParseMnemonic("attack pizza motion avocado network gather crop fresh patrol unusual wild holiday candy pony ranch winter theme error hybrid van cereal salon goddess expire")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext(newPlatform())
+	scr := NewEmptySeedScreen(ctx, "")
+	// Select last word calculator, 24 words.
+	ctxButton(ctx, Down, Down, Button3, Down, Button3)
+	for _, w := range want[:len(want)-1] {
+		ctxString(ctx, strings.ToUpper(bip39.LabelFor(w)))
+		ctxButton(ctx, Button2)
+		scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+	}
+	if scr.candidates == nil {
+		t.Fatal("no last word candidates after input")
+	}
+	if n := len(scr.candidates.candidates); n != 8 {
+		t.Fatalf("got %d candidates, want 8", n)
+	}
+	idx := -1
+	for i, w := range scr.candidates.candidates {
+		if w == want[len(want)-1] {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		t.Fatal("last word missing from candidates")
+	}
+	for i := 0; i < idx; i++ {
+		ctxPress(ctx, Down)
+	}
+	ctxButton(ctx, Button3)
+	scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+	if scr.candidates != nil {
+		t.Fatal("candidate not picked")
+	}
+	// Confirm seed.
+	ctxButton(ctx, Button3)
+	got, res := scr.Layout(ctx, op.Ctx{}, &singleTheme, image.Point{})
+	if res != ResultComplete || !reflect.DeepEqual(got, want) {
+		t.Errorf("got seed %v (%v), want %v", got, res, want)
+	}
+}
+
 func NewSeedScreen(m bip39.Mnemonic) *SeedScreen {
 	return &SeedScreen{
 		Mnemonic: m,
@@ -467,7 +1454,8 @@ func TestMulti(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		const size = backup.LargePlate
+		// The screen picks the smallest plate that fits.
+		size := r.app.scr.engrave.plate.Size
 		descPlate := backup.Descriptor{
 			Descriptor: oneOfTwo,
 			KeyIdx:     i,
@@ -545,10 +1533,23 @@ type testPlatform struct {
 		closed  chan []mjolnir.Cmd
 		connErr error
 		ioErr   error
+		// ioErrAfter delays ioErr by a number of
+		// reads.
+		ioErrAfter int
+		fault      mjolnir.Fault
+		// misparked makes the engraver report a needle
+		// away from its park position.
+		misparked bool
 	}
 
 	timeOffset time.Duration
 	qrImages   map[*uint8][]byte
+	// settings are the stored settings, if any.
+	settings *settings.Settings
+	// audit is the stored audit log.
+	audit []auditlog.Entry
+	// noAuditKey simulates a device without an audit key.
+	noAuditKey bool
 }
 
 func (t *testPlatform) ScanQR(img *image.Gray) ([][]byte, error) {
@@ -619,6 +1620,56 @@ func ctxButton(ctx *Context, bs ...Button) {
 func (p *testPlatform) Wakeup() {
 }
 
+func (p *testPlatform) LoadSettings() (settings.Settings, error) {
+	if p.settings == nil {
+		return settings.Default(), nil
+	}
+	return *p.settings, nil
+}
+
+func (p *testPlatform) StoreSettings(s settings.Settings) error {
+	p.settings = &s
+	return nil
+}
+
+func (p *testPlatform) AppendAudit(entries []auditlog.Entry) error {
+	if p.noAuditKey {
+		return auditlog.ErrNoKey
+	}
+	p.audit = append(p.audit, entries...)
+	return nil
+}
+
+func (p *testPlatform) LoadAudit() (auditlog.Log, error) {
+	if p.noAuditKey {
+		return auditlog.Log{}, auditlog.ErrNoKey
+	}
+	var l auditlog.Log
+	for _, e := range p.audit {
+		l.Records = append(l.Records, auditlog.Record{Entry: e})
+	}
+	return l, nil
+}
+
+func (p *testPlatform) ExportAudit() error {
+	if p.noAuditKey {
+		return auditlog.ErrNoKey
+	}
+	return nil
+}
+
+func (p *testPlatform) CheckAuditKey() error {
+	if p.noAuditKey {
+		return auditlog.ErrNoKey
+	}
+	return nil
+}
+
+func (p *testPlatform) SetupAuditKey() error {
+	p.noAuditKey = false
+	return nil
+}
+
 func (p *testPlatform) Events() []Event {
 	evts := p.events
 	p.events = nil
@@ -629,10 +1680,15 @@ type wrappedEngraver struct {
 	dev    *mjolnir.Simulator
 	closed chan<- []mjolnir.Cmd
 	ioErr  error
+	reads  int
 }
 
 func (w *wrappedEngraver) Read(p []byte) (int, error) {
 	n, err := w.dev.Read(p)
+	if w.reads > 0 {
+		w.reads--
+		return n, err
+	}
 	if err == nil {
 		err = w.ioErr
 	}
@@ -641,7 +1697,7 @@ func (w *wrappedEngraver) Read(p []byte) (int, error) {
 
 func (w *wrappedEngraver) Write(p []byte) (int, error) {
 	n, err := w.dev.Write(p)
-	if err == nil {
+	if err == nil && w.reads == 0 {
 		err = w.ioErr
 	}
 	return n, err
@@ -654,12 +1710,17 @@ func (w *wrappedEngraver) Close() error {
 	return w.dev.Close()
 }
 
-func (p *testPlatform) Engraver() (io.ReadWriteCloser, error) {
+func (p *testPlatform) Engraver() (driver.Device, error) {
 	if err := p.engrave.connErr; err != nil {
 		return nil, err
 	}
 	sim := mjolnir.NewSimulator()
-	return &wrappedEngraver{sim, p.engrave.closed, p.engrave.ioErr}, nil
+	sim.Fault = p.engrave.fault
+	e := mjolnir.NewEngraver(&wrappedEngraver{sim, p.engrave.closed, p.engrave.ioErr, p.engrave.ioErrAfter})
+	if p.engrave.misparked {
+		return misparkedEngraver{e}, nil
+	}
+	return e, nil
 }
 
 func (p *testPlatform) CameraFrame(dims image.Point) {
@@ -810,23 +1871,20 @@ done:
 			r.Frame(t)
 		}
 	}
-received:
-	for {
-		select {
-		case got := <-r.p.engrave.closed:
-			// Verify the step is advanced after engrave completion.
-			r.Frame(t)
-			if scr.instructions[scr.step].Type == EngraveInstruction {
-				t.Fatalf("instructions didn't progress part engraving screen")
-			}
-			want := simEngrave(t, side)
-			if !reflect.DeepEqual(want, got) {
-				t.Fatalf("engraver commands mismatch for side %v", side)
-			}
-			break received
-		default:
-			r.Frame(t)
+	got := <-r.p.engrave.closed
+	// Verify the step is advanced after engrave completion, which
+	// is reported asynchronously.
+	deadline := time.Now().Add(time.Second)
+	for scr.instructions[scr.step].Type == EngraveInstruction {
+		if time.Now().After(deadline) {
+			t.Fatalf("instructions didn't progress part engraving screen")
 		}
+		time.Sleep(time.Millisecond)
+		r.Frame(t)
+	}
+	want := simEngrave(t, side)
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("engraver commands mismatch for side %v", side)
 	}
 }
 
@@ -835,13 +1893,7 @@ func simEngrave(t *testing.T, plate engrave.Command) []mjolnir.Cmd {
 	defer sim.Close()
 	prog := &mjolnir.Program{}
 	plate.Engrave(prog)
-	prog.Prepare()
-	errs := make(chan error, 1)
-	go func() {
-		errs <- mjolnir.Engrave(sim, prog, nil, nil)
-	}()
-	plate.Engrave(prog)
-	if err := <-errs; err != nil {
+	if err := mjolnir.Engrave(sim, prog, nil, nil); err != nil {
 		t.Fatal(err)
 	}
 	return sim.Cmds
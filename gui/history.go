@@ -0,0 +1,193 @@
+package gui
+
+import (
+	"errors"
+	"image"
+	"log"
+	"strconv"
+
+	"seedhammer.com/auditlog"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+)
+
+// HistoryScreen lists the engraving audit log, most recent entry
+// first, and exports it to the SD card.
+type HistoryScreen struct {
+	log auditlog.Log
+	// unavailable is set if the stored log couldn't be read.
+	unavailable bool
+	// unsigned is set if the audit key is not set up, so the log
+	// can't be stored.
+	unsigned bool
+	// pending are the entries not yet stored.
+	pending []auditlog.Entry
+	result  *ErrorScreen
+	scroll  int
+}
+
+func NewHistoryScreen(ctx *Context) *HistoryScreen {
+	s := new(HistoryScreen)
+	s.load(ctx)
+	return s
+}
+
+// load the stored and pending entries of the log.
+func (s *HistoryScreen) load(ctx *Context) {
+	ctx.FlushAudit()
+	s.pending = append([]auditlog.Entry(nil), ctx.audit...)
+	s.log = auditlog.Log{}
+	s.unavailable = true
+	s.unsigned = errors.Is(ctx.Platform.CheckAuditKey(), auditlog.ErrNoKey)
+	if ctx.NoSDCard || s.unsigned {
+		return
+	}
+	l, err := ctx.Platform.LoadAudit()
+	if err != nil {
+		log.Printf("audit: %v", err)
+		return
+	}
+	s.log = l
+	s.unavailable = false
+}
+
+// export the log to the SD card.
+func (s *HistoryScreen) export(ctx *Context) *ErrorScreen {
+	if err := ctx.Platform.ExportAudit(); err != nil {
+		log.Printf("audit: %v", err)
+		return NewErrorScreen(ctx, err)
+	}
+	return &ErrorScreen{
+		Title: ctx.Text(locale.HistoryExported),
+		Body:  ctx.Text(locale.HistoryExportedMsg),
+	}
+}
+
+func (s *HistoryScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) Result {
+	const linesPerPage = 8
+	const linesPerScroll = linesPerPage - 3
+
+	for {
+		if s.result != nil {
+			if !s.result.Update(ctx) {
+				break
+			}
+			s.result = nil
+			s.load(ctx)
+		}
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if e.Click {
+				return ResultCancelled
+			}
+		case Button3, Center:
+			if e.Click && !s.unavailable {
+				s.result = s.export(ctx)
+			}
+		case Up:
+			if e.Pressed {
+				s.scroll -= linesPerScroll
+			}
+		case Down:
+			if e.Pressed {
+				s.scroll += linesPerScroll
+			}
+		}
+	}
+	op.ColorOp(ops, th.Background)
+
+	r := layout.Rectangle{Max: dims}
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.History))
+
+	btnw := assets.NavBtnPrimary.Bounds().Dx()
+	body := r.Shrink(leadingSize, btnw, 0, btnw)
+	inner := body.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+
+	bodyst := ctx.Styles.body
+	subst := ctx.Styles.subtitle
+	var bodytxt richText
+	switch {
+	case s.unsigned:
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.HistoryUnsigned))
+		bodytxt.Y += infoSpacing
+	case s.unavailable:
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.HistoryUnavailable))
+		bodytxt.Y += infoSpacing
+	}
+	if len(s.log.Key) > 0 {
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistoryKey, "Key", auditlog.Fingerprint(s.log.Key)))
+		bodytxt.Y += infoSpacing
+	}
+	n := len(s.log.Records) + len(s.pending)
+	if n == 0 {
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistoryEmpty))
+	}
+	for i := n - 1; i >= 0; i-- {
+		if i < n-1 {
+			bodytxt.Y += infoSpacing
+		}
+		pending := i >= len(s.log.Records)
+		var e auditlog.Entry
+		if pending {
+			e = s.pending[i-len(s.log.Records)]
+		} else {
+			e = s.log.Records[i].Entry
+		}
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, e.Time.Format("2006-01-02 15:04"))
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistorySide,
+			"Plate", e.Plate, "Side", strconv.Itoa(e.Side+1)))
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistoryShare,
+			"N", strconv.Itoa(e.Share+1), "Total", strconv.Itoa(e.Shares), "Fingerprint", e.MasterFingerprint))
+		if e.Title != "" {
+			bodytxt.Add(ops, bodyst, body.Dx(), th.Text, e.Title)
+		}
+		if e.DescriptorChecksum != "" {
+			bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistoryWallet, "Checksum", e.DescriptorChecksum))
+		}
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, ctx.Text(locale.HistoryDuration,
+			"Actual", formatDuration(ctx, e.Actual), "Estimated", formatDuration(ctx, e.Estimated)))
+		if e.DryRun {
+			bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.HistoryDryRun))
+		}
+		if e.Error != "" {
+			bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.HistoryFailed, "Err", e.Error))
+		}
+		if pending {
+			bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.HistoryPending))
+		}
+	}
+
+	maxScroll := len(bodytxt.Lines) - linesPerPage
+	if s.scroll > maxScroll {
+		s.scroll = maxScroll
+	}
+	if s.scroll < 0 {
+		s.scroll = 0
+	}
+	if len(bodytxt.Lines) > 0 {
+		off := bodytxt.Lines[s.scroll].Y - bodytxt.Lines[0].Y
+		ops.Begin()
+		for _, l := range bodytxt.Lines {
+			op.Position(ops, l.W, inner.Min.Sub(image.Pt(0, off)))
+		}
+		fadeClip(ops, ops.End(), image.Rectangle(body))
+	}
+
+	if s.result != nil {
+		s.result.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+		return ResultNone
+	}
+	nav := []NavButton{{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack}}
+	if !s.unavailable {
+		nav = append(nav, NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconCheckmark})
+	}
+	layoutNavigation(ctx, ops, th, dims, nav...)
+	return ResultNone
+}
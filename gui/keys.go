@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"seedhammer.com/bc/urtypes"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+)
+
+// KeysScreen lists the keys of a descriptor with their master
+// fingerprint, derivation path and extended key, and flags
+// keys that deviate from the usual.
+type KeysScreen struct {
+	desc urtypes.OutputDescriptor
+	// keyIdx is the index of the key matching the seed, or -1.
+	keyIdx int
+	scroll int
+}
+
+func NewKeysScreen(desc urtypes.OutputDescriptor, keyIdx int) *KeysScreen {
+	return &KeysScreen{desc: desc, keyIdx: keyIdx}
+}
+
+// standardPath returns the standard derivation path of the keys of
+// script on network, or nil if the script has none.
+func standardPath(script urtypes.Script, network *chaincfg.Params) urtypes.Path {
+	if script == urtypes.UnknownScript {
+		return nil
+	}
+	p := append(urtypes.Path(nil), script.DerivationPath()...)
+	if network != &chaincfg.MainNetParams && len(p) > 1 {
+		// Testnet coin type.
+		p[1] = hdkeychain.HardenedKeyStart + 1
+	}
+	return p
+}
+
+// unusualPath reports whether the derivation path of k differs from
+// the standard path for script.
+func unusualPath(script urtypes.Script, k urtypes.KeyDescriptor) bool {
+	std := standardPath(script, k.Network)
+	if len(std) != len(k.DerivationPath) {
+		return true
+	}
+	for i, c := range std {
+		if k.DerivationPath[i] != c {
+			return true
+		}
+	}
+	return false
+}
+
+// mixedNetworks reports whether the keys of desc are for more than
+// one network.
+func mixedNetworks(desc urtypes.OutputDescriptor) bool {
+	for _, k := range desc.Keys {
+		if k.Network != desc.Keys[0].Network {
+			return true
+		}
+	}
+	return false
+}
+
+// keyPath formats the derivation path of k, including the
+// children of the key.
+func keyPath(k urtypes.KeyDescriptor) string {
+	var b strings.Builder
+	b.WriteString(k.DerivationPath.String())
+	for _, c := range k.Children {
+		b.WriteByte('/')
+		switch c.Type {
+		case urtypes.ChildDerivation:
+			b.WriteString(strconv.Itoa(int(c.Index)))
+		case urtypes.RangeDerivation:
+			fmt.Fprintf(&b, "<%d;%d>", c.Index, c.End)
+		case urtypes.WildcardDerivation:
+			b.WriteByte('*')
+		}
+		if c.Hardened {
+			b.WriteByte('h')
+		}
+	}
+	return b.String()
+}
+
+func (s *KeysScreen) Layout(ctx *Context, ops op.Ctx, dims image.Point) bool {
+	const linesPerPage = 8
+	const linesPerScroll = linesPerPage - 3
+
+	for {
+		e, ok := ctx.Next(Button1, Up, Down)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if e.Click {
+				return true
+			}
+		case Up:
+			if e.Pressed {
+				s.scroll -= linesPerScroll
+			}
+		case Down:
+			if e.Pressed {
+				s.scroll += linesPerScroll
+			}
+		}
+	}
+	th := &descriptorTheme
+	op.ColorOp(ops, th.Background)
+
+	r := layout.Rectangle{Max: dims}
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.Keys))
+
+	btnw := assets.NavBtnPrimary.Bounds().Dx()
+	body := r.Shrink(leadingSize, btnw, 0, btnw)
+	inner := body.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+
+	bodyst := ctx.Styles.body
+	subst := ctx.Styles.subtitle
+	var bodytxt richText
+	if mixedNetworks(s.desc) {
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.MixedNetworks))
+		bodytxt.Y += infoSpacing
+	}
+	for i, k := range s.desc.Keys {
+		if i > 0 {
+			bodytxt.Y += infoSpacing
+		}
+		n := strconv.Itoa(i + 1)
+		heading := ctx.Text(locale.KeyN, "N", n)
+		if i == s.keyIdx {
+			heading = ctx.Text(locale.KeyNSeed, "N", n)
+		}
+		bodytxt.Add(ops, subst, body.Dx(), th.Text, heading)
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, fmt.Sprintf("%.8x", k.MasterFingerprint))
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, keyPath(k))
+		const xpubLen = 12
+		bodytxt.Add(ops, bodyst, body.Dx(), th.Text, shortenAddress(xpubLen, k.String()))
+		if unusualPath(s.desc.Script, k) {
+			bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.UnusualPath))
+		}
+		if k.Network != &chaincfg.MainNetParams {
+			bodytxt.Add(ops, subst, body.Dx(), th.Text, ctx.Text(locale.TestnetKey))
+		}
+	}
+
+	maxScroll := len(bodytxt.Lines) - linesPerPage
+	if s.scroll > maxScroll {
+		s.scroll = maxScroll
+	}
+	if s.scroll < 0 {
+		s.scroll = 0
+	}
+	if len(bodytxt.Lines) > 0 {
+		off := bodytxt.Lines[s.scroll].Y - bodytxt.Lines[0].Y
+		ops.Begin()
+		for _, l := range bodytxt.Lines {
+			op.Position(ops, l.W, inner.Min.Sub(image.Pt(0, off)))
+		}
+		fadeClip(ops, ops.End(), image.Rectangle(body))
+	}
+
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+	)
+	return false
+}
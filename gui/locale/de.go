@@ -0,0 +1,225 @@
+package locale
+
+var de = Catalog{
+	LanguageName: "Deutsch",
+	Error:        "Fehler",
+
+	BackupWallet:    "Wallet sichern",
+	RecoverWallet:   "Wallet wiederherstellen",
+	GenerateSeed:    "Seed erzeugen",
+	MultisigSession: "Multisig-Sitzung",
+	Settings:        "Einstellungen",
+	History:         "Verlauf",
+	RemoveSDCard:    "SD-Karte entfernen",
+	RemoveSDCardMsg: "Entferne die SD-Karte, um fortzufahren.\n\nTaste halten, um diese Warnung zu ignorieren.",
+
+	Descriptor:       "Deskriptor",
+	InputMethod:      "Eingabemethode wählen",
+	NumWords:         "Anzahl der Wörter wählen",
+	ChoiceScan:       "SCANNEN",
+	ChoiceSkip:       "ÜBERSPRINGEN",
+	ChoiceKeyboard:   "TASTATUR",
+	ChoiceCamera:     "KAMERA",
+	ChoiceLastWord:   "LETZTES WORT",
+	Choice12Words:    "12 WÖRTER",
+	Choice24Words:    "24 WÖRTER",
+	ChoiceAddresses:  "ADRESSEN",
+	ChoiceKeys:       "SCHLÜSSEL",
+	ChoiceEngrave:    "GRAVIEREN",
+	ChoiceResume:     "FORTSETZEN",
+	ChoiceRestart:    "NEU STARTEN",
+	ChoiceDiceWords:  "WÜRFELWORTE",
+	ChoiceDiceHash:   "WÜRFELHASH",
+	ChoiceCoinFlips:  "MÜNZWÜRFE",
+	ChoiceReuse:      "ÜBERNEHMEN",
+	Scan:             "Scannen",
+	OutputDescriptor: "Wallet-Output-Deskriptor",
+	SeedQR:           "SeedQR oder Mnemonic",
+
+	TooLarge:              "Zu groß",
+	DescriptorTooLarge:    "Der Deskriptor passt auf keine Plattengröße.",
+	QRTooLarge:            "Der QR-Code enthält mehr Daten, als dieses Gerät unterstützt.",
+	InvalidQR:             "Ungültiger QR-Code",
+	InvalidQRMsg:          "Der QR-Code ist beschädigt oder kein gültiger animierter QR-Code.",
+	IncompatibleQR:        "Der QR-Code passt nicht zu den zuvor gescannten QR-Codes.",
+	DuplicatedShare:       "Doppelter Anteil",
+	DuplicatedShareMsg:    "Der Anteil {{.Fingerprint}} ist mehrfach in der Wallet enthalten.",
+	NotDescriptor:         "Die gescannten Daten sind weder ein Wallet-Output-Deskriptor noch ein XPUB-Schlüssel.",
+	UnsupportedDescriptor: "Der Deskriptor wird nicht unterstützt.",
+	UnknownWallet:         "Unbekannte Wallet",
+	UnknownWalletMsg:      "Die Wallet passt nicht zum Seed oder ist mit einer Passphrase geschützt.",
+	UnknownWalletConfirm:  "Die Wallet passt nicht zum Seed.\n\nFalls sie mit einer Passphrase geschützt ist, zum Bestätigen lange drücken.",
+	RecoveryFailed:        "Die gescannten QR-Codes ergeben keine unterstützte Wallet.",
+
+	ConfirmWallet: "Wallet bestätigen",
+	WalletTitle:   "Titel",
+	WalletType:    "Typ",
+	WalletScript:  "Skript",
+	Singlesig:     "Singlesig",
+	Multisig:      "{{.Threshold}}-von-{{.Keys}}-Multisig",
+	Testnet:       "{{.Type}} (Testnet)",
+	Receive:       "Empfangen",
+	Change:        "Wechselgeld",
+	WalletDetails: "Wallet-Details",
+	DetailsLead:   "Anzeige auswählen",
+	Keys:          "Schlüssel",
+	KeyN:          "Schlüssel {{.N}}",
+	KeyNSeed:      "Schlüssel {{.N}}, dieser Seed",
+	UnusualPath:   "Unüblicher Pfad",
+	TestnetKey:    "Testnet-Schlüssel",
+	MixedNetworks: "Schlüssel mischen Netzwerke",
+
+	VerifyAddress:      "Adresse prüfen",
+	BitcoinAddress:     "Bitcoin-Adresse",
+	InputAddress:       "Adresse eingeben",
+	SearchingAddresses: "Adressen werden gesucht\n{{.N}} von {{.Total}}",
+	InvalidAddress:     "Ungültige Adresse",
+	InvalidAddressMsg:  "Die Daten sind keine gültige Adresse für das Netzwerk dieser Wallet.",
+	AddressFound:       "Adresse gefunden",
+	ReceiveAddressMsg:  "Die Adresse gehört zu dieser Wallet.\n\nEs ist die Empfangsadresse mit Index {{.Index}}.",
+	ChangeAddressMsg:   "Die Adresse gehört zu dieser Wallet.\n\nEs ist die Wechselgeldadresse mit Index {{.Index}}.",
+	AddressNotFound:    "Nicht in Wallet",
+	AddressNotFoundMsg: "Die Adresse gehört nicht zu dieser Wallet.\n\nKeine der ersten {{.Gap}} Empfangs- und Wechselgeldadressen stimmt überein.",
+
+	Recover:         "Wiederherstellen",
+	ScanPlateQRs:    "Platten-QR-Codes scannen",
+	QRsScanned:      "{{.N}} QR-Codes gescannt",
+	Recovered:       "Wiederhergestellt",
+	WalletRecovered: "Wallet wiederhergestellt",
+	SharesOf:        "Anteile {{.Shares}} von {{.N}}",
+
+	InputSeed:       "Seed eingeben",
+	ConfirmSeed:     "Seed bestätigen",
+	InputWords:      "Wörter eingeben",
+	LastWord:        "Letztes Wort",
+	Candidate:       "Kandidat {{.N}} von {{.Total}}",
+	InvalidSeed:     "Ungültiger Seed",
+	ElectrumSeed:    "Electrum-Seeds werden nicht unterstützt.",
+	NotSeed:         "Die gescannten Daten sind kein Seed.",
+	InvalidMnemonic: "Die Seed-Phrase ist ungültig.\n\nPrüfe die Wörter und versuche es erneut.",
+	DiscardSeed:     "Seed verwerfen?",
+	DiscardSeedMsg:  "Zurückgehen verwirft den Seed.\n\nZum Bestätigen Taste halten.",
+	InputFragment:   "Fragment eingeben",
+	InvalidFragment: "Ungültiges Fragment",
+	InvalidFragMsg:  "Das Fragment passt nicht zu den vorherigen Fragmenten.",
+	InvalidFrags:    "Ungültige Fragmente",
+	InvalidFragsMsg: "Die Fragmente ergeben keine gültigen Daten.\n\nPrüfe die Fragmente und versuche es erneut.",
+	InvalidData:     "Ungültige Daten",
+	InvalidDataMsg:  "Die Fragmente stellen keine gültigen Daten dar.\n\nFehlerdetails: {{.Err}}",
+	DiscardFrags:    "Fragmente verwerfen?",
+	DiscardFragsMsg: "Zurückgehen verwirft die eingegebenen Fragmente.\n\nZum Bestätigen Taste halten.",
+
+	WordOf:          "Wort {{.N}}/{{.Total}}",
+	Dice:            "Würfel",
+	CoinFlips:       "Münzwürfe",
+	RollOf:          "Wurf {{.N}} von {{.Total}}",
+	FlipOf:          "Münzwurf {{.N}} von {{.Total}}",
+	InvalidRoll:     "Ungültiger Wurf",
+	InvalidRollMsg:  "Der Wurf entspricht keinem Wort.\n\nWürfle erneut.",
+	CameraNoise:     "Kamerarauschen",
+	MixCameraNoise:  "Kamerarauschen beimischen?",
+	CollectingNoise: "Kamerarauschen wird gesammelt",
+	CameraError:     "Kamerafehler: {{.Err}}",
+	EntropySource:   "Entropiequelle wählen",
+
+	EngravePlate:        "Platte gravieren",
+	EngravingLeft:       "Platte wird graviert, noch {{.Left}}",
+	About:               "Etwa {{.Duration}}.",
+	AboutTotal:          "Etwa {{.Duration}} ({{.Total}} insgesamt).",
+	Minutes:             "{{.M}} Min.",
+	HoursMinutes:        "{{.H}} Std. {{.M}} Min.",
+	CheckFingerprint:    "Stelle sicher, dass der Fingerabdruck oben zum gewünschten Anteil gehört.",
+	DisconnectEngraver:  "Schalte den Graveur aus und trenne ihn von diesem Gerät.",
+	MoveHammerhead:      "Bewege den Hammerkopf von Hand ganz nach oben links.",
+	PlacePlate:          "Lege eine {{.Name}} auf\neine Schutzplatte.",
+	TightenNuts:         "Ziehe die Muttern fest an.",
+	AdjustNeedle:        "Löse die Rändelschraube am Hammerkopf. Stelle die Nadel auf ~1,5 mm über der Platte ein.",
+	TightenScrew:        "Ziehe die Rändelschraube am Hammerkopf fest und stelle den Tiefenwähler auf \"Strong\".",
+	ConnectEngraver:     "Schalte die Graviermaschine ein und verbinde dieses Gerät über den mittleren Anschluss.",
+	StartEngraving:      "Taste halten, um das Gravieren zu starten. Der Vorgang ist laut, trage einen Gehörschutz.",
+	FlipPlate:           "Schraube die 4 Muttern ab und drehe die obere Metallplatte horizontal um.",
+	EngravingComplete:   "Gravur erfolgreich abgeschlossen.",
+	VerifyHint:          "Drücke die mittlere Taste, um die Platte per Scan zu prüfen.",
+	ConnectionError:     "Verbindungsfehler",
+	ConnectionErrorMsg:  "Stelle sicher, dass der Graveur eingeschaltet und mit dem mittleren Anschluss dieses Geräts verbunden ist.\n\nFehlerdetails: {{.Err}}",
+	ConnectionLostMsg:   "Schalte den Graveur aus und trenne dieses Gerät. Warte 10 Sekunden, schalte den Graveur dann ein und verbinde ihn erneut.\n\nFehlerdetails: {{.Err}}",
+	EngravingCancelled:  "Gravur abgebrochen",
+	EngravingCancelMsg:  "Der Graveur hat die Gravur abgebrochen.",
+	NeedleNotParked:     "Nadel nicht geparkt",
+	NeedleNotParkedMsg:  "Die Gravur ist abgeschlossen, aber die Nadel steht nicht in ihrer Ruheposition. Prüfe Platte und Nadel, bevor du fortfährst.\n\nFehlerdetails: {{.Err}}",
+	ResumeEngraving:     "Fortsetzen?",
+	ResumeEngravingLead: "Unterbrochene Gravur fortsetzen",
+	CancelEngraving:     "Abbrechen?",
+	CancelEngravingMsg:  "Dies bricht den Graviervorgang ab.\n\nZum Bestätigen Taste halten.",
+
+	Preview:      "Vorschau",
+	PreviewSide:  "Seite {{.N}} von {{.Total}}, {{.Zoom}}x",
+	PreviewOrder: "Seite {{.N}} von {{.Total}}, {{.Zoom}}x, Reihenfolge",
+
+	Verify:                "Prüfen",
+	EngravedQR:            "Gravierter QR-Code",
+	EngravedQROf:          "Gravierter QR-Code {{.N}} von {{.Total}}",
+	VerificationFailed:    "Prüfung fehlgeschlagen",
+	VerificationFailedMsg: "Der gescannte QR-Code entspricht nicht dem beabsichtigten Inhalt.\n\nScanne die gerade gravierte Platte. Andernfalls verwende die Platte nicht als Backup.",
+	PlateVerified:         "Platte geprüft",
+	PlateVerifiedMsg:      "Die gravierten QR-Codes entsprechen dem beabsichtigten Inhalt.",
+	UnreadableQR:          "Unlesbarer QR-Code",
+	UnreadableQRMsg:       "Auf der Platte konnte kein QR-Code gelesen werden.\n\nIst die Gravur zu flach, fehlt den Modulen der Kontrast: senke die Nadel und graviere eine neue Platte. Sind die Module verschmiert, ziehe die Muttern und die Rändelschraube am Hammerkopf fest, bevor du eine neue Platte gravierst.\n\nSonst scanne erneut bei gleichmäßigem Licht.",
+
+	Session:          "Sitzung",
+	NotMultisig:      "Kein Multisig",
+	NotMultisigMsg:   "Der Deskriptor hat nur einen Anteil. Verwende stattdessen Wallet sichern.",
+	ShareEngraved:    "Anteil bereits graviert",
+	ShareEngravedMsg: "Der Anteil {{.Fingerprint}} wurde in dieser Sitzung bereits graviert.\n\nGib den Seed eines verbleibenden Anteils ein.",
+	EndSession:       "Sitzung beenden?",
+	EndSessionMsg:    "{{.N}} Anteile sind nicht graviert.\n\nZum Bestätigen Taste halten.",
+	SharesEngraved:   "{{.N}} von {{.Total}} Anteilen graviert",
+	ShareVerified:    "{{.Plate}}, geprüft",
+	ShareUnverified:  "{{.Plate}}, ungeprüft",
+	NotEngraved:      "Nicht graviert",
+
+	HistoryEmpty:       "Keine Gravuren aufgezeichnet.",
+	HistoryUnavailable: "SD-Karte einlegen, um das gespeicherte Protokoll zu lesen.",
+	HistoryUnsigned:    "Das Protokoll ist nicht signiert. Richte den Prüfschlüssel in den Einstellungen ein, um es zu speichern.",
+	HistoryKey:         "Gerät {{.Key}}",
+	HistorySide:        "{{.Plate}}, Seite {{.Side}}",
+	HistoryShare:       "Anteil {{.N}}/{{.Total}}, {{.Fingerprint}}",
+	HistoryWallet:      "Wallet {{.Checksum}}",
+	HistoryDuration:    "{{.Actual}} (ca. {{.Estimated}})",
+	HistoryFailed:      "Fehler: {{.Err}}",
+	HistoryDryRun:      "Testlauf",
+	HistoryPending:     "Noch nicht gespeichert",
+	HistoryExported:    "Protokoll exportiert",
+	HistoryExportedMsg: "Das signierte Protokoll liegt in audit.json auf der SD-Karte.",
+
+	SettingPlate:        "Platte",
+	SettingMove:         "Fahren",
+	SettingPrint:        "Gravur",
+	SettingDryRun:       "Testlauf",
+	SettingCamera:       "Kamera",
+	SettingGuide:        "Anleitung",
+	SettingLanguage:     "Sprache",
+	SettingGap:          "Tiefe",
+	HelpPlate:           "Kleinste Plattengröße",
+	HelpMove:            "Tempo beim Fahren",
+	HelpPrint:           "Tempo beim Gravieren",
+	HelpDryRun:          "Standardmäßig Testlauf",
+	HelpCamera:          "Ausrichtung der Kamera",
+	HelpGuide:           "Anleitung für das erste Mal",
+	HelpLanguage:        "Sprache der Oberfläche",
+	HelpGap:             "Tiefe der Adresssuche",
+	SettingAudit:        "Prüfschlüssel",
+	HelpAudit:           "Signiert den Verlauf",
+	AuditKeyReady:       "Eingerichtet",
+	AuditKeyMissing:     "Nicht eingerichtet",
+	AuditKeyUnavailable: "Nicht verfügbar",
+	SetupAuditKey:       "Prüfschlüssel einrichten?",
+	SetupAuditKeyMsg:    "Der Schlüssel wird im Einmalspeicher dieses Geräts abgelegt. Das ist dauerhaft und kann nicht rückgängig gemacht werden.\n\nZum Bestätigen Taste halten.",
+	AuditKeySetUp:       "Prüfschlüssel eingerichtet",
+	AuditKeySetUpMsg:    "Der Gravurverlauf wird jetzt signiert und gespeichert.",
+	On:                  "Ein",
+	Off:                 "Aus",
+	DefaultSpeed:        "Standard",
+	CameraNormal:        "Normal",
+	CameraRotated:       "Gedreht",
+}
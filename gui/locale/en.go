@@ -0,0 +1,464 @@
+package locale
+
+// Message IDs.
+const (
+	LanguageName ID = "language-name"
+	Error        ID = "error"
+
+	// Main screen.
+	BackupWallet    ID = "main.backup-wallet"
+	RecoverWallet   ID = "main.recover-wallet"
+	GenerateSeed    ID = "main.generate-seed"
+	MultisigSession ID = "main.multisig-session"
+	Settings        ID = "main.settings"
+	History         ID = "main.history"
+	RemoveSDCard    ID = "main.remove-sd-card"
+	RemoveSDCardMsg ID = "main.remove-sd-card.body"
+
+	// Input choices.
+	Descriptor       ID = "choice.descriptor"
+	InputMethod      ID = "choice.input-method"
+	NumWords         ID = "choice.num-words"
+	ChoiceScan       ID = "choice.scan"
+	ChoiceSkip       ID = "choice.skip"
+	ChoiceKeyboard   ID = "choice.keyboard"
+	ChoiceCamera     ID = "choice.camera"
+	ChoiceLastWord   ID = "choice.last-word"
+	Choice12Words    ID = "choice.12-words"
+	Choice24Words    ID = "choice.24-words"
+	ChoiceAddresses  ID = "choice.addresses"
+	ChoiceKeys       ID = "choice.keys"
+	ChoiceEngrave    ID = "choice.engrave"
+	ChoiceResume     ID = "choice.resume"
+	ChoiceRestart    ID = "choice.restart"
+	ChoiceDiceWords  ID = "choice.dice-words"
+	ChoiceDiceHash   ID = "choice.dice-hash"
+	ChoiceCoinFlips  ID = "choice.coin-flips"
+	ChoiceReuse      ID = "choice.reuse"
+	Scan             ID = "scan"
+	OutputDescriptor ID = "scan.output-descriptor"
+	SeedQR           ID = "scan.seedqr"
+
+	// Errors.
+	TooLarge              ID = "error.too-large"
+	DescriptorTooLarge    ID = "error.descriptor-too-large"
+	QRTooLarge            ID = "error.qr-too-large"
+	InvalidQR             ID = "error.invalid-qr"
+	InvalidQRMsg          ID = "error.invalid-qr.body"
+	IncompatibleQR        ID = "error.incompatible-qr"
+	DuplicatedShare       ID = "error.duplicated-share"
+	DuplicatedShareMsg    ID = "error.duplicated-share.body"
+	NotDescriptor         ID = "error.not-descriptor"
+	UnsupportedDescriptor ID = "error.unsupported-descriptor"
+	UnknownWallet         ID = "error.unknown-wallet"
+	UnknownWalletMsg      ID = "error.unknown-wallet.body"
+	UnknownWalletConfirm  ID = "error.unknown-wallet.confirm"
+	RecoveryFailed        ID = "error.recovery-failed"
+
+	// Wallet confirmation and addresses.
+	ConfirmWallet ID = "wallet.confirm"
+	WalletTitle   ID = "wallet.title"
+	WalletType    ID = "wallet.type"
+	WalletScript  ID = "wallet.script"
+	Singlesig     ID = "wallet.singlesig"
+	Multisig      ID = "wallet.multisig"
+	Testnet       ID = "wallet.testnet"
+	Receive       ID = "wallet.receive"
+	Change        ID = "wallet.change"
+	WalletDetails ID = "wallet.details"
+	DetailsLead   ID = "wallet.details.lead"
+	Keys          ID = "wallet.keys"
+	KeyN          ID = "wallet.key"
+	KeyNSeed      ID = "wallet.key-seed"
+	UnusualPath   ID = "wallet.unusual-path"
+	TestnetKey    ID = "wallet.testnet-key"
+	MixedNetworks ID = "wallet.mixed-networks"
+
+	// Address verification.
+	VerifyAddress      ID = "address.verify"
+	BitcoinAddress     ID = "address.bitcoin"
+	InputAddress       ID = "address.input"
+	SearchingAddresses ID = "address.searching"
+	InvalidAddress     ID = "address.invalid"
+	InvalidAddressMsg  ID = "address.invalid.body"
+	AddressFound       ID = "address.found"
+	ReceiveAddressMsg  ID = "address.receive.body"
+	ChangeAddressMsg   ID = "address.change.body"
+	AddressNotFound    ID = "address.not-found"
+	AddressNotFoundMsg ID = "address.not-found.body"
+
+	// Recovery.
+	Recover         ID = "recover"
+	ScanPlateQRs    ID = "recover.scan"
+	QRsScanned      ID = "recover.scanned"
+	Recovered       ID = "recover.recovered"
+	WalletRecovered ID = "recover.wallet-recovered"
+	SharesOf        ID = "recover.shares"
+
+	// Seed input.
+	InputSeed       ID = "seed.input"
+	ConfirmSeed     ID = "seed.confirm"
+	InputWords      ID = "seed.input-words"
+	LastWord        ID = "seed.last-word"
+	Candidate       ID = "seed.candidate"
+	InvalidSeed     ID = "seed.invalid"
+	ElectrumSeed    ID = "seed.electrum"
+	NotSeed         ID = "seed.not-seed"
+	InvalidMnemonic ID = "seed.invalid-mnemonic"
+	DiscardSeed     ID = "seed.discard"
+	DiscardSeedMsg  ID = "seed.discard.body"
+	InputFragment   ID = "fragment.input"
+	InvalidFragment ID = "fragment.invalid"
+	InvalidFragMsg  ID = "fragment.invalid.body"
+	InvalidFrags    ID = "fragment.invalid-set"
+	InvalidFragsMsg ID = "fragment.invalid-set.body"
+	InvalidData     ID = "fragment.invalid-data"
+	InvalidDataMsg  ID = "fragment.invalid-data.body"
+	DiscardFrags    ID = "fragment.discard"
+	DiscardFragsMsg ID = "fragment.discard.body"
+
+	// Seed generation.
+	WordOf          ID = "generate.word"
+	Dice            ID = "generate.dice"
+	CoinFlips       ID = "generate.coin-flips"
+	RollOf          ID = "generate.roll"
+	FlipOf          ID = "generate.flip"
+	InvalidRoll     ID = "generate.invalid-roll"
+	InvalidRollMsg  ID = "generate.invalid-roll.body"
+	CameraNoise     ID = "generate.camera-noise"
+	MixCameraNoise  ID = "generate.mix-camera-noise"
+	CollectingNoise ID = "generate.collecting-noise"
+	CameraError     ID = "generate.camera-error"
+	EntropySource   ID = "generate.entropy-source"
+
+	// Engraving.
+	EngravePlate        ID = "engrave.title"
+	EngravingLeft       ID = "engrave.left"
+	About               ID = "engrave.about"
+	AboutTotal          ID = "engrave.about-total"
+	Minutes             ID = "engrave.minutes"
+	HoursMinutes        ID = "engrave.hours-minutes"
+	CheckFingerprint    ID = "engrave.check-fingerprint"
+	DisconnectEngraver  ID = "engrave.disconnect"
+	MoveHammerhead      ID = "engrave.move-hammerhead"
+	PlacePlate          ID = "engrave.place-plate"
+	TightenNuts         ID = "engrave.tighten-nuts"
+	AdjustNeedle        ID = "engrave.adjust-needle"
+	TightenScrew        ID = "engrave.tighten-screw"
+	ConnectEngraver     ID = "engrave.connect"
+	StartEngraving      ID = "engrave.start"
+	FlipPlate           ID = "engrave.flip-plate"
+	EngravingComplete   ID = "engrave.complete"
+	VerifyHint          ID = "engrave.verify-hint"
+	ConnectionError     ID = "engrave.connection-error"
+	ConnectionErrorMsg  ID = "engrave.connection-error.body"
+	ConnectionLostMsg   ID = "engrave.connection-lost.body"
+	EngravingCancelled  ID = "engrave.cancelled"
+	EngravingCancelMsg  ID = "engrave.cancelled.body"
+	NeedleNotParked     ID = "engrave.not-parked"
+	NeedleNotParkedMsg  ID = "engrave.not-parked.body"
+	ResumeEngraving     ID = "engrave.resume"
+	ResumeEngravingLead ID = "engrave.resume.lead"
+	CancelEngraving     ID = "engrave.cancel"
+	CancelEngravingMsg  ID = "engrave.cancel.body"
+
+	// Plate preview.
+	Preview      ID = "preview"
+	PreviewSide  ID = "preview.side"
+	PreviewOrder ID = "preview.order"
+
+	// Plate verification.
+	Verify                ID = "verify"
+	EngravedQR            ID = "verify.qr"
+	EngravedQROf          ID = "verify.qr-of"
+	VerificationFailed    ID = "verify.failed"
+	VerificationFailedMsg ID = "verify.failed.body"
+	PlateVerified         ID = "verify.passed"
+	PlateVerifiedMsg      ID = "verify.passed.body"
+	UnreadableQR          ID = "verify.unreadable"
+	UnreadableQRMsg       ID = "verify.unreadable.body"
+
+	// Multisig session.
+	Session          ID = "session"
+	NotMultisig      ID = "session.not-multisig"
+	NotMultisigMsg   ID = "session.not-multisig.body"
+	ShareEngraved    ID = "session.share-engraved"
+	ShareEngravedMsg ID = "session.share-engraved.body"
+	EndSession       ID = "session.end"
+	EndSessionMsg    ID = "session.end.body"
+	SharesEngraved   ID = "session.shares-engraved"
+	ShareVerified    ID = "session.share-verified"
+	ShareUnverified  ID = "session.share-unverified"
+	NotEngraved      ID = "session.not-engraved"
+
+	// Engraving history.
+	HistoryEmpty       ID = "history.empty"
+	HistoryUnavailable ID = "history.unavailable"
+	HistoryUnsigned    ID = "history.unsigned"
+	HistoryKey         ID = "history.key"
+	HistorySide        ID = "history.side"
+	HistoryShare       ID = "history.share"
+	HistoryWallet      ID = "history.wallet"
+	HistoryDuration    ID = "history.duration"
+	HistoryFailed      ID = "history.failed"
+	HistoryDryRun      ID = "history.dry-run"
+	HistoryPending     ID = "history.pending"
+	HistoryExported    ID = "history.exported"
+	HistoryExportedMsg ID = "history.exported.body"
+
+	// Settings.
+	SettingPlate        ID = "settings.plate"
+	SettingMove         ID = "settings.move"
+	SettingPrint        ID = "settings.print"
+	SettingDryRun       ID = "settings.dry-run"
+	SettingCamera       ID = "settings.camera"
+	SettingGuide        ID = "settings.guide"
+	SettingLanguage     ID = "settings.language"
+	SettingGap          ID = "settings.gap"
+	HelpPlate           ID = "settings.plate.help"
+	HelpMove            ID = "settings.move.help"
+	HelpPrint           ID = "settings.print.help"
+	HelpDryRun          ID = "settings.dry-run.help"
+	HelpCamera          ID = "settings.camera.help"
+	HelpGuide           ID = "settings.guide.help"
+	HelpLanguage        ID = "settings.language.help"
+	HelpGap             ID = "settings.gap.help"
+	SettingAudit        ID = "settings.audit"
+	HelpAudit           ID = "settings.audit.help"
+	AuditKeyReady       ID = "settings.audit-ready"
+	AuditKeyMissing     ID = "settings.audit-missing"
+	AuditKeyUnavailable ID = "settings.audit-unavailable"
+	SetupAuditKey       ID = "settings.setup-audit"
+	SetupAuditKeyMsg    ID = "settings.setup-audit.body"
+	AuditKeySetUp       ID = "settings.audit-set-up"
+	AuditKeySetUpMsg    ID = "settings.audit-set-up.body"
+	On                  ID = "settings.on"
+	Off                 ID = "settings.off"
+	DefaultSpeed        ID = "settings.default-speed"
+	CameraNormal        ID = "settings.camera-normal"
+	CameraRotated       ID = "settings.camera-rotated"
+)
+
+var en = Catalog{
+	LanguageName: "English",
+	Error:        "Error",
+
+	BackupWallet:    "Backup Wallet",
+	RecoverWallet:   "Recover Wallet",
+	GenerateSeed:    "Generate Seed",
+	MultisigSession: "Multisig Session",
+	Settings:        "Settings",
+	History:         "History",
+	RemoveSDCard:    "Remove SD card",
+	RemoveSDCardMsg: "Remove SD card to continue.\n\nHold button to ignore this warning.",
+
+	Descriptor:       "Descriptor",
+	InputMethod:      "Choose input method",
+	NumWords:         "Choose number of words",
+	ChoiceScan:       "SCAN",
+	ChoiceSkip:       "SKIP",
+	ChoiceKeyboard:   "KEYBOARD",
+	ChoiceCamera:     "CAMERA",
+	ChoiceLastWord:   "LAST WORD",
+	Choice12Words:    "12 WORDS",
+	Choice24Words:    "24 WORDS",
+	ChoiceAddresses:  "ADDRESSES",
+	ChoiceKeys:       "KEYS",
+	ChoiceEngrave:    "ENGRAVE",
+	ChoiceResume:     "RESUME",
+	ChoiceRestart:    "RESTART",
+	ChoiceDiceWords:  "DICE WORDS",
+	ChoiceDiceHash:   "DICE HASH",
+	ChoiceCoinFlips:  "COIN FLIPS",
+	ChoiceReuse:      "RE-USE",
+	Scan:             "Scan",
+	OutputDescriptor: "Wallet Output Descriptor",
+	SeedQR:           "SeedQR or Mnemonic",
+
+	TooLarge:              "Too Large",
+	DescriptorTooLarge:    "The descriptor cannot fit any plate size.",
+	QRTooLarge:            "The QR code contains more data than this device supports.",
+	InvalidQR:             "Invalid QR Code",
+	InvalidQRMsg:          "The QR code is damaged or not a valid animated QR code.",
+	IncompatibleQR:        "The QR code doesn't match the previously scanned QR codes.",
+	DuplicatedShare:       "Duplicated Share",
+	DuplicatedShareMsg:    "The share {{.Fingerprint}} is listed more than once in the wallet.",
+	NotDescriptor:         "The scanned data does not represent a wallet output descriptor or XPUB key.",
+	UnsupportedDescriptor: "The descriptor is not supported.",
+	UnknownWallet:         "Unknown Wallet",
+	UnknownWalletMsg:      "The wallet does not match the seed or is passphrase protected.",
+	UnknownWalletConfirm:  "The wallet does not match the seed.\n\nIf it is passphrase protected, long press to confirm.",
+	RecoveryFailed:        "The scanned QR codes don't combine into a supported wallet.",
+
+	ConfirmWallet: "Confirm Wallet",
+	WalletTitle:   "Title",
+	WalletType:    "Type",
+	WalletScript:  "Script",
+	Singlesig:     "Singlesig",
+	Multisig:      "{{.Threshold}}-of-{{.Keys}} multisig",
+	Testnet:       "{{.Type}} (testnet)",
+	Receive:       "Receive",
+	Change:        "Change",
+	WalletDetails: "Wallet Details",
+	DetailsLead:   "Choose what to show",
+	Keys:          "Keys",
+	KeyN:          "Key {{.N}}",
+	KeyNSeed:      "Key {{.N}}, this seed",
+	UnusualPath:   "Unusual path",
+	TestnetKey:    "Testnet key",
+	MixedNetworks: "Keys mix networks",
+
+	VerifyAddress:      "Verify Address",
+	BitcoinAddress:     "Bitcoin Address",
+	InputAddress:       "Input Address",
+	SearchingAddresses: "Searching addresses\n{{.N}} of {{.Total}}",
+	InvalidAddress:     "Invalid Address",
+	InvalidAddressMsg:  "The data is not a valid address for the network of this wallet.",
+	AddressFound:       "Address Found",
+	ReceiveAddressMsg:  "The address belongs to this wallet.\n\nIt is the receive address with index {{.Index}}.",
+	ChangeAddressMsg:   "The address belongs to this wallet.\n\nIt is the change address with index {{.Index}}.",
+	AddressNotFound:    "Not in Wallet",
+	AddressNotFoundMsg: "The address does not belong to this wallet.\n\nNone of the first {{.Gap}} receive and change addresses match.",
+
+	Recover:         "Recover",
+	ScanPlateQRs:    "Scan Plate QR Codes",
+	QRsScanned:      "{{.N}} QR Codes Scanned",
+	Recovered:       "Recovered",
+	WalletRecovered: "Wallet Recovered",
+	SharesOf:        "Shares {{.Shares}} of {{.N}}",
+
+	InputSeed:       "Input Seed",
+	ConfirmSeed:     "Confirm Seed",
+	InputWords:      "Input Words",
+	LastWord:        "Last Word",
+	Candidate:       "Candidate {{.N}} of {{.Total}}",
+	InvalidSeed:     "Invalid Seed",
+	ElectrumSeed:    "Electrum seeds are not supported.",
+	NotSeed:         "The scanned data does not represent a seed.",
+	InvalidMnemonic: "The seed phrase is invalid.\n\nCheck the words and try again.",
+	DiscardSeed:     "Discard Seed?",
+	DiscardSeedMsg:  "Going back will discard the seed.\n\nHold button to confirm.",
+	InputFragment:   "Input Fragment",
+	InvalidFragment: "Invalid Fragment",
+	InvalidFragMsg:  "The fragment doesn't match the previous fragments.",
+	InvalidFrags:    "Invalid Fragments",
+	InvalidFragsMsg: "The fragments don't combine into valid data.\n\nCheck the fragments and try again.",
+	InvalidData:     "Invalid Data",
+	InvalidDataMsg:  "The fragments don't represent valid data.\n\nError details: {{.Err}}",
+	DiscardFrags:    "Discard Fragments?",
+	DiscardFragsMsg: "Going back will discard the entered fragments.\n\nHold button to confirm.",
+
+	WordOf:          "Word {{.N}}/{{.Total}}",
+	Dice:            "Dice",
+	CoinFlips:       "Coin Flips",
+	RollOf:          "Roll {{.N}} of {{.Total}}",
+	FlipOf:          "Flip {{.N}} of {{.Total}}",
+	InvalidRoll:     "Invalid Roll",
+	InvalidRollMsg:  "The roll doesn't match a word.\n\nRoll the dice again.",
+	CameraNoise:     "Camera Noise",
+	MixCameraNoise:  "Mix in camera noise?",
+	CollectingNoise: "Collecting camera noise",
+	CameraError:     "Camera error: {{.Err}}",
+	EntropySource:   "Choose entropy source",
+
+	EngravePlate:        "Engrave Plate",
+	EngravingLeft:       "Engraving plate, {{.Left}} left",
+	About:               "About {{.Duration}}.",
+	AboutTotal:          "About {{.Duration}} ({{.Total}} total).",
+	Minutes:             "{{.M}} min",
+	HoursMinutes:        "{{.H}} h {{.M}} min",
+	CheckFingerprint:    "Make sure the fingerprint above represents the intended share.",
+	DisconnectEngraver:  "Turn off the engraver and disconnect it from this device.",
+	MoveHammerhead:      "Manually move the hammerhead to the far upper left position.",
+	PlacePlate:          "Place a {{.Name}} on top\nof a protective plate.",
+	TightenNuts:         "Tighten the nuts firmly.",
+	AdjustNeedle:        "Loosen the hammerhead finger screw. Adjust needle distance to ~1.5 mm above the plate.",
+	TightenScrew:        "Tighten the hammerhead finger screw and make sure the depth selector is set to \"Strong\".",
+	ConnectEngraver:     "Turn on the engraving machine and connect this device via the middle port.",
+	StartEngraving:      "Hold button to start the engraving process. The process is loud, use hearing protection.",
+	FlipPlate:           "Unscrew the 4 nuts and flip the top metal plate horizontally.",
+	EngravingComplete:   "Engraving completed successfully.",
+	VerifyHint:          "Press the middle button to verify the plate by scanning it.",
+	ConnectionError:     "Connection Error",
+	ConnectionErrorMsg:  "Ensure the engraver is turned on and verify that it is connected to the middle port of this device.\n\nError details: {{.Err}}",
+	ConnectionLostMsg:   "Turn off the engraver and disconnect this device from it. Wait 10 seconds, then turn on the engraver and reconnect.\n\nError details: {{.Err}}",
+	EngravingCancelled:  "Engraving Cancelled",
+	EngravingCancelMsg:  "The engraver cancelled the engraving.",
+	NeedleNotParked:     "Needle Not Parked",
+	NeedleNotParkedMsg:  "The engraving completed, but the needle stopped away from its rest position. Check the plate and the needle before continuing.\n\nError details: {{.Err}}",
+	ResumeEngraving:     "Resume?",
+	ResumeEngravingLead: "Continue interrupted engraving",
+	CancelEngraving:     "Cancel?",
+	CancelEngravingMsg:  "This will cancel the engraving process.\n\nHold button to confirm.",
+
+	Preview:      "Preview",
+	PreviewSide:  "Side {{.N}} of {{.Total}}, {{.Zoom}}x",
+	PreviewOrder: "Side {{.N}} of {{.Total}}, {{.Zoom}}x, order",
+
+	Verify:                "Verify",
+	EngravedQR:            "Engraved QR code",
+	EngravedQROf:          "Engraved QR code {{.N}} of {{.Total}}",
+	VerificationFailed:    "Verification Failed",
+	VerificationFailedMsg: "The scanned QR code doesn't match the intended content.\n\nMake sure to scan the plate just engraved. Otherwise, don't use the plate for a backup.",
+	PlateVerified:         "Plate Verified",
+	PlateVerifiedMsg:      "The engraved QR codes match the intended content.",
+	UnreadableQR:          "Unreadable QR Code",
+	UnreadableQRMsg:       "No QR code could be read from the plate.\n\nIf the engraving is too shallow, the modules lack contrast: lower the needle and engrave a new plate. If the modules are smeared together, tighten the nuts and the hammerhead finger screw before engraving a new plate.\n\nOtherwise, scan again in even lighting.",
+
+	Session:          "Session",
+	NotMultisig:      "Not Multisig",
+	NotMultisigMsg:   "The descriptor has only a single share. Use Backup Wallet instead.",
+	ShareEngraved:    "Share Already Engraved",
+	ShareEngravedMsg: "The share {{.Fingerprint}} is already engraved in this session.\n\nInput the seed of a remaining share.",
+	EndSession:       "End Session?",
+	EndSessionMsg:    "{{.N}} shares are not engraved.\n\nHold button to confirm.",
+	SharesEngraved:   "{{.N}} of {{.Total}} shares engraved",
+	ShareVerified:    "{{.Plate}}, verified",
+	ShareUnverified:  "{{.Plate}}, not verified",
+	NotEngraved:      "Not engraved",
+
+	HistoryEmpty:       "No engravings recorded.",
+	HistoryUnavailable: "Insert the SD card to read the stored log.",
+	HistoryUnsigned:    "The log is not signed. Set up the audit key in Settings to store it.",
+	HistoryKey:         "Device {{.Key}}",
+	HistorySide:        "{{.Plate}}, side {{.Side}}",
+	HistoryShare:       "Share {{.N}}/{{.Total}}, {{.Fingerprint}}",
+	HistoryWallet:      "Wallet {{.Checksum}}",
+	HistoryDuration:    "{{.Actual}} (est. {{.Estimated}})",
+	HistoryFailed:      "Failed: {{.Err}}",
+	HistoryDryRun:      "Dry run",
+	HistoryPending:     "Not yet stored",
+	HistoryExported:    "Log Exported",
+	HistoryExportedMsg: "The signed log is stored in audit.json on the SD card.",
+
+	SettingPlate:        "Plate",
+	SettingMove:         "Move",
+	SettingPrint:        "Print",
+	SettingDryRun:       "Dry Run",
+	SettingCamera:       "Camera",
+	SettingGuide:        "Guide",
+	SettingLanguage:     "Language",
+	SettingGap:          "Gap",
+	HelpPlate:           "Smallest plate to engrave",
+	HelpMove:            "Speed of needle moves",
+	HelpPrint:           "Speed of engraving",
+	HelpDryRun:          "Dry run by default",
+	HelpCamera:          "Camera orientation",
+	HelpGuide:           "First time instructions",
+	HelpLanguage:        "User interface language",
+	HelpGap:             "Address search depth",
+	SettingAudit:        "Audit Key",
+	HelpAudit:           "Key that signs the history",
+	AuditKeyReady:       "Set up",
+	AuditKeyMissing:     "Not set up",
+	AuditKeyUnavailable: "Unavailable",
+	SetupAuditKey:       "Set Up Audit Key?",
+	SetupAuditKeyMsg:    "The key is stored in the one-time memory of this device. This is permanent and can't be undone.\n\nHold button to confirm.",
+	AuditKeySetUp:       "Audit Key Set Up",
+	AuditKeySetUpMsg:    "The engraving history is now signed and stored.",
+	On:                  "On",
+	Off:                 "Off",
+	DefaultSpeed:        "Default",
+	CameraNormal:        "Normal",
+	CameraRotated:       "Rotated",
+}
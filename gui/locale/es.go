@@ -0,0 +1,225 @@
+package locale
+
+var es = Catalog{
+	LanguageName: "Español",
+	Error:        "Error",
+
+	BackupWallet:    "Respaldar billetera",
+	RecoverWallet:   "Recuperar billetera",
+	GenerateSeed:    "Generar semilla",
+	MultisigSession: "Sesión multifirma",
+	Settings:        "Ajustes",
+	History:         "Historial",
+	RemoveSDCard:    "Retira la tarjeta SD",
+	RemoveSDCardMsg: "Retira la tarjeta SD para continuar.\n\nMantén pulsado el botón para ignorar esta advertencia.",
+
+	Descriptor:       "Descriptor",
+	InputMethod:      "Elige el método de entrada",
+	NumWords:         "Elige el número de palabras",
+	ChoiceScan:       "ESCANEAR",
+	ChoiceSkip:       "OMITIR",
+	ChoiceKeyboard:   "TECLADO",
+	ChoiceCamera:     "CÁMARA",
+	ChoiceLastWord:   "ÚLTIMA PALABRA",
+	Choice12Words:    "12 PALABRAS",
+	Choice24Words:    "24 PALABRAS",
+	ChoiceAddresses:  "DIRECCIONES",
+	ChoiceKeys:       "CLAVES",
+	ChoiceEngrave:    "GRABAR",
+	ChoiceResume:     "REANUDAR",
+	ChoiceRestart:    "REINICIAR",
+	ChoiceDiceWords:  "DADO: PALABRAS",
+	ChoiceDiceHash:   "DADO: HASH",
+	ChoiceCoinFlips:  "MONEDAS",
+	ChoiceReuse:      "REUTILIZAR",
+	Scan:             "Escanear",
+	OutputDescriptor: "Descriptor de la billetera",
+	SeedQR:           "SeedQR o mnemónico",
+
+	TooLarge:              "Demasiado grande",
+	DescriptorTooLarge:    "El descriptor no cabe en ningún tamaño de placa.",
+	QRTooLarge:            "El código QR contiene más datos de los que admite este dispositivo.",
+	InvalidQR:             "Código QR no válido",
+	InvalidQRMsg:          "El código QR está dañado o no es un código QR animado válido.",
+	IncompatibleQR:        "El código QR no coincide con los códigos QR escaneados antes.",
+	DuplicatedShare:       "Parte duplicada",
+	DuplicatedShareMsg:    "La parte {{.Fingerprint}} aparece más de una vez en la billetera.",
+	NotDescriptor:         "Los datos escaneados no son un descriptor de salida de billetera ni una clave XPUB.",
+	UnsupportedDescriptor: "El descriptor no es compatible.",
+	UnknownWallet:         "Billetera desconocida",
+	UnknownWalletMsg:      "La billetera no coincide con la semilla o está protegida con frase de contraseña.",
+	UnknownWalletConfirm:  "La billetera no coincide con la semilla.\n\nSi está protegida con frase de contraseña, mantén pulsado para confirmar.",
+	RecoveryFailed:        "Los códigos QR escaneados no forman una billetera compatible.",
+
+	ConfirmWallet: "Confirmar billetera",
+	WalletTitle:   "Título",
+	WalletType:    "Tipo",
+	WalletScript:  "Script",
+	Singlesig:     "Firma única",
+	Multisig:      "Multifirma {{.Threshold}} de {{.Keys}}",
+	Testnet:       "{{.Type}} (testnet)",
+	Receive:       "Recibir",
+	Change:        "Cambio",
+	WalletDetails: "Detalles",
+	DetailsLead:   "Elige qué mostrar",
+	Keys:          "Claves",
+	KeyN:          "Clave {{.N}}",
+	KeyNSeed:      "Clave {{.N}}, esta semilla",
+	UnusualPath:   "Ruta inusual",
+	TestnetKey:    "Clave de testnet",
+	MixedNetworks: "Las claves mezclan redes",
+
+	VerifyAddress:      "Verificar dirección",
+	BitcoinAddress:     "Dirección bitcoin",
+	InputAddress:       "Introducir dirección",
+	SearchingAddresses: "Buscando direcciones\n{{.N}} de {{.Total}}",
+	InvalidAddress:     "Dirección no válida",
+	InvalidAddressMsg:  "Los datos no son una dirección válida para la red de esta billetera.",
+	AddressFound:       "Dirección encontrada",
+	ReceiveAddressMsg:  "La dirección pertenece a esta billetera.\n\nEs la dirección de recepción con índice {{.Index}}.",
+	ChangeAddressMsg:   "La dirección pertenece a esta billetera.\n\nEs la dirección de cambio con índice {{.Index}}.",
+	AddressNotFound:    "No está en la billetera",
+	AddressNotFoundMsg: "La dirección no pertenece a esta billetera.\n\nNinguna de las primeras {{.Gap}} direcciones de recepción y cambio coincide.",
+
+	Recover:         "Recuperar",
+	ScanPlateQRs:    "Escanea los QR de las placas",
+	QRsScanned:      "{{.N}} códigos QR escaneados",
+	Recovered:       "Recuperada",
+	WalletRecovered: "Billetera recuperada",
+	SharesOf:        "Partes {{.Shares}} de {{.N}}",
+
+	InputSeed:       "Introducir semilla",
+	ConfirmSeed:     "Confirmar semilla",
+	InputWords:      "Introducir palabras",
+	LastWord:        "Última palabra",
+	Candidate:       "Candidata {{.N}} de {{.Total}}",
+	InvalidSeed:     "Semilla no válida",
+	ElectrumSeed:    "Las semillas de Electrum no son compatibles.",
+	NotSeed:         "Los datos escaneados no son una semilla.",
+	InvalidMnemonic: "La frase semilla no es válida.\n\nRevisa las palabras e inténtalo de nuevo.",
+	DiscardSeed:     "¿Descartar semilla?",
+	DiscardSeedMsg:  "Volver atrás descartará la semilla.\n\nMantén pulsado el botón para confirmar.",
+	InputFragment:   "Introducir fragmento",
+	InvalidFragment: "Fragmento no válido",
+	InvalidFragMsg:  "El fragmento no coincide con los fragmentos anteriores.",
+	InvalidFrags:    "Fragmentos no válidos",
+	InvalidFragsMsg: "Los fragmentos no forman datos válidos.\n\nRevisa los fragmentos e inténtalo de nuevo.",
+	InvalidData:     "Datos no válidos",
+	InvalidDataMsg:  "Los fragmentos no representan datos válidos.\n\nDetalles del error: {{.Err}}",
+	DiscardFrags:    "¿Descartar fragmentos?",
+	DiscardFragsMsg: "Volver atrás descartará los fragmentos introducidos.\n\nMantén pulsado el botón para confirmar.",
+
+	WordOf:          "Palabra {{.N}}/{{.Total}}",
+	Dice:            "Dados",
+	CoinFlips:       "Monedas",
+	RollOf:          "Tirada {{.N}} de {{.Total}}",
+	FlipOf:          "Lanzamiento {{.N}} de {{.Total}}",
+	InvalidRoll:     "Tirada no válida",
+	InvalidRollMsg:  "La tirada no corresponde a ninguna palabra.\n\nVuelve a tirar los dados.",
+	CameraNoise:     "Ruido de cámara",
+	MixCameraNoise:  "¿Mezclar ruido de la cámara?",
+	CollectingNoise: "Recogiendo ruido de la cámara",
+	CameraError:     "Error de cámara: {{.Err}}",
+	EntropySource:   "Elige la fuente de entropía",
+
+	EngravePlate:        "Grabar placa",
+	EngravingLeft:       "Grabando placa, quedan {{.Left}}",
+	About:               "Unos {{.Duration}}.",
+	AboutTotal:          "Unos {{.Duration}} ({{.Total}} en total).",
+	Minutes:             "{{.M}} min",
+	HoursMinutes:        "{{.H}} h {{.M}} min",
+	CheckFingerprint:    "Asegúrate de que la huella de arriba corresponde a la parte deseada.",
+	DisconnectEngraver:  "Apaga el grabador y desconéctalo de este dispositivo.",
+	MoveHammerhead:      "Mueve a mano el cabezal hasta la posición superior izquierda.",
+	PlacePlate:          "Coloca una {{.Name}} sobre\nuna placa protectora.",
+	TightenNuts:         "Aprieta bien las tuercas.",
+	AdjustNeedle:        "Afloja el tornillo del cabezal. Ajusta la aguja a ~1,5 mm sobre la placa.",
+	TightenScrew:        "Aprieta el tornillo del cabezal y asegúrate de que el selector de profundidad esté en \"Strong\".",
+	ConnectEngraver:     "Enciende el grabador y conecta este dispositivo al puerto central.",
+	StartEngraving:      "Mantén pulsado el botón para empezar a grabar. El proceso es ruidoso, usa protección auditiva.",
+	FlipPlate:           "Desenrosca las 4 tuercas y da la vuelta horizontalmente a la placa metálica superior.",
+	EngravingComplete:   "Grabado completado con éxito.",
+	VerifyHint:          "Pulsa el botón central para verificar la placa escaneándola.",
+	ConnectionError:     "Error de conexión",
+	ConnectionErrorMsg:  "Asegúrate de que el grabador está encendido y conectado al puerto central de este dispositivo.\n\nDetalles del error: {{.Err}}",
+	ConnectionLostMsg:   "Apaga el grabador y desconecta este dispositivo. Espera 10 segundos, enciende el grabador y vuelve a conectarlo.\n\nDetalles del error: {{.Err}}",
+	EngravingCancelled:  "Grabado cancelado",
+	EngravingCancelMsg:  "El grabador canceló el grabado.",
+	NeedleNotParked:     "Aguja no aparcada",
+	NeedleNotParkedMsg:  "El grabado ha terminado, pero la aguja se detuvo fuera de su posición de reposo. Revisa la placa y la aguja antes de continuar.\n\nDetalles del error: {{.Err}}",
+	ResumeEngraving:     "¿Reanudar?",
+	ResumeEngravingLead: "Continuar el grabado interrumpido",
+	CancelEngraving:     "¿Cancelar?",
+	CancelEngravingMsg:  "Esto cancelará el proceso de grabado.\n\nMantén pulsado el botón para confirmar.",
+
+	Preview:      "Vista previa",
+	PreviewSide:  "Cara {{.N}} de {{.Total}}, {{.Zoom}}x",
+	PreviewOrder: "Cara {{.N}} de {{.Total}}, {{.Zoom}}x, orden",
+
+	Verify:                "Verificar",
+	EngravedQR:            "Código QR grabado",
+	EngravedQROf:          "Código QR grabado {{.N}} de {{.Total}}",
+	VerificationFailed:    "Verificación fallida",
+	VerificationFailedMsg: "El código QR escaneado no coincide con el contenido previsto.\n\nAsegúrate de escanear la placa recién grabada. Si no, no uses la placa como respaldo.",
+	PlateVerified:         "Placa verificada",
+	PlateVerifiedMsg:      "Los códigos QR grabados coinciden con el contenido previsto.",
+	UnreadableQR:          "Código QR ilegible",
+	UnreadableQRMsg:       "No se pudo leer ningún código QR de la placa.\n\nSi el grabado es poco profundo, los módulos no tienen contraste: baja la aguja y graba una placa nueva. Si los módulos están corridos, aprieta las tuercas y el tornillo del cabezal antes de grabar una placa nueva.\n\nSi no, vuelve a escanear con luz uniforme.",
+
+	Session:          "Sesión",
+	NotMultisig:      "No es multifirma",
+	NotMultisigMsg:   "El descriptor solo tiene una parte. Usa Respaldar billetera.",
+	ShareEngraved:    "Parte ya grabada",
+	ShareEngravedMsg: "La parte {{.Fingerprint}} ya se grabó en esta sesión.\n\nIntroduce la semilla de una parte restante.",
+	EndSession:       "¿Terminar sesión?",
+	EndSessionMsg:    "{{.N}} partes no están grabadas.\n\nMantén pulsado el botón para confirmar.",
+	SharesEngraved:   "{{.N}} de {{.Total}} partes grabadas",
+	ShareVerified:    "{{.Plate}}, verificada",
+	ShareUnverified:  "{{.Plate}}, sin verificar",
+	NotEngraved:      "Sin grabar",
+
+	HistoryEmpty:       "No hay grabados registrados.",
+	HistoryUnavailable: "Inserta la tarjeta SD para leer el registro guardado.",
+	HistoryUnsigned:    "El registro no está firmado. Configura la clave de auditoría en Ajustes para guardarlo.",
+	HistoryKey:         "Dispositivo {{.Key}}",
+	HistorySide:        "{{.Plate}}, cara {{.Side}}",
+	HistoryShare:       "Parte {{.N}}/{{.Total}}, {{.Fingerprint}}",
+	HistoryWallet:      "Cartera {{.Checksum}}",
+	HistoryDuration:    "{{.Actual}} (est. {{.Estimated}})",
+	HistoryFailed:      "Error: {{.Err}}",
+	HistoryDryRun:      "Prueba",
+	HistoryPending:     "Aún no guardado",
+	HistoryExported:    "Registro exportado",
+	HistoryExportedMsg: "El registro firmado está en audit.json en la tarjeta SD.",
+
+	SettingPlate:        "Placa",
+	SettingMove:         "Mover",
+	SettingPrint:        "Grabar",
+	SettingDryRun:       "Prueba",
+	SettingCamera:       "Cámara",
+	SettingGuide:        "Guía",
+	SettingLanguage:     "Idioma",
+	SettingGap:          "Rango",
+	HelpPlate:           "Placa mínima a grabar",
+	HelpMove:            "Velocidad de movimiento",
+	HelpPrint:           "Velocidad de grabado",
+	HelpDryRun:          "Prueba en seco por defecto",
+	HelpCamera:          "Orientación de la cámara",
+	HelpGuide:           "Instrucciones iniciales",
+	HelpLanguage:        "Idioma de la interfaz",
+	HelpGap:             "Alcance de la búsqueda",
+	SettingAudit:        "Clave de auditoría",
+	HelpAudit:           "Firma el historial",
+	AuditKeyReady:       "Configurada",
+	AuditKeyMissing:     "Sin configurar",
+	AuditKeyUnavailable: "No disponible",
+	SetupAuditKey:       "¿Configurar la clave de auditoría?",
+	SetupAuditKeyMsg:    "La clave se guarda en la memoria de un solo uso de este dispositivo. Es permanente y no se puede deshacer.\n\nMantén pulsado el botón para confirmar.",
+	AuditKeySetUp:       "Clave de auditoría configurada",
+	AuditKeySetUpMsg:    "El historial de grabado ahora se firma y se guarda.",
+	On:                  "Sí",
+	Off:                 "No",
+	DefaultSpeed:        "Normal",
+	CameraNormal:        "Normal",
+	CameraRotated:       "Girada",
+}
@@ -0,0 +1,225 @@
+package locale
+
+var ja = Catalog{
+	LanguageName: "日本語",
+	Error:        "エラー",
+
+	BackupWallet:    "ウォレットのバックアップ",
+	RecoverWallet:   "ウォレットの復元",
+	GenerateSeed:    "シードの生成",
+	MultisigSession: "マルチシグ",
+	Settings:        "設定",
+	History:         "履歴",
+	RemoveSDCard:    "SDカードを抜く",
+	RemoveSDCardMsg: "続行するにはSDカードを抜いてください。\n\nこの警告を無視するにはボタンを長押しします。",
+
+	Descriptor:       "ディスクリプタ",
+	InputMethod:      "入力方法を選択",
+	NumWords:         "単語数を選択",
+	ChoiceScan:       "スキャン",
+	ChoiceSkip:       "スキップ",
+	ChoiceKeyboard:   "キーボード",
+	ChoiceCamera:     "カメラ",
+	ChoiceLastWord:   "最後の単語",
+	Choice12Words:    "12単語",
+	Choice24Words:    "24単語",
+	ChoiceAddresses:  "アドレス",
+	ChoiceKeys:       "鍵",
+	ChoiceEngrave:    "刻印",
+	ChoiceResume:     "再開",
+	ChoiceRestart:    "やり直す",
+	ChoiceDiceWords:  "サイコロ単語",
+	ChoiceDiceHash:   "サイコロハッシュ",
+	ChoiceCoinFlips:  "コイン投げ",
+	ChoiceReuse:      "再利用",
+	Scan:             "スキャン",
+	OutputDescriptor: "ウォレットのディスクリプタ",
+	SeedQR:           "SeedQRまたはニーモニック",
+
+	TooLarge:              "大きすぎます",
+	DescriptorTooLarge:    "ディスクリプタがどのプレートにも収まりません。",
+	QRTooLarge:            "QRコードのデータがこのデバイスの上限を超えています。",
+	InvalidQR:             "無効なQRコード",
+	InvalidQRMsg:          "QRコードが破損しているか、有効なアニメーションQRコードではありません。",
+	IncompatibleQR:        "QRコードが前にスキャンしたQRコードと一致しません。",
+	DuplicatedShare:       "重複したシェア",
+	DuplicatedShareMsg:    "シェア{{.Fingerprint}}がウォレットに複数回含まれています。",
+	NotDescriptor:         "スキャンしたデータはウォレットの出力ディスクリプタでもXPUBキーでもありません。",
+	UnsupportedDescriptor: "このディスクリプタには対応していません。",
+	UnknownWallet:         "不明なウォレット",
+	UnknownWalletMsg:      "ウォレットがシードと一致しないか、パスフレーズで保護されています。",
+	UnknownWalletConfirm:  "ウォレットがシードと一致しません。\n\nパスフレーズで保護されている場合は、長押しして確定します。",
+	RecoveryFailed:        "スキャンしたQRコードから対応するウォレットを復元できません。",
+
+	ConfirmWallet: "ウォレットの確認",
+	WalletTitle:   "タイトル",
+	WalletType:    "種類",
+	WalletScript:  "スクリプト",
+	Singlesig:     "シングルシグ",
+	Multisig:      "{{.Threshold}}-of-{{.Keys}}マルチシグ",
+	Testnet:       "{{.Type}}(テストネット)",
+	Receive:       "受取",
+	Change:        "おつり",
+	WalletDetails: "ウォレットの詳細",
+	DetailsLead:   "表示する項目を選択",
+	Keys:          "鍵",
+	KeyN:          "鍵{{.N}}",
+	KeyNSeed:      "鍵{{.N}}(このシード)",
+	UnusualPath:   "通常と異なるパス",
+	TestnetKey:    "テストネットの鍵",
+	MixedNetworks: "鍵のネットワークが混在",
+
+	VerifyAddress:      "アドレスの検証",
+	BitcoinAddress:     "ビットコインアドレス",
+	InputAddress:       "アドレスの入力",
+	SearchingAddresses: "アドレスを検索中\n{{.N}} / {{.Total}}",
+	InvalidAddress:     "無効なアドレス",
+	InvalidAddressMsg:  "このウォレットのネットワークの有効なアドレスではありません。",
+	AddressFound:       "アドレス一致",
+	ReceiveAddressMsg:  "このアドレスはこのウォレットのものです。\n\nインデックス{{.Index}}の受取アドレスです。",
+	ChangeAddressMsg:   "このアドレスはこのウォレットのものです。\n\nインデックス{{.Index}}のおつりアドレスです。",
+	AddressNotFound:    "該当なし",
+	AddressNotFoundMsg: "このアドレスはこのウォレットのものではありません。\n\n最初の{{.Gap}}個の受取アドレスとおつりアドレスのいずれとも一致しません。",
+
+	Recover:         "復元",
+	ScanPlateQRs:    "プレートのQRをスキャン",
+	QRsScanned:      "QRコード{{.N}}個をスキャン済み",
+	Recovered:       "復元完了",
+	WalletRecovered: "ウォレットを復元しました",
+	SharesOf:        "シェア{{.Shares}} / {{.N}}",
+
+	InputSeed:       "シードの入力",
+	ConfirmSeed:     "シードの確認",
+	InputWords:      "単語の入力",
+	LastWord:        "最後の単語",
+	Candidate:       "候補 {{.N}} / {{.Total}}",
+	InvalidSeed:     "無効なシード",
+	ElectrumSeed:    "Electrumのシードには対応していません。",
+	NotSeed:         "スキャンしたデータはシードではありません。",
+	InvalidMnemonic: "シードフレーズが無効です。\n\n単語を確認して、もう一度お試しください。",
+	DiscardSeed:     "シードを破棄しますか?",
+	DiscardSeedMsg:  "戻るとシードが破棄されます。\n\n確定するにはボタンを長押しします。",
+	InputFragment:   "フラグメントの入力",
+	InvalidFragment: "無効なフラグメント",
+	InvalidFragMsg:  "フラグメントが前のフラグメントと一致しません。",
+	InvalidFrags:    "無効なフラグメント",
+	InvalidFragsMsg: "フラグメントを組み合わせても有効なデータになりません。\n\nフラグメントを確認して、もう一度お試しください。",
+	InvalidData:     "無効なデータ",
+	InvalidDataMsg:  "フラグメントは有効なデータではありません。\n\nエラーの詳細: {{.Err}}",
+	DiscardFrags:    "フラグメントを破棄しますか?",
+	DiscardFragsMsg: "戻ると入力したフラグメントが破棄されます。\n\n確定するにはボタンを長押しします。",
+
+	WordOf:          "単語 {{.N}}/{{.Total}}",
+	Dice:            "サイコロ",
+	CoinFlips:       "コイン投げ",
+	RollOf:          "出目 {{.N}} / {{.Total}}",
+	FlipOf:          "コイン {{.N}} / {{.Total}}",
+	InvalidRoll:     "無効な出目",
+	InvalidRollMsg:  "出目が単語に対応しません。\n\nもう一度サイコロを振ってください。",
+	CameraNoise:     "カメラノイズ",
+	MixCameraNoise:  "カメラノイズを混ぜますか?",
+	CollectingNoise: "カメラノイズを収集中",
+	CameraError:     "カメラエラー: {{.Err}}",
+	EntropySource:   "エントロピー源を選択",
+
+	EngravePlate:        "プレートの刻印",
+	EngravingLeft:       "刻印中、残り{{.Left}}",
+	About:               "約{{.Duration}}。",
+	AboutTotal:          "約{{.Duration}}(合計{{.Total}})。",
+	Minutes:             "{{.M}}分",
+	HoursMinutes:        "{{.H}}時間{{.M}}分",
+	CheckFingerprint:    "上のフィンガープリントが目的のシェアであることを確認してください。",
+	DisconnectEngraver:  "刻印機の電源を切り、このデバイスから外してください。",
+	MoveHammerhead:      "ハンマーヘッドを手で左上の端まで動かしてください。",
+	PlacePlate:          "{{.Name}}を保護プレートの\n上に置いてください。",
+	TightenNuts:         "ナットをしっかり締めてください。",
+	AdjustNeedle:        "ハンマーヘッドのつまみネジを緩め、針をプレートの約1.5 mm上に合わせてください。",
+	TightenScrew:        "ハンマーヘッドのつまみネジを締め、深さセレクタが\"Strong\"になっていることを確認してください。",
+	ConnectEngraver:     "刻印機の電源を入れ、このデバイスを中央のポートに接続してください。",
+	StartEngraving:      "ボタンを長押しすると刻印を開始します。大きな音がするので、耳を保護してください。",
+	FlipPlate:           "4つのナットを外し、上の金属プレートを左右に裏返してください。",
+	EngravingComplete:   "刻印が正常に完了しました。",
+	VerifyHint:          "中央のボタンを押すと、プレートをスキャンして検証します。",
+	ConnectionError:     "接続エラー",
+	ConnectionErrorMsg:  "刻印機の電源が入っていて、このデバイスの中央のポートに接続されていることを確認してください。\n\nエラーの詳細: {{.Err}}",
+	ConnectionLostMsg:   "刻印機の電源を切り、このデバイスを外してください。10秒待ってから刻印機の電源を入れ、接続し直してください。\n\nエラーの詳細: {{.Err}}",
+	EngravingCancelled:  "刻印の中止",
+	EngravingCancelMsg:  "刻印機が刻印を中止しました。",
+	NeedleNotParked:     "針が待機位置にありません",
+	NeedleNotParkedMsg:  "刻印は完了しましたが、針が待機位置から外れて停止しました。続ける前にプレートと針を確認してください。\n\nエラーの詳細: {{.Err}}",
+	ResumeEngraving:     "再開しますか?",
+	ResumeEngravingLead: "中断した刻印を続ける",
+	CancelEngraving:     "中止しますか?",
+	CancelEngravingMsg:  "刻印を中止します。\n\n確定するにはボタンを長押しします。",
+
+	Preview:      "プレビュー",
+	PreviewSide:  "面 {{.N}} / {{.Total}}、{{.Zoom}}倍",
+	PreviewOrder: "面 {{.N}} / {{.Total}}、{{.Zoom}}倍、順序",
+
+	Verify:                "検証",
+	EngravedQR:            "刻印したQRコード",
+	EngravedQROf:          "刻印したQRコード {{.N}} / {{.Total}}",
+	VerificationFailed:    "検証失敗",
+	VerificationFailedMsg: "スキャンしたQRコードが意図した内容と一致しません。\n\n刻印したばかりのプレートをスキャンしてください。一致しない場合は、そのプレートをバックアップに使わないでください。",
+	PlateVerified:         "検証済み",
+	PlateVerifiedMsg:      "刻印したQRコードは意図した内容と一致します。",
+	UnreadableQR:          "読み取り不可",
+	UnreadableQRMsg:       "プレートからQRコードを読み取れませんでした。\n\n刻印が浅すぎるとモジュールのコントラストが不足します。針を下げて新しいプレートを刻印してください。モジュールがつぶれている場合は、ナットとハンマーヘッドのつまみネジを締めてから新しいプレートを刻印してください。\n\nそれ以外の場合は、均一な明るさでもう一度スキャンしてください。",
+
+	Session:          "セッション",
+	NotMultisig:      "マルチシグではありません",
+	NotMultisigMsg:   "このディスクリプタのシェアは1つだけです。代わりにウォレットのバックアップを使ってください。",
+	ShareEngraved:    "刻印済みのシェア",
+	ShareEngravedMsg: "シェア{{.Fingerprint}}はこのセッションで刻印済みです。\n\n残りのシェアのシードを入力してください。",
+	EndSession:       "終了しますか?",
+	EndSessionMsg:    "{{.N}}個のシェアが未刻印です。\n\n確定するにはボタンを長押しします。",
+	SharesEngraved:   "{{.Total}}個中{{.N}}個のシェアを刻印済み",
+	ShareVerified:    "{{.Plate}}、検証済み",
+	ShareUnverified:  "{{.Plate}}、未検証",
+	NotEngraved:      "未刻印",
+
+	HistoryEmpty:       "刻印の記録はありません。",
+	HistoryUnavailable: "保存された記録を読むにはSDカードを挿入してください。",
+	HistoryUnsigned:    "記録は署名されていません。保存するには設定で監査キーを設定してください。",
+	HistoryKey:         "デバイス鍵 {{.Key}}",
+	HistorySide:        "{{.Plate}}、面{{.Side}}",
+	HistoryShare:       "シェア{{.N}}/{{.Total}}、{{.Fingerprint}}",
+	HistoryWallet:      "ウォレット {{.Checksum}}",
+	HistoryDuration:    "{{.Actual}}(見積{{.Estimated}})",
+	HistoryFailed:      "失敗: {{.Err}}",
+	HistoryDryRun:      "試運転",
+	HistoryPending:     "未保存",
+	HistoryExported:    "記録をエクスポート",
+	HistoryExportedMsg: "署名済みの記録はSDカードのaudit.jsonに保存されています。",
+
+	SettingPlate:        "プレート",
+	SettingMove:         "移動",
+	SettingPrint:        "刻印",
+	SettingDryRun:       "試運転",
+	SettingCamera:       "カメラ",
+	SettingGuide:        "ガイド",
+	SettingLanguage:     "言語",
+	SettingGap:          "検索数",
+	HelpPlate:           "刻印する最小のプレート",
+	HelpMove:            "針の移動速度",
+	HelpPrint:           "刻印の速度",
+	HelpDryRun:          "既定で試運転",
+	HelpCamera:          "カメラの向き",
+	HelpGuide:           "初回の手順",
+	HelpLanguage:        "表示言語",
+	HelpGap:             "アドレス検索の範囲",
+	SettingAudit:        "監査キー",
+	HelpAudit:           "履歴に署名するキー",
+	AuditKeyReady:       "設定済み",
+	AuditKeyMissing:     "未設定",
+	AuditKeyUnavailable: "利用不可",
+	SetupAuditKey:       "監査キーを設定しますか?",
+	SetupAuditKeyMsg:    "キーはこのデバイスのワンタイムメモリに保存されます。これは永久的で、元に戻せません。\n\nボタンを長押しして確定します。",
+	AuditKeySetUp:       "監査キーを設定しました",
+	AuditKeySetUpMsg:    "刻印の履歴は署名されて保存されます。",
+	On:                  "オン",
+	Off:                 "オフ",
+	DefaultSpeed:        "既定",
+	CameraNormal:        "標準",
+	CameraRotated:       "回転",
+}
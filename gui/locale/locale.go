@@ -0,0 +1,86 @@
+// package locale implements the message catalogs of the user
+// interface.
+//
+// Messages are identified by ID and may contain {{.Key}}
+// placeholders that are substituted when formatted.
+package locale
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ID identifies a message.
+type ID string
+
+// Catalog maps message IDs to their translation.
+type Catalog map[ID]string
+
+// Default is the locale of the source messages. Messages missing
+// from other catalogs fall back to the default catalog.
+const Default = "en"
+
+var catalogs = map[string]Catalog{
+	"en": en,
+	"de": de,
+	"es": es,
+	"ja": ja,
+}
+
+// Locales returns the supported locales, default first.
+func Locales() []string {
+	var locs []string
+	for l := range catalogs {
+		if l != Default {
+			locs = append(locs, l)
+		}
+	}
+	sort.Strings(locs)
+	return append([]string{Default}, locs...)
+}
+
+// Lookup returns the catalog of a locale, or nil if the locale
+// is not supported.
+func Lookup(locale string) Catalog {
+	return catalogs[locale]
+}
+
+// Text returns the message for id in locale, with the
+// placeholders substituted by args. Args are pairs of keys and
+// values, such that the pair "Name", "SH01" replaces {{.Name}}
+// with SH01.
+func Text(locale string, id ID, args ...string) string {
+	msg, ok := catalogs[locale][id]
+	if !ok {
+		msg, ok = en[id]
+	}
+	if !ok {
+		panic("unknown message: " + string(id))
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	if len(args)%2 != 0 {
+		panic("odd number of arguments")
+	}
+	var pairs []string
+	for i := 0; i < len(args); i += 2 {
+		pairs = append(pairs, "{{."+args[i]+"}}", args[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Runes returns the set of runes in the messages of a catalog,
+// including their title case variants for messages displayed
+// in title case.
+func (c Catalog) Runes() map[rune]bool {
+	runes := make(map[rune]bool)
+	for _, msg := range c {
+		for _, r := range msg {
+			runes[r] = true
+			runes[unicode.ToTitle(r)] = true
+		}
+	}
+	return runes
+}
@@ -0,0 +1,65 @@
+package locale
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+var placeholder = regexp.MustCompile(`{{\.[A-Za-z]+}}`)
+
+func placeholders(msg string) []string {
+	p := placeholder.FindAllString(msg, -1)
+	sort.Strings(p)
+	return p
+}
+
+func TestCatalogs(t *testing.T) {
+	for loc, c := range catalogs {
+		if _, ok := c[LanguageName]; !ok {
+			t.Errorf("%s: missing language name", loc)
+		}
+		for id, msg := range c {
+			src, ok := en[id]
+			if !ok {
+				t.Errorf("%s: unknown message %q", loc, id)
+				continue
+			}
+			if got, want := placeholders(msg), placeholders(src); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %q has placeholders %v, want %v", loc, id, got, want)
+			}
+		}
+		for id := range en {
+			if _, ok := c[id]; !ok {
+				t.Errorf("%s: missing message %q", loc, id)
+			}
+		}
+	}
+}
+
+func TestLocales(t *testing.T) {
+	locs := Locales()
+	if len(locs) != len(catalogs) || locs[0] != Default {
+		t.Errorf("Locales() = %v, want %d locales starting with %q", locs, len(catalogs), Default)
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		locale string
+		id     ID
+		args   []string
+		want   string
+	}{
+		{"en", Multisig, []string{"Threshold", "2", "Keys", "3"}, "2-of-3 multisig"},
+		{"de", Multisig, []string{"Threshold", "2", "Keys", "3"}, "2-von-3-Multisig"},
+		{"xx", Receive, nil, "Receive"},
+		{"de", PlacePlate, []string{"Name", "SH01"}, "Lege eine SH01 auf\neine Schutzplatte."},
+	}
+	for _, test := range tests {
+		if got := Text(test.locale, test.id, test.args...); got != test.want {
+			t.Errorf("Text(%q, %q, %q) = %q, want %q", test.locale, test.id, test.args, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package gui
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+
+	"seedhammer.com/driver/mjolnir"
+	"seedhammer.com/engrave"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/gui/widget"
+	"seedhammer.com/image/rgb565"
+)
+
+// PreviewScreen shows the engraving of a plate side, overlaid with
+// the safe area and the screw hole keep-outs. The view can be
+// zoomed and panned, and the lines colored by engraving order.
+type PreviewScreen struct {
+	Plate Plate
+	Side  int
+
+	zoom int
+	// center is the center of the view, in machine units.
+	center image.Point
+	// order colors the lines by engraving order.
+	order bool
+	cache struct {
+		img   *rgb565.Image
+		ppmm  float32
+		order bool
+	}
+}
+
+const (
+	// maxPreviewZoom is the base 2 logarithm of the largest
+	// magnification.
+	maxPreviewZoom = 2
+	// orderSteps is the number of colors of the engraving
+	// order.
+	orderSteps = 16
+)
+
+var (
+	previewPlateColor = color.NRGBA{R: 0xc8, G: 0xc8, B: 0xc8, A: 0xff}
+	previewSafeColor  = color.NRGBA{R: 0x00, G: 0xa0, B: 0x40, A: 0xff}
+	previewHoleColor  = color.NRGBA{R: 0xff, G: 0x00, B: 0x00, A: 0x60}
+	previewFirstColor = color.NRGBA{R: 0x00, G: 0x40, B: 0xff, A: 0xff}
+	previewLastColor  = color.NRGBA{R: 0xff, G: 0x40, B: 0x00, A: 0xff}
+)
+
+func NewPreviewScreen(plate Plate, side int) *PreviewScreen {
+	b := plate.Size.Bounds()
+	return &PreviewScreen{
+		Plate: plate,
+		Side:  side,
+		center: image.Pt(
+			int(float32(b.Min.X+b.Max.X)/2*mjolnir.Millimeter),
+			int(float32(b.Min.Y+b.Max.Y)/2*mjolnir.Millimeter),
+		),
+	}
+}
+
+// ppmm returns the number of pixels per millimeter that
+// fits the plate inside view at the current zoom level.
+func (s *PreviewScreen) ppmm(view image.Point) float32 {
+	sz := s.Plate.Size.Bounds().Size()
+	ppmm := float32(view.X) / float32(sz.X)
+	if h := float32(view.Y) / float32(sz.Y); h < ppmm {
+		ppmm = h
+	}
+	return ppmm * float32(int(1)<<s.zoom)
+}
+
+// render rasterizes the side at ppmm pixels per millimeter,
+// re-using the previous image if possible.
+func (s *PreviewScreen) render(ppmm float32) *rgb565.Image {
+	if c := s.cache; c.img != nil && c.ppmm == ppmm && c.order == s.order {
+		return c.img
+	}
+	b := s.Plate.Size.Bounds()
+	img := rgb565.New(image.Rect(
+		int(float32(b.Min.X)*ppmm), int(float32(b.Min.Y)*ppmm),
+		int(float32(b.Max.X)*ppmm), int(float32(b.Max.Y)*ppmm),
+	))
+	draw.Draw(img, img.Bounds(), image.NewUniform(previewPlateColor), image.Point{}, draw.Src)
+	r := engrave.NewRasterizer(img, img.Bounds(), ppmm/mjolnir.Millimeter, mjolnir.StrokeWidth*ppmm)
+	side := s.Plate.Sides[s.Side]
+	if s.order {
+		var n instructionCounter
+		side.Engrave(&n)
+		side.Engrave(&orderProgram{r: r, n: int(n)})
+	} else {
+		side.Engrave(r)
+	}
+	r.Rasterize()
+	s.cache.img = img
+	s.cache.ppmm = ppmm
+	s.cache.order = s.order
+	return img
+}
+
+// orderProgram colors lines along a gradient from the first
+// to the last instruction.
+type orderProgram struct {
+	r *engrave.Rasterizer
+	n int
+	i int
+}
+
+func (o *orderProgram) step() {
+	if o.i*orderSteps%o.n < orderSteps {
+		t := o.i * orderSteps / o.n
+		o.r.SetColor(lerpColor(previewFirstColor, previewLastColor, t, orderSteps-1))
+	}
+	o.i++
+}
+
+func (o *orderProgram) Move(p image.Point) {
+	o.step()
+	o.r.Move(p)
+}
+
+func (o *orderProgram) Line(p image.Point) {
+	o.step()
+	o.r.Line(p)
+}
+
+func lerpColor(c0, c1 color.NRGBA, t, n int) color.NRGBA {
+	lerp := func(v0, v1 uint8) uint8 {
+		return uint8((int(v0)*(n-t) + int(v1)*t) / n)
+	}
+	return color.NRGBA{
+		R: lerp(c0.R, c1.R),
+		G: lerp(c0.G, c1.G),
+		B: lerp(c0.B, c1.B),
+		A: lerp(c0.A, c1.A),
+	}
+}
+
+// pan moves the view center by a quarter of the view in
+// direction d.
+func (s *PreviewScreen) pan(view image.Point, ppmm float32, d image.Point) {
+	step := image.Pt(
+		int(float32(view.X/4)/ppmm*mjolnir.Millimeter),
+		int(float32(view.Y/4)/ppmm*mjolnir.Millimeter),
+	)
+	s.center = s.center.Add(image.Pt(d.X*step.X, d.Y*step.Y))
+}
+
+// clampCenter keeps the view inside the plate, or centers the
+// plate if it fits inside the view.
+func (s *PreviewScreen) clampCenter(view image.Point, ppmm float32) {
+	b := s.Plate.Size.Bounds()
+	clamp := func(c, min, max, view int) int {
+		lo := int(float32(min) * mjolnir.Millimeter)
+		hi := int(float32(max) * mjolnir.Millimeter)
+		half := int(float32(view) / 2 / ppmm * mjolnir.Millimeter)
+		if hi-lo <= 2*half {
+			return (lo + hi) / 2
+		}
+		if c < lo+half {
+			return lo + half
+		}
+		if c > hi-half {
+			return hi - half
+		}
+		return c
+	}
+	s.center.X = clamp(s.center.X, b.Min.X, b.Max.X, view.X)
+	s.center.Y = clamp(s.center.Y, b.Min.Y, b.Max.Y, view.Y)
+}
+
+func (s *PreviewScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) Result {
+	r := layout.Rectangle{Max: dims}
+	_, view := r.CutTop(leadingSize)
+	view, lead := view.CutBottom(leadingSize)
+	navw := assets.NavBtnPrimary.Bounds().Dx()
+	view = view.Shrink(0, navw, 0, navw)
+	viewsz := view.Size()
+	for {
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down, Left, Right, CCW, CW)
+		if !ok {
+			break
+		}
+		if !e.Pressed && !e.Click {
+			continue
+		}
+		ppmm := s.ppmm(viewsz)
+		switch e.Button {
+		case Button1:
+			if e.Click {
+				return ResultCancelled
+			}
+		case Button3:
+			if e.Click {
+				s.zoom = (s.zoom + 1) % (maxPreviewZoom + 1)
+			}
+		case Center:
+			if e.Click {
+				s.order = !s.order
+			}
+		case CW:
+			if e.Pressed && s.zoom < maxPreviewZoom {
+				s.zoom++
+			}
+		case CCW:
+			if e.Pressed && s.zoom > 0 {
+				s.zoom--
+			}
+		case Up:
+			if e.Pressed {
+				s.pan(viewsz, ppmm, image.Pt(0, -1))
+			}
+		case Down:
+			if e.Pressed {
+				s.pan(viewsz, ppmm, image.Pt(0, 1))
+			}
+		case Left:
+			if e.Pressed {
+				s.pan(viewsz, ppmm, image.Pt(-1, 0))
+			}
+		case Right:
+			if e.Pressed {
+				s.pan(viewsz, ppmm, image.Pt(1, 0))
+			}
+		}
+	}
+	ppmm := s.ppmm(viewsz)
+	s.clampCenter(viewsz, ppmm)
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.Preview))
+
+	img := s.render(ppmm)
+	// off maps plate pixels to screen coordinates.
+	center := image.Pt(
+		int(float32(s.center.X)*ppmm/mjolnir.Millimeter),
+		int(float32(s.center.Y)*ppmm/mjolnir.Millimeter),
+	)
+	off := view.Min.Add(viewsz.Div(2)).Sub(center)
+	toScreen := func(mm image.Rectangle) image.Rectangle {
+		return image.Rect(
+			int(float32(mm.Min.X)*ppmm), int(float32(mm.Min.Y)*ppmm),
+			int(float32(mm.Max.X)*ppmm), int(float32(mm.Max.Y)*ppmm),
+		).Add(off)
+	}
+	ops.Begin()
+	op.Offset(ops, off)
+	op.ImageOp(ops, img)
+	for _, k := range s.Plate.Size.KeepOuts() {
+		fillRect(ops, toScreen(k), previewHoleColor)
+	}
+	safe := toScreen(s.Plate.Size.SafeArea())
+	edges := []image.Rectangle{
+		{Min: safe.Min, Max: image.Pt(safe.Max.X, safe.Min.Y+1)},
+		{Min: image.Pt(safe.Min.X, safe.Max.Y-1), Max: safe.Max},
+		{Min: safe.Min, Max: image.Pt(safe.Min.X+1, safe.Max.Y)},
+		{Min: image.Pt(safe.Max.X-1, safe.Min.Y), Max: safe.Max},
+	}
+	for _, e := range edges {
+		fillRect(ops, e, previewSafeColor)
+	}
+	plate := ops.End()
+	op.ClipOp(image.Rectangle(view)).Add(ops)
+	plate.Add(ops)
+
+	leadID := locale.PreviewSide
+	if s.order {
+		leadID = locale.PreviewOrder
+	}
+	leadTxt := ctx.Text(leadID,
+		"N", strconv.Itoa(s.Side+1),
+		"Total", strconv.Itoa(len(s.Plate.Sides)),
+		"Zoom", strconv.Itoa(1<<s.zoom))
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text, leadTxt)
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+
+	layoutNavigation(ctx, ops, th, dims,
+		NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+		NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconDot},
+	)
+	return ResultNone
+}
+
+// fillRect fills r with col.
+func fillRect(ops op.Ctx, r image.Rectangle, col color.NRGBA) {
+	op.ClipOp(r).Add(ops)
+	op.ColorOp(ops, col)
+}
@@ -0,0 +1,300 @@
+package gui
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+
+	"seedhammer.com/backup"
+	"seedhammer.com/bc/urtypes"
+	"seedhammer.com/bip39"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/gui/widget"
+)
+
+// SessionScreen engraves the shares of a multisig wallet in a
+// single session. The descriptor is loaded once, and the seed of
+// every remaining share is asked for in turn.
+type SessionScreen struct {
+	method    *ChoiceScreen
+	scanner   *ScanScreen
+	fragments *FragmentKeyboardScreen
+	desc      *urtypes.OutputDescriptor
+	seed      *SeedScreen
+	engrave   *EngraveScreen
+	warning   *ErrorScreen
+	cancel    *ConfirmWarningScreen
+	// shares are the engraved shares, indexed by key.
+	shares []*sessionShare
+	// keyIdx is the share being engraved.
+	keyIdx int
+	scroll int
+}
+
+type sessionShare struct {
+	Size     backup.PlateSize
+	Verified bool
+}
+
+func NewSessionScreen(ctx *Context) *SessionScreen {
+	return &SessionScreen{
+		method: &ChoiceScreen{
+			Title:   ctx.Text(locale.Descriptor),
+			Lead:    ctx.Text(locale.InputMethod),
+			Choices: []string{ctx.Text(locale.ChoiceScan), ctx.Text(locale.ChoiceKeyboard)},
+		},
+	}
+}
+
+// load the session descriptor.
+func (s *SessionScreen) load(ctx *Context, res any) *ErrorScreen {
+	desc, warning := parseDescriptor(ctx, res)
+	if warning != nil {
+		return warning
+	}
+	if len(desc.Keys) < 2 {
+		return &ErrorScreen{
+			Title: ctx.Text(locale.NotMultisig),
+			Body:  ctx.Text(locale.NotMultisigMsg),
+		}
+	}
+	if err := validateDescriptor(desc); err != nil {
+		return NewErrorScreen(ctx, err)
+	}
+	s.desc = &desc
+	s.shares = make([]*sessionShare, len(desc.Keys))
+	return nil
+}
+
+// remaining returns the number of shares not yet engraved.
+func (s *SessionScreen) remaining() int {
+	n := 0
+	for _, sh := range s.shares {
+		if sh == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// addSeed prepares the engraving of the share of m.
+func (s *SessionScreen) addSeed(ctx *Context, m bip39.Mnemonic) *ErrorScreen {
+	keyIdx, ok := descriptorKeyIdx(*s.desc, m, "")
+	if !ok {
+		return &ErrorScreen{
+			Title: ctx.Text(locale.UnknownWallet),
+			Body:  ctx.Text(locale.UnknownWalletMsg),
+		}
+	}
+	if s.shares[keyIdx] != nil {
+		return &ErrorScreen{
+			Title: ctx.Text(locale.ShareEngraved),
+			Body:  ctx.Text(locale.ShareEngravedMsg, "Fingerprint", fmt.Sprintf("%.8x", s.desc.Keys[keyIdx].MasterFingerprint)),
+		}
+	}
+	plate, err := engravePlate(*s.desc, keyIdx, m, ctx.Settings.PlateSize)
+	if err != nil {
+		return NewErrorScreen(ctx, err)
+	}
+	s.keyIdx = keyIdx
+	s.engrave = NewEngraveScreen(ctx, plate)
+	return nil
+}
+
+func (s *SessionScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) Result {
+	for {
+		switch {
+		case s.scanner != nil:
+			res, status := s.scanner.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return ResultNone
+			}
+			s.scanner = nil
+			if status == ResultComplete {
+				s.warning = s.load(ctx, res)
+			}
+			continue
+		case s.fragments != nil:
+			res, status := s.fragments.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return ResultNone
+			}
+			s.fragments = nil
+			if status == ResultComplete {
+				s.warning = s.load(ctx, res)
+			}
+			continue
+		case s.desc == nil && s.warning == nil:
+			choice, status := s.method.Layout(ctx, ops.Begin(), th, dims, true)
+			dialog := ops.End()
+			switch status {
+			case ResultNone:
+				dialog.Add(ops)
+				return ResultNone
+			case ResultCancelled:
+				return ResultCancelled
+			}
+			switch choice {
+			case 0:
+				s.scanner = &ScanScreen{
+					Title: ctx.Text(locale.Scan),
+					Lead:  ctx.Text(locale.OutputDescriptor),
+				}
+			case 1:
+				s.fragments = &FragmentKeyboardScreen{
+					Type: "crypto-output",
+				}
+			}
+			continue
+		case s.seed != nil:
+			m, status := s.seed.Layout(ctx, ops.Begin(), th, dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return ResultNone
+			}
+			s.seed = nil
+			if status == ResultComplete {
+				s.warning = s.addSeed(ctx, m)
+			}
+			continue
+		case s.engrave != nil:
+			status := s.engrave.Layout(ctx, ops.Begin(), dims)
+			dialog := ops.End()
+			if status == ResultNone {
+				dialog.Add(ops)
+				return ResultNone
+			}
+			if status == ResultComplete {
+				s.shares[s.keyIdx] = &sessionShare{
+					Size:     s.engrave.plate.Size,
+					Verified: s.engrave.verified,
+				}
+			}
+			s.engrave = nil
+			continue
+		case s.warning != nil:
+			if s.warning.Update(ctx) {
+				s.warning = nil
+				continue
+			}
+		case s.cancel != nil:
+			switch s.cancel.Update(ctx) {
+			case ConfirmYes:
+				return ResultCancelled
+			case ConfirmNo:
+				s.cancel = nil
+				continue
+			}
+		}
+		if s.desc == nil {
+			break
+		}
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if !e.Click {
+				break
+			}
+			if s.remaining() == 0 {
+				return ResultComplete
+			}
+			s.cancel = &ConfirmWarningScreen{
+				Title: ctx.Text(locale.EndSession),
+				Body:  ctx.Text(locale.EndSessionMsg, "N", strconv.Itoa(s.remaining())),
+				Icon:  assets.IconDiscard,
+			}
+		case Button3, Center:
+			if !e.Click {
+				break
+			}
+			if s.remaining() == 0 {
+				return ResultComplete
+			}
+			s.seed = NewEmptySeedScreen(ctx, ctx.Text(locale.InputSeed))
+			continue
+		case Up:
+			if e.Pressed && s.scroll > 0 {
+				s.scroll--
+			}
+		case Down:
+			if e.Pressed && s.scroll < len(s.shares)-1 {
+				s.scroll++
+			}
+		}
+	}
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.Session))
+	if s.desc != nil {
+		s.layoutSummary(ctx, ops, th, dims)
+	}
+
+	switch {
+	case s.warning != nil:
+		s.warning.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	case s.cancel != nil:
+		s.cancel.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	default:
+		icn := image.RGBA64Image(assets.IconRight)
+		if s.remaining() == 0 {
+			icn = assets.IconCheckmark
+		}
+		layoutNavigation(ctx, ops, th, dims,
+			NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+			NavButton{Button: Button3, Style: StylePrimary, Icon: icn},
+		)
+	}
+	return ResultNone
+}
+
+// layoutSummary lists the fingerprint, plate size and
+// verification status of every share.
+func (s *SessionScreen) layoutSummary(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) {
+	r := layout.Rectangle{Max: dims}
+	_, list := r.CutTop(leadingSize)
+	list, lead := list.CutBottom(leadingSize)
+	navw := assets.NavBtnPrimary.Bounds().Dx()
+	list = list.Shrink(0, navw, 0, navw)
+
+	n := len(s.shares)
+	leadTxt := ctx.Text(locale.SharesEngraved, "N", strconv.Itoa(n-s.remaining()), "Total", strconv.Itoa(n))
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text, leadTxt)
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+
+	style := ctx.Styles.body
+	content := list.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+	lineHeight := widget.Label(op.Ctx{}, style, th.Text, "0").Y
+	// Every share takes two lines.
+	y := content.Min.Y - s.scroll*2*lineHeight
+	{
+		ops := ops.Begin()
+		for i, sh := range s.shares {
+			status := ctx.Text(locale.NotEngraved)
+			if sh != nil {
+				status = ctx.Text(locale.ShareUnverified, "Plate", plateName(sh.Size))
+				if sh.Verified {
+					status = ctx.Text(locale.ShareVerified, "Plate", plateName(sh.Size))
+				}
+			}
+			widget.Label(ops.Begin(), style, th.Text, fmt.Sprintf("%d: %.8x", i+1, s.desc.Keys[i].MasterFingerprint))
+			op.Position(ops, ops.End(), image.Pt(content.Min.X, y))
+			widget.Label(ops.Begin(), style, th.Text, status)
+			op.Position(ops, ops.End(), image.Pt(content.Min.X+8, y+lineHeight))
+			y += 2 * lineHeight
+		}
+	}
+	fadeClip(ops, ops.End(), image.Rectangle(list))
+}
@@ -0,0 +1,369 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"strconv"
+
+	"seedhammer.com/auditlog"
+	"seedhammer.com/backup"
+	"seedhammer.com/gui/assets"
+	"seedhammer.com/gui/layout"
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/gui/widget"
+	"seedhammer.com/settings"
+)
+
+// SettingsScreen edits a copy of the device settings, and sets up
+// the audit log key.
+type SettingsScreen struct {
+	Settings settings.Settings
+	field    settingsField
+	scroll   int
+	// audit is the status of the audit key, as reported by
+	// Platform.CheckAuditKey.
+	audit        error
+	auditChecked bool
+	confirm      *ConfirmWarningScreen
+	result       *ErrorScreen
+}
+
+type settingsField int
+
+const (
+	fieldPlateSize settingsField = iota
+	fieldMoveSpeed
+	fieldPrintSpeed
+	fieldDryRun
+	fieldRotateCamera
+	fieldGuide
+	fieldLocale
+	fieldAddressGap
+	fieldAudit
+
+	numSettingsFields = int(fieldAudit) + 1
+)
+
+// speedSteps is the number of engraving speed steps in (0,1].
+// Step zero selects the engraver default.
+const speedSteps = 20
+
+// locales lists the supported user interface languages.
+var locales = locale.Locales()
+
+// addressGaps lists the selectable address search gaps.
+var addressGaps = []uint32{1000, 2000, 5000, settings.DefaultAddressGap, 20000, 50000, 100000, 200000, 500000, settings.MaxAddressGap}
+
+func NewSettingsScreen(s settings.Settings) *SettingsScreen {
+	return &SettingsScreen{Settings: s}
+}
+
+// label returns the short name of f.
+func (f settingsField) label() locale.ID {
+	switch f {
+	case fieldPlateSize:
+		return locale.SettingPlate
+	case fieldMoveSpeed:
+		return locale.SettingMove
+	case fieldPrintSpeed:
+		return locale.SettingPrint
+	case fieldDryRun:
+		return locale.SettingDryRun
+	case fieldRotateCamera:
+		return locale.SettingCamera
+	case fieldGuide:
+		return locale.SettingGuide
+	case fieldLocale:
+		return locale.SettingLanguage
+	case fieldAddressGap:
+		return locale.SettingGap
+	case fieldAudit:
+		return locale.SettingAudit
+	}
+	panic("invalid field")
+}
+
+// help returns the description of f.
+func (f settingsField) help() locale.ID {
+	switch f {
+	case fieldPlateSize:
+		return locale.HelpPlate
+	case fieldMoveSpeed:
+		return locale.HelpMove
+	case fieldPrintSpeed:
+		return locale.HelpPrint
+	case fieldDryRun:
+		return locale.HelpDryRun
+	case fieldRotateCamera:
+		return locale.HelpCamera
+	case fieldGuide:
+		return locale.HelpGuide
+	case fieldLocale:
+		return locale.HelpLanguage
+	case fieldAddressGap:
+		return locale.HelpGap
+	case fieldAudit:
+		return locale.HelpAudit
+	}
+	panic("invalid field")
+}
+
+// value returns the displayed value of f. The language is
+// named in its own locale.
+func (s *SettingsScreen) value(ctx *Context, f settingsField) string {
+	onOff := func(v bool) string {
+		if v {
+			return ctx.Text(locale.On)
+		}
+		return ctx.Text(locale.Off)
+	}
+	speed := func(v float32) string {
+		if v == 0 {
+			return ctx.Text(locale.DefaultSpeed)
+		}
+		return fmt.Sprintf("%d%%", int(math.Round(float64(v)*100)))
+	}
+	set := &s.Settings
+	switch f {
+	case fieldPlateSize:
+		return plateName(set.PlateSize) + "+"
+	case fieldMoveSpeed:
+		return speed(set.MoveSpeed)
+	case fieldPrintSpeed:
+		return speed(set.PrintSpeed)
+	case fieldDryRun:
+		return onOff(set.DryRun)
+	case fieldRotateCamera:
+		if set.RotateCamera {
+			return ctx.Text(locale.CameraRotated)
+		}
+		return ctx.Text(locale.CameraNormal)
+	case fieldGuide:
+		return onOff(!set.Calibrated)
+	case fieldLocale:
+		return locale.Text(set.Locale, locale.LanguageName)
+	case fieldAddressGap:
+		return strconv.FormatUint(uint64(set.Gap()), 10)
+	case fieldAudit:
+		switch {
+		case s.audit == nil:
+			return ctx.Text(locale.AuditKeyReady)
+		case errors.Is(s.audit, auditlog.ErrNoKey):
+			return ctx.Text(locale.AuditKeyMissing)
+		default:
+			return ctx.Text(locale.AuditKeyUnavailable)
+		}
+	}
+	panic("invalid field")
+}
+
+// adjust steps the value of f in direction dir.
+func (s *SettingsScreen) adjust(f settingsField, dir int) {
+	step := func(v, n int) int {
+		v += dir
+		if v < 0 {
+			v = 0
+		}
+		if v > n {
+			v = n
+		}
+		return v
+	}
+	speed := func(v float32) float32 {
+		i := int(math.Round(float64(v) * speedSteps))
+		return float32(step(i, speedSteps)) / speedSteps
+	}
+	set := &s.Settings
+	switch f {
+	case fieldPlateSize:
+		set.PlateSize = backup.PlateSize(step(int(set.PlateSize), int(backup.LargePlate)))
+	case fieldMoveSpeed:
+		set.MoveSpeed = speed(set.MoveSpeed)
+	case fieldPrintSpeed:
+		set.PrintSpeed = speed(set.PrintSpeed)
+	case fieldDryRun:
+		set.DryRun = !set.DryRun
+	case fieldRotateCamera:
+		set.RotateCamera = !set.RotateCamera
+	case fieldGuide:
+		set.Calibrated = !set.Calibrated
+	case fieldLocale:
+		idx := 0
+		for i, l := range locales {
+			if l == set.Locale {
+				idx = i
+			}
+		}
+		idx = (idx + dir + len(locales)) % len(locales)
+		set.Locale = locales[idx]
+	case fieldAddressGap:
+		idx := 0
+		for i, g := range addressGaps {
+			if g == set.Gap() {
+				idx = i
+			}
+		}
+		set.AddressGap = addressGaps[step(idx, len(addressGaps)-1)]
+	}
+}
+
+// setupAuditKey sets up the audit key and stores the pending
+// audit entries.
+func (s *SettingsScreen) setupAuditKey(ctx *Context) *ErrorScreen {
+	err := ctx.Platform.SetupAuditKey()
+	s.audit = ctx.Platform.CheckAuditKey()
+	if err != nil {
+		log.Printf("audit: %v", err)
+		return NewErrorScreen(ctx, err)
+	}
+	ctx.FlushAudit()
+	return &ErrorScreen{
+		Title: ctx.Text(locale.AuditKeySetUp),
+		Body:  ctx.Text(locale.AuditKeySetUpMsg),
+	}
+}
+
+func (s *SettingsScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) Result {
+	if !s.auditChecked {
+		s.audit = ctx.Platform.CheckAuditKey()
+		s.auditChecked = true
+	}
+	for {
+		switch {
+		case s.result != nil:
+			if s.result.Update(ctx) {
+				s.result = nil
+				continue
+			}
+		case s.confirm != nil:
+			switch s.confirm.Update(ctx) {
+			case ConfirmYes:
+				s.confirm = nil
+				s.result = s.setupAuditKey(ctx)
+				continue
+			case ConfirmNo:
+				s.confirm = nil
+				continue
+			}
+		}
+		e, ok := ctx.Next(Button1, Button3, Center, Up, Down, Left, Right, CCW, CW)
+		if !ok {
+			break
+		}
+		switch e.Button {
+		case Button1:
+			if e.Click {
+				return ResultCancelled
+			}
+		case Button3:
+			if e.Click {
+				return ResultComplete
+			}
+		case Center:
+			if !e.Click {
+				break
+			}
+			if s.field == fieldAudit {
+				// Setting up the key is irreversible.
+				if errors.Is(s.audit, auditlog.ErrNoKey) {
+					s.confirm = &ConfirmWarningScreen{
+						Title: ctx.Text(locale.SetupAuditKey),
+						Body:  ctx.Text(locale.SetupAuditKeyMsg),
+						Icon:  assets.IconCheckmark,
+					}
+				}
+				break
+			}
+			s.adjust(s.field, 1)
+		case Up:
+			if e.Pressed && s.field > 0 {
+				s.field--
+			}
+		case Down:
+			if e.Pressed && int(s.field) < numSettingsFields-1 {
+				s.field++
+			}
+		case Left, CCW:
+			if e.Pressed {
+				s.adjust(s.field, -1)
+			}
+		case Right, CW:
+			if e.Pressed {
+				s.adjust(s.field, 1)
+			}
+		}
+	}
+
+	op.ColorOp(ops, th.Background)
+	layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.Settings))
+
+	r := layout.Rectangle{Max: dims}
+	_, list := r.CutTop(leadingSize)
+	list, lead := list.CutBottom(leadingSize)
+	navw := assets.NavBtnPrimary.Bounds().Dx()
+	list = list.Shrink(0, navw, 0, navw)
+
+	leadsz := widget.LabelW(ops.Begin(), ctx.Styles.lead, dims.X-2*8, th.Text, ctx.Text(s.field.help()))
+	op.Position(ops, ops.End(), lead.Center(leadsz))
+
+	style := ctx.Styles.body
+	lineHeight := widget.Label(op.Ctx{}, style, th.Text, "0").Y
+	content := list.Shrink(scrollFadeDist, 0, scrollFadeDist, 0)
+	// Keep the selected field visible.
+	if visible := content.Dy() / lineHeight; visible > 0 {
+		if int(s.field) < s.scroll {
+			s.scroll = int(s.field)
+		}
+		if int(s.field) >= s.scroll+visible {
+			s.scroll = int(s.field) - visible + 1
+		}
+	}
+	y := content.Min.Y - s.scroll*lineHeight
+	width := content.Dx()
+	{
+		ops := ops.Begin()
+		for i := 0; i < numSettingsFields; i++ {
+			f := settingsField(i)
+			col := th.Text
+			if f == s.field {
+				bg := image.Rectangle{Max: image.Pt(width, lineHeight)}
+				op.MaskOp(ops.Begin(), assets.ButtonFocused.For(bg))
+				op.ColorOp(ops, th.Text)
+				op.Position(ops, ops.End(), image.Pt(content.Min.X, y))
+				col = th.Background
+			}
+			const pad = 4
+			widget.Label(ops.Begin(), style, col, ctx.Text(f.label()))
+			op.Position(ops, ops.End(), image.Pt(content.Min.X+pad, y))
+			vstyle := style
+			if f == fieldLocale {
+				// The current faces may lack the glyphs of the
+				// language name.
+				vstyle = NewStyles(s.Settings.Locale).body
+			}
+			vsz := widget.Label(ops.Begin(), vstyle, col, s.value(ctx, f))
+			op.Position(ops, ops.End(), image.Pt(content.Max.X-pad-vsz.X, y))
+			y += lineHeight
+		}
+	}
+	fadeClip(ops, ops.End(), image.Rectangle(list))
+
+	switch {
+	case s.result != nil:
+		s.result.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	case s.confirm != nil:
+		s.confirm.Layout(ctx, ops.Begin(), th, dims)
+		ops.End().Add(ops)
+	default:
+		layoutNavigation(ctx, ops, th, dims,
+			NavButton{Button: Button1, Style: StyleSecondary, Icon: assets.IconBack},
+			NavButton{Button: Button3, Style: StylePrimary, Icon: assets.IconCheckmark},
+		)
+	}
+	return ResultNone
+}
@@ -3,6 +3,7 @@ package gui
 import (
 	"image/color"
 
+	"seedhammer.com/font/bitmap"
 	"seedhammer.com/font/comfortaa"
 	"seedhammer.com/font/poppins"
 	"seedhammer.com/gui/text"
@@ -68,8 +69,18 @@ func init() {
 	theme.inactiveMask = 0x55
 }
 
-func NewStyles() Styles {
-	return Styles{
+// localeFaces lists the faces covering the glyphs of the locales
+// other than the default.
+var localeFaces = map[string]struct {
+	regular16, bold16, bold20, bold23 *bitmap.Face
+}{
+	"de": {poppins.Regular16DE, poppins.Bold16DE, poppins.Bold20DE, poppins.Bold23DE},
+	"es": {poppins.Regular16ES, poppins.Bold16ES, poppins.Bold20ES, poppins.Bold23ES},
+	"ja": {poppins.Regular16JA, poppins.Bold16JA, poppins.Bold20JA, poppins.Bold23JA},
+}
+
+func NewStyles(locale string) Styles {
+	st := Styles{
 		title: text.Style{
 			Face:          poppins.Bold23,
 			Alignment:     text.AlignCenter,
@@ -117,4 +128,15 @@ func NewStyles() Styles {
 			LetterSpacing: -1,
 		},
 	}
+	if f, ok := localeFaces[locale]; ok {
+		st.title.Face = f.bold23
+		st.body.Face = f.regular16
+		st.warning.Face = f.bold23
+		st.lead.Face = f.regular16
+		st.subtitle.Face = f.bold16
+		st.nav.Face = f.bold23
+		st.button.Face = f.bold20
+		st.keyboard.Face = f.bold16
+	}
+	return st
 }
@@ -0,0 +1,141 @@
+package gui
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"strconv"
+	"time"
+
+	"seedhammer.com/gui/locale"
+	"seedhammer.com/gui/op"
+	"seedhammer.com/seedqr"
+)
+
+// VerifyScreen scans the QR codes of an engraved plate and
+// compares them with the intended contents.
+type VerifyScreen struct {
+	Plate    Plate
+	scanner  *ScanScreen
+	deadline time.Time
+	// verified tracks the plate QR codes successfully scanned.
+	verified []bool
+	result   *ErrorScreen
+	passed   bool
+}
+
+// verifyTimeout is the duration of scanning before the plate
+// is considered unreadable.
+const verifyTimeout = 30 * time.Second
+
+// match returns the index of the plate QR code that matches the
+// scanned content, or -1. UR fragments must match exactly, but
+// SeedQRs need only encode the same seed.
+func (s *VerifyScreen) match(qr []byte) int {
+	uqr := bytes.ToUpper(qr)
+	m, isSeedQR := seedqr.Parse(qr)
+	for i, want := range s.Plate.QRs {
+		if bytes.HasPrefix(uqr, []byte("UR:")) {
+			if bytes.Equal(uqr, bytes.ToUpper(want)) {
+				return i
+			}
+			continue
+		}
+		if !isSeedQR {
+			continue
+		}
+		if wantm, ok := seedqr.Parse(want); ok && reflect.DeepEqual(m, wantm) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *VerifyScreen) check(ctx *Context, qr []byte) {
+	idx := s.match(qr)
+	if idx == -1 {
+		s.result = &ErrorScreen{
+			Title: ctx.Text(locale.VerificationFailed),
+			Body:  ctx.Text(locale.VerificationFailedMsg),
+		}
+		return
+	}
+	s.verified[idx] = true
+	left := 0
+	for _, v := range s.verified {
+		if !v {
+			left++
+		}
+	}
+	if left > 0 {
+		return
+	}
+	s.passed = true
+	s.result = &ErrorScreen{
+		Title: ctx.Text(locale.PlateVerified),
+		Body:  ctx.Text(locale.PlateVerifiedMsg),
+	}
+}
+
+func (s *VerifyScreen) lead(ctx *Context) string {
+	n := 0
+	for _, v := range s.verified {
+		if v {
+			n++
+		}
+	}
+	if len(s.verified) == 1 {
+		return ctx.Text(locale.EngravedQR)
+	}
+	return ctx.Text(locale.EngravedQROf, "N", strconv.Itoa(n+1), "Total", strconv.Itoa(len(s.verified)))
+}
+
+func (s *VerifyScreen) Layout(ctx *Context, ops op.Ctx, th *Colors, dims image.Point) Result {
+	if s.verified == nil {
+		s.verified = make([]bool, len(s.Plate.QRs))
+	}
+	for {
+		if s.result != nil {
+			if !s.result.Update(ctx) {
+				op.ColorOp(ops, th.Background)
+				layoutTitle(ctx, ops, dims.X, th.Text, ctx.Text(locale.Verify))
+				s.result.Layout(ctx, ops.Begin(), th, dims)
+				ops.End().Add(ops)
+				return ResultNone
+			}
+			s.result = nil
+			if s.passed {
+				return ResultComplete
+			}
+		}
+		if s.scanner == nil {
+			s.scanner = &ScanScreen{
+				Title: ctx.Text(locale.Verify),
+				Lead:  s.lead(ctx),
+				Raw:   true,
+			}
+			s.deadline = ctx.Platform.Now().Add(verifyTimeout)
+			ctx.WakeupAt(s.deadline)
+		}
+		res, status := s.scanner.Layout(ctx, ops.Begin(), dims)
+		dialog := ops.End()
+		switch status {
+		case ResultCancelled:
+			return ResultCancelled
+		case ResultComplete:
+			s.scanner = nil
+			s.check(ctx, res.([]byte))
+			continue
+		}
+		if !ctx.Platform.Now().Before(s.deadline) {
+			s.scanner = nil
+			s.result = &ErrorScreen{
+				Title: ctx.Text(locale.UnreadableQR),
+				Body:  ctx.Text(locale.UnreadableQRMsg),
+			}
+			continue
+		}
+		dialog.Add(ops)
+		return ResultNone
+	}
+}
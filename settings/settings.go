@@ -0,0 +1,249 @@
+// package settings implements the persistent device settings of
+// the controller. Settings are stored in a small versioned and
+// checksummed file, and must never contain secrets such as seeds
+// or descriptors.
+package settings
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+
+	"seedhammer.com/backup"
+)
+
+// Settings are the user preferences and device state that
+// survive a restart.
+type Settings struct {
+	// Calibrated is set once the first engraving has
+	// completed.
+	Calibrated bool
+	// RotateCamera rotates the camera feed 180 degrees.
+	RotateCamera bool
+	// PlateSize is the smallest plate size to engrave.
+	PlateSize backup.PlateSize
+	// MoveSpeed and PrintSpeed are the engraving speeds. Zero
+	// selects the engraver default, and values in (0,1] range
+	// from the slowest to the fastest speed.
+	MoveSpeed  float32
+	PrintSpeed float32
+	// DryRun enables dry runs by default.
+	DryRun bool
+	// Locale is the language of the user interface.
+	Locale string
+	// AddressGap is the number of receive and change
+	// addresses searched when verifying an address. Zero
+	// selects DefaultAddressGap.
+	AddressGap uint32
+}
+
+// Version is the version of the encoding. Version 1 lacks the
+// address gap.
+const Version = 2
+
+const (
+	// DefaultAddressGap is the address search gap of a new
+	// device.
+	DefaultAddressGap = 10000
+	// MaxAddressGap is the largest address search gap.
+	MaxAddressGap = 1000000
+)
+
+const (
+	magic = "SHST"
+	// headerSize is the size of the magic, version and
+	// payload length.
+	headerSize = len(magic) + 1 + 2
+	// maxLocale is the maximum length of a locale.
+	maxLocale = 16
+)
+
+const (
+	flagCalibrated = 1 << iota
+	flagRotateCamera
+	flagDryRun
+)
+
+var (
+	ErrCorrupt = errors.New("settings: corrupt data")
+	ErrVersion = errors.New("settings: unsupported version")
+)
+
+// Default returns the settings of a new device.
+func Default() Settings {
+	return Settings{Locale: "en"}
+}
+
+// Validate checks that the values of s are in range.
+func (s Settings) Validate() error {
+	switch s.PlateSize {
+	case backup.SmallPlate, backup.SquarePlate, backup.LargePlate:
+	default:
+		return fmt.Errorf("settings: invalid plate size: %d", s.PlateSize)
+	}
+	if !validSpeed(s.MoveSpeed) || !validSpeed(s.PrintSpeed) {
+		return fmt.Errorf("settings: speeds out of range: %g, %g", s.MoveSpeed, s.PrintSpeed)
+	}
+	if len(s.Locale) > maxLocale {
+		return fmt.Errorf("settings: locale too long: %q", s.Locale)
+	}
+	if s.AddressGap > MaxAddressGap {
+		return fmt.Errorf("settings: address gap too large: %d", s.AddressGap)
+	}
+	return nil
+}
+
+// Gap returns the address search gap, accounting for
+// the default.
+func (s Settings) Gap() uint32 {
+	if s.AddressGap == 0 {
+		return DefaultAddressGap
+	}
+	return s.AddressGap
+}
+
+func validSpeed(s float32) bool {
+	return s >= 0 && s <= 1
+}
+
+// Encode s into its binary form. It panics if s is not valid.
+func Encode(s Settings) []byte {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	var flags uint8
+	if s.Calibrated {
+		flags |= flagCalibrated
+	}
+	if s.RotateCamera {
+		flags |= flagRotateCamera
+	}
+	if s.DryRun {
+		flags |= flagDryRun
+	}
+	var payload []byte
+	payload = append(payload, flags, uint8(s.PlateSize))
+	payload = binary.BigEndian.AppendUint32(payload, math.Float32bits(s.MoveSpeed))
+	payload = binary.BigEndian.AppendUint32(payload, math.Float32bits(s.PrintSpeed))
+	payload = append(payload, uint8(len(s.Locale)))
+	payload = append(payload, s.Locale...)
+	payload = binary.BigEndian.AppendUint32(payload, s.AddressGap)
+
+	var b []byte
+	b = append(b, magic...)
+	b = append(b, Version)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(payload)))
+	b = append(b, payload...)
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+}
+
+// Decode the binary form of settings.
+func Decode(b []byte) (Settings, error) {
+	if len(b) < headerSize+4 || !bytes.HasPrefix(b, []byte(magic)) {
+		return Settings{}, ErrCorrupt
+	}
+	data, sum := b[:len(b)-4], b[len(b)-4:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(sum) {
+		return Settings{}, ErrCorrupt
+	}
+	v := data[len(magic)]
+	if v < 1 || v > Version {
+		return Settings{}, ErrVersion
+	}
+	payload := data[headerSize:]
+	if int(binary.BigEndian.Uint16(data[headerSize-2:])) != len(payload) {
+		return Settings{}, ErrCorrupt
+	}
+	// Flags, plate size, speeds and locale length.
+	const fixedSize = 1 + 1 + 4 + 4 + 1
+	if len(payload) < fixedSize {
+		return Settings{}, ErrCorrupt
+	}
+	flags := payload[0]
+	s := Settings{
+		Calibrated:   flags&flagCalibrated != 0,
+		RotateCamera: flags&flagRotateCamera != 0,
+		DryRun:       flags&flagDryRun != 0,
+		PlateSize:    backup.PlateSize(payload[1]),
+		MoveSpeed:    math.Float32frombits(binary.BigEndian.Uint32(payload[2:])),
+		PrintSpeed:   math.Float32frombits(binary.BigEndian.Uint32(payload[6:])),
+	}
+	locale := payload[fixedSize:]
+	if v >= 2 {
+		// Address gap.
+		n := len(locale) - 4
+		if n < 0 {
+			return Settings{}, ErrCorrupt
+		}
+		s.AddressGap = binary.BigEndian.Uint32(locale[n:])
+		locale = locale[:n]
+	}
+	if int(payload[fixedSize-1]) != len(locale) {
+		return Settings{}, ErrCorrupt
+	}
+	s.Locale = string(locale)
+	if err := s.Validate(); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+// Load reads the settings file at path. A missing file results
+// in the default settings.
+func Load(path string) (Settings, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Default(), nil
+		}
+		return Settings{}, fmt.Errorf("settings: %w", err)
+	}
+	return Decode(b)
+}
+
+// Store writes s to the settings file at path. The file is
+// replaced atomically, so an interrupted write leaves the
+// previous settings intact.
+func Store(path string, s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := writeSync(tmp, Encode(s)); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	// Persist the rename.
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	return nil
+}
+
+func writeSync(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
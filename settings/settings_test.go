@@ -0,0 +1,130 @@
+package settings
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"seedhammer.com/backup"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []Settings{
+		Default(),
+		{},
+		{
+			Calibrated:   true,
+			RotateCamera: true,
+			PlateSize:    backup.LargePlate,
+			MoveSpeed:    0.7,
+			PrintSpeed:   0.15,
+			DryRun:       true,
+			Locale:       "ja",
+			AddressGap:   50000,
+		},
+	}
+	for _, want := range tests {
+		got, err := Decode(Encode(want))
+		if err != nil {
+			t.Errorf("%+v: %v", want, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("round trip got %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	enc := Encode(Settings{Calibrated: true, Locale: "de"})
+	for i := range enc {
+		b := append([]byte(nil), enc...)
+		b[i] ^= 0x10
+		if _, err := Decode(b); err == nil {
+			t.Errorf("flipped bit in byte %d not detected", i)
+		}
+	}
+	for n := range enc {
+		if _, err := Decode(enc[:n]); err == nil {
+			t.Errorf("truncation to %d bytes not detected", n)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	b := Encode(Default())
+	data := b[:len(b)-4]
+	data[len(magic)] = Version + 1
+	b = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	if _, err := Decode(b); !errors.Is(err, ErrVersion) {
+		t.Errorf("decoding future version returned %v, want %v", err, ErrVersion)
+	}
+}
+
+func TestVersion1(t *testing.T) {
+	want := Settings{Calibrated: true, PlateSize: backup.SquarePlate, Locale: "de"}
+	b := Encode(want)
+	data := b[:len(b)-4]
+	// Strip the address gap.
+	data = data[:len(data)-4]
+	data[len(magic)] = 1
+	binary.BigEndian.PutUint16(data[headerSize-2:], uint16(len(data)-headerSize))
+	b = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	got, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("decoded version 1 settings %+v, want %+v", got, want)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	invalid := []Settings{
+		{PlateSize: backup.LargePlate + 1},
+		{MoveSpeed: -0.1},
+		{PrintSpeed: 1.5},
+		{Locale: "a-very-long-locale-name"},
+		{AddressGap: MaxAddressGap + 1},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%+v validated", s)
+		}
+		if err := Store(filepath.Join(t.TempDir(), "settings"), s); err == nil {
+			t.Errorf("%+v stored", s)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != Default() {
+		t.Errorf("missing file loaded %+v, want defaults", s)
+	}
+	s.Calibrated = true
+	s.PlateSize = backup.SquarePlate
+	if err := Store(path, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != s {
+		t.Errorf("loaded %+v, want %+v", got, s)
+	}
+	if err := os.WriteFile(path, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("loading garbage returned %v, want %v", err, ErrCorrupt)
+	}
+}